@@ -5,10 +5,13 @@ import (
 	"band-manager-backend/internal/db"
 	"band-manager-backend/internal/handlers" // Nowy import
 	"band-manager-backend/internal/services"
+	"band-manager-backend/internal/usecases"
+	"context"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	_ "time/tzdata" // Event time zones must not depend on the host's zoneinfo
 )
 
 // enableCORS adds CORS headers to all HTTP responses.
@@ -41,6 +44,35 @@ func enableCORS(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+// requireAuth rejects requests without a valid bearer token of an active
+// session and stores the authenticated user's and session's IDs in the request context.
+func requireAuth(authHandler *handlers.AuthHandler, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, sessionID, err := authHandler.Authenticate(r)
+		if err != nil {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		ctx := handlers.WithUserID(r.Context(), userID)
+		ctx = handlers.WithSessionID(ctx, sessionID)
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// requireAdmin rejects authenticated requests of users who are not system administrators.
+func requireAdmin(adminHandler *handlers.AdminHandler, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := handlers.UserIDFromContext(r.Context())
+		if !ok || !adminHandler.IsAdmin(userID) {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
 // main initializes the application, sets up services,
 // configures HTTP routes, and starts the server.
 func main() {
@@ -63,13 +95,57 @@ func main() {
 
 	db.InitDB()
 
-	authHandler := handlers.NewAuthHandler()
-	groupHandler := handlers.NewGroupHandler()
+	tokenService := services.NewTokenService(cfg)
+
+	storage, err := services.NewStorage(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize file storage: %v", err)
+	}
+
+	jobWorker := usecases.NewJobWorker(gcService, emailService, cfg.JobConfig.Workers, cfg.JobConfig.PollInterval)
+	jobWorker.Start(context.Background())
+	usecases.NewReminderScheduler().Start(context.Background())
+
+	// Files left unreferenced, e.g. by replaced notesheets or deleted tracks,
+	// are removed in the background on startup.
+	go func() {
+		report, err := usecases.NewBlobStore(storage).CollectGarbage(false)
+		if err != nil {
+			log.Printf("Warning: Failed to clean up file storage: %v", err)
+			return
+		}
+		log.Printf("Deleted %d unreferenced files (%d bytes) from file storage", len(report.Files), report.TotalBytes)
+	}()
+
+	authHandler := handlers.NewAuthHandler(tokenService, cfg.AuthConfig.TrustedProxies)
+	groupHandler := handlers.NewGroupHandler(cfg.UploadConfig)
 	subgroupHandler := handlers.NewSubgroupHandler()
-	trackHandler := handlers.NewTrackHandler()
-	eventHandler := handlers.NewEventHandler(gcService, emailService)
+	roleHandler := handlers.NewRoleHandler()
+	instrumentHandler := handlers.NewInstrumentHandler()
+	invitationHandler := handlers.NewInvitationHandler()
+	joinRequestHandler := handlers.NewJoinRequestHandler()
+	trackHandler := handlers.NewTrackHandler(storage, cfg.UploadConfig)
+	eventHandler := handlers.NewEventHandler(gcService)
+	setlistHandler := handlers.NewSetlistHandler()
+	calendarFeedHandler := handlers.NewCalendarFeedHandler()
+	googleCalendarHandler := handlers.NewGoogleCalendarHandler(gcService)
 	announcementHandler := handlers.NewAnnouncementHandler()
-	adminHandler := handlers.NewAdminHandler()
+	adminHandler := handlers.NewAdminHandler(storage)
+	userHandler := handlers.NewUserHandler()
+
+	if err := adminHandler.BootstrapAdmin(os.Getenv("ADMIN_EMAIL"), os.Getenv("ADMIN_PASSWORD")); err != nil {
+		log.Printf("Warning: Failed to bootstrap administrator: %v", err)
+	}
+
+	// protected wraps a handler with CORS headers and bearer token authentication.
+	protected := func(next http.HandlerFunc) http.HandlerFunc {
+		return enableCORS(requireAuth(authHandler, next))
+	}
+
+	// adminOnly additionally restricts a protected handler to system administrators.
+	adminOnly := func(next http.HandlerFunc) http.HandlerFunc {
+		return protected(requireAdmin(adminHandler, next))
+	}
 
 	http.HandleFunc("/", enableCORS(func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintf(w, "Hello World!")
@@ -78,96 +154,242 @@ func main() {
 	// POST /api/verify/login - Authenticates user and returns session data
 	http.HandleFunc("/api/verify/login", enableCORS(authHandler.Login))
 	// POST /api/verify/register - Creates new user account
+	// POST /api/verify/refresh - Exchanges refresh token for a new token pair
+	// POST /api/verify/forgot-password - Sends password reset link
+	// POST /api/verify/reset-password - Sets new password using reset token
+	// POST /api/verify/email - Confirms email address using verification token
+	// POST /api/verify/resend-verification - Sends new verification link
 	http.HandleFunc("/api/verify/register", enableCORS(authHandler.Register))
+	http.HandleFunc("/api/verify/refresh", enableCORS(authHandler.Refresh))
+	http.HandleFunc("/api/verify/forgot-password", enableCORS(authHandler.ForgotPassword))
+	http.HandleFunc("/api/verify/reset-password", enableCORS(authHandler.ResetPassword))
+	http.HandleFunc("/api/verify/email", enableCORS(authHandler.VerifyEmail))
+	http.HandleFunc("/api/verify/resend-verification", enableCORS(authHandler.ResendVerification))
+
+	// Session management endpoints
+	// GET /api/session/list - Lists user's active sessions
+	// POST /api/session/logout - Revokes current session
+	// POST /api/session/revoke-others - Revokes all sessions except current one
+	// DELETE /api/session/revoke/{sessionId} - Revokes one of user's sessions
+	http.HandleFunc("/api/session/list", protected(authHandler.ListSessions))
+	http.HandleFunc("/api/session/logout", protected(authHandler.Logout))
+	http.HandleFunc("/api/session/revoke-others", protected(authHandler.RevokeOtherSessions))
+	http.HandleFunc("/api/session/revoke/", protected(authHandler.RevokeSession))
+
+	// User preference endpoints
+	// GET /api/user/preferences - Gets user's preferences (event reminders)
+	// PUT /api/user/preferences - Updates user's preferences
+	http.HandleFunc("/api/user/preferences", protected(userHandler.Preferences))
 
 	// Group management endpoints
 	// POST /api/group/create - Creates new band group
 	// POST /api/group/join - Joins existing group using access token
-	// GET /api/group/{groupId}/{userId} - Gets group details
-	// GET /api/group/user/{userId} - Gets user's groups
-	// GET /api/group/members/{groupId}/{userId} - Gets group members
-	// DELETE /api/group/remove/{groupId}/{userId}/{requesterId} - Removes member from group
-	// PUT /api/group/role/{groupId}/{userId}/{requesterId} - Updates member's role
-	http.HandleFunc("/api/group/create", enableCORS(groupHandler.Create))
-	http.HandleFunc("/api/group/join", enableCORS(groupHandler.Join))
-	http.HandleFunc("/api/group/", enableCORS(groupHandler.GetGroupInfo))
-	http.HandleFunc("/api/group/user/", enableCORS(groupHandler.GetUserGroups))
-	http.HandleFunc("/api/group/members/", enableCORS(groupHandler.GetGroupMembers))
-	http.HandleFunc("/api/group/remove/", enableCORS(groupHandler.RemoveMember))
-	http.HandleFunc("/api/group/role/", enableCORS(groupHandler.UpdateMemberRole))
+	// GET /api/group/{groupId} - Gets group details
+	// GET /api/group/user - Gets user's groups
+	// GET /api/group/members/{groupId} - Gets group members
+	// DELETE /api/group/remove/{groupId}/{userId} - Removes member from group
+	// PUT /api/group/role/{groupId}/{userId} - Updates member's role
+	// POST /api/group/transfer/{groupId}/{userId} - Transfers ownership to another member
+	// POST /api/group/leave/{groupId} - Leaves group
+	// PUT /api/group/settings/{groupId} - Updates group settings (join approval, time zone, reminders)
+	http.HandleFunc("/api/group/create", protected(groupHandler.Create))
+	http.HandleFunc("/api/group/join", protected(groupHandler.Join))
+	http.HandleFunc("/api/group/", protected(groupHandler.GetGroupInfo))
+	http.HandleFunc("/api/group/user", protected(groupHandler.GetUserGroups))
+	http.HandleFunc("/api/group/members/", protected(groupHandler.GetGroupMembers))
+	http.HandleFunc("/api/group/remove/", protected(groupHandler.RemoveMember))
+	http.HandleFunc("/api/group/role/", protected(groupHandler.UpdateMemberRole))
+	http.HandleFunc("/api/group/transfer/", protected(groupHandler.TransferOwnership))
+	http.HandleFunc("/api/group/leave/", protected(groupHandler.Leave))
+	http.HandleFunc("/api/group/settings/", protected(groupHandler.UpdateSettings))
 
 	// Subgroup management endpoints
 	// POST /api/subgroup/create - Creates new subgroup
-	// GET /api/subgroup/info/{subgroupId}/{userId} - Gets subgroup details
-	// PUT /api/subgroup/update/{subgroupId}/{userId} - Updates subgroup
-	// DELETE /api/subgroup/delete/{subgroupId}/{userId} - Deletes subgroup
-	// POST /api/subgroup/members/add/{subgroupId}/{userId} - Adds members to subgroup
-	// DELETE /api/subgroup/members/remove/{subgroupId}/{memberId}/{requesterId} - Removes member
-	// GET /api/subgroup/group/{groupId}/{userId} - Gets all subgroups in group
-	http.HandleFunc("/api/subgroup/create", enableCORS(subgroupHandler.Create))
-	http.HandleFunc("/api/subgroup/info/", enableCORS(subgroupHandler.GetInfo))
-	http.HandleFunc("/api/subgroup/update/", enableCORS(subgroupHandler.Update))
-	http.HandleFunc("/api/subgroup/delete/", enableCORS(subgroupHandler.Delete))
-	http.HandleFunc("/api/subgroup/members/add/", enableCORS(subgroupHandler.AddMembers))
-	http.HandleFunc("/api/subgroup/members/remove/", enableCORS(subgroupHandler.RemoveMember))
-	http.HandleFunc("/api/subgroup/group/", enableCORS(subgroupHandler.GetGroupSubgroups))
+	// GET /api/subgroup/info/{subgroupId} - Gets subgroup details
+	// PUT /api/subgroup/update/{subgroupId} - Updates subgroup
+	// DELETE /api/subgroup/delete/{subgroupId} - Deletes subgroup
+	// POST /api/subgroup/members/add/{subgroupId} - Adds members to subgroup
+	// DELETE /api/subgroup/members/remove/{subgroupId}/{memberId} - Removes member
+	// PUT /api/subgroup/members/role/{subgroupId}/{memberId} - Sets member's subgroup role (leader/member)
+	// GET /api/subgroup/group/{groupId} - Gets all subgroups in group
+	http.HandleFunc("/api/subgroup/create", protected(subgroupHandler.Create))
+	http.HandleFunc("/api/subgroup/info/", protected(subgroupHandler.GetInfo))
+	http.HandleFunc("/api/subgroup/update/", protected(subgroupHandler.Update))
+	http.HandleFunc("/api/subgroup/delete/", protected(subgroupHandler.Delete))
+	http.HandleFunc("/api/subgroup/members/add/", protected(subgroupHandler.AddMembers))
+	http.HandleFunc("/api/subgroup/members/remove/", protected(subgroupHandler.RemoveMember))
+	http.HandleFunc("/api/subgroup/members/role/", protected(subgroupHandler.UpdateMemberRole))
+	http.HandleFunc("/api/subgroup/group/", protected(subgroupHandler.GetGroupSubgroups))
 
 	// Track and notesheet management endpoints
 	// POST /api/track/create - Creates new track
 	// POST /api/track/notesheet - Adds notesheet to track
-	// GET /api/track/user/notesheets/{trackId}/{userId} - Gets user's notesheets
-	// GET /api/track/group/{groupId}/{userId} - Gets group's tracks
-	// GET /api/track/notesheets/{trackId}/{userId} - Gets track's notesheets
-	// POST /api/track/notesheet/upload/{notesheetId}/{userId} - Uploads notesheet file
-	// GET /api/track/notesheet/file/{notesheetId}/{userId} - Downloads notesheet file
+	// GET /api/track/user/notesheets/{trackId} - Gets notesheets for user's subgroups and instruments
+	// GET /api/track/group/{groupId} - Gets group's tracks
+	// GET /api/track/notesheets/{trackId} - Gets track's notesheets
+	// POST /api/track/notesheet/upload/{notesheetId} - Uploads new version of notesheet file
+	// GET /api/track/notesheet/file/{notesheetId} - Downloads notesheet file
+	// GET /api/track/notesheet/versions/{notesheetId} - Lists versions of notesheet file
+	// GET /api/track/notesheet/version/file/{versionId} - Downloads specific version of notesheet file
+	// POST /api/track/notesheet/version/restore/{versionId} - Makes version of notesheet file current again
 	// POST /api/track/notesheet/create - Creates notesheet with file
-	// DELETE /api/track/delete/{trackId}/{userId} - Deletes track
-	http.HandleFunc("/api/track/create", enableCORS(trackHandler.Create))
-	http.HandleFunc("/api/track/notesheet", enableCORS(trackHandler.AddNotesheet))
-	http.HandleFunc("/api/track/user/notesheets/", enableCORS(trackHandler.GetUserNotesheets))
-	http.HandleFunc("/api/track/group/", enableCORS(trackHandler.GetGroupTracks))
-	http.HandleFunc("/api/track/notesheets/", enableCORS(trackHandler.GetTrackNotesheets))
-	http.HandleFunc("/api/track/notesheet/upload/", enableCORS(trackHandler.UploadNotesheetFile))
-	http.HandleFunc("/api/track/notesheet/file/", enableCORS(trackHandler.DownloadNotesheetFile))
-	http.HandleFunc("/api/track/notesheet/create/", enableCORS(trackHandler.CreateNotesheetWithFile))
-	http.HandleFunc("/api/track/delete/", trackHandler.DeleteTrack)
-	http.HandleFunc("/api/group/refresh-token/", enableCORS(groupHandler.RefreshAccessToken))
+	// PUT /api/track/notesheet/instrument/{notesheetId} - Changes instrument notesheet is a part for
+	// DELETE /api/track/delete/{trackId} - Deletes track
+	http.HandleFunc("/api/track/create", protected(trackHandler.Create))
+	http.HandleFunc("/api/track/notesheet", protected(trackHandler.AddNotesheet))
+	http.HandleFunc("/api/track/user/notesheets/", protected(trackHandler.GetUserNotesheets))
+	http.HandleFunc("/api/track/group/", protected(trackHandler.GetGroupTracks))
+	http.HandleFunc("/api/track/notesheets/", protected(trackHandler.GetTrackNotesheets))
+	http.HandleFunc("/api/track/notesheet/upload/", protected(trackHandler.UploadNotesheetFile))
+	http.HandleFunc("/api/track/notesheet/file/", protected(trackHandler.DownloadNotesheetFile))
+	http.HandleFunc("/api/track/notesheet/versions/", protected(trackHandler.GetNotesheetVersions))
+	http.HandleFunc("/api/track/notesheet/version/file/", protected(trackHandler.DownloadNotesheetVersion))
+	http.HandleFunc("/api/track/notesheet/version/restore/", protected(trackHandler.RestoreNotesheetVersion))
+	http.HandleFunc("/api/track/notesheet/create/", protected(trackHandler.CreateNotesheetWithFile))
+	http.HandleFunc("/api/track/notesheet/instrument/", protected(trackHandler.SetNotesheetInstrument))
+	http.HandleFunc("/api/track/delete/", protected(trackHandler.DeleteTrack))
+	http.HandleFunc("/api/group/refresh-token/", protected(groupHandler.RefreshAccessToken))
+
+	// Role management endpoints
+	// GET /api/role/group/{groupId} - Gets roles and permissions of a group
+	// POST /api/role/create/{groupId} - Creates custom role
+	// PUT /api/role/update/{roleId} - Updates custom role
+	// DELETE /api/role/delete/{roleId} - Deletes custom role
+	http.HandleFunc("/api/role/group/", protected(roleHandler.GetGroupRoles))
+	http.HandleFunc("/api/role/create/", protected(roleHandler.Create))
+	http.HandleFunc("/api/role/update/", protected(roleHandler.Update))
+	http.HandleFunc("/api/role/delete/", protected(roleHandler.Delete))
+
+	// Instrument catalogue endpoints
+	// GET /api/instrument/group/{groupId} - Gets group's instrument catalogue with players
+	// POST /api/instrument/create/{groupId} - Adds instrument (name, family, transposition, clef) to catalogue
+	// PUT /api/instrument/update/{instrumentId} - Updates instrument
+	// DELETE /api/instrument/delete/{instrumentId} - Removes instrument from catalogue
+	// GET /api/instrument/mine/{groupId} - Gets instruments user plays in group
+	// PUT /api/instrument/mine/{groupId} - Sets instruments user plays in group
+	http.HandleFunc("/api/instrument/group/", protected(instrumentHandler.GetGroupInstruments))
+	http.HandleFunc("/api/instrument/create/", protected(instrumentHandler.Create))
+	http.HandleFunc("/api/instrument/update/", protected(instrumentHandler.Update))
+	http.HandleFunc("/api/instrument/delete/", protected(instrumentHandler.Delete))
+	http.HandleFunc("/api/instrument/mine/", protected(instrumentHandler.Mine))
+
+	// Invitation endpoints
+	// POST /api/invitation/create - Invites an email address to a group
+	// GET /api/invitation/group/{groupId} - Gets group's pending invitations
+	// DELETE /api/invitation/revoke/{invitationId} - Revokes invitation
+	// GET /api/invitation/info?token={token} - Gets invitation details (public)
+	// POST /api/invitation/accept - Accepts invitation
+	http.HandleFunc("/api/invitation/create", protected(invitationHandler.Create))
+	http.HandleFunc("/api/invitation/group/", protected(invitationHandler.GetGroupInvitations))
+	http.HandleFunc("/api/invitation/revoke/", protected(invitationHandler.Revoke))
+	http.HandleFunc("/api/invitation/info", enableCORS(invitationHandler.GetInfo))
+	http.HandleFunc("/api/invitation/accept", protected(invitationHandler.Accept))
+
+	// Join request endpoints
+	// GET /api/join-request/group/{groupId} - Gets group's pending join requests
+	// POST /api/join-request/approve/{requestId} - Approves join request with role and subgroups
+	// POST /api/join-request/reject/{requestId} - Rejects join request
+	http.HandleFunc("/api/join-request/group/", protected(joinRequestHandler.GetGroupRequests))
+	http.HandleFunc("/api/join-request/approve/", protected(joinRequestHandler.Approve))
+	http.HandleFunc("/api/join-request/reject/", protected(joinRequestHandler.Reject))
 
 	// Event management endpoints
-	// POST /api/event/create - Creates new event
-	// GET /api/event/info/{eventId}/{userId} - Gets event details
-	// PUT /api/event/update/{eventId}/{userId} - Updates event
-	// DELETE /api/event/delete/{eventId}/{userId} - Deletes event
-	// GET /api/event/group/{groupId}/{userId} - Gets group's events
-	// GET /api/event/user/{userId} - Gets user's events
-	http.HandleFunc("/api/event/create", enableCORS(eventHandler.Create))
-	http.HandleFunc("/api/event/info/", enableCORS(eventHandler.GetInfo))
-	http.HandleFunc("/api/event/update/", enableCORS(eventHandler.Update))
-	http.HandleFunc("/api/event/delete/", enableCORS(eventHandler.Delete))
-	http.HandleFunc("/api/event/group/", enableCORS(eventHandler.GetGroupEvents))
-	http.HandleFunc("/api/event/user/", enableCORS(eventHandler.GetUserEvents))
+	// POST /api/event/create - Creates new event (409 with conflicts unless forced)
+	// GET /api/event/info/{eventId} - Gets event details with setlist
+	// GET /api/event/check-slot?group_id=&date=&end_date=&user_ids= - Lists members' conflicts at a proposed time
+	// PUT /api/event/update/{eventId} - Updates event (scope/occurrence_date for recurring series)
+	// DELETE /api/event/delete/{eventId}?scope=&occurrence= - Deletes event or occurrences
+	// GET /api/event/group/{groupId}?from=&to=&type= - Gets group's events, expanding recurring ones
+	// GET /api/event/user?from=&to=&type= - Gets user's events, expanding recurring ones
+	// POST /api/event/respond/{eventId} - Responds to event (yes/no/maybe)
+	// POST /api/event/respond-link - Responds to event using a link from an event email (public)
+	// GET /api/event/attendance/{eventId} - Gets attendance roll-up per subgroup
+	// GET /api/event/export/{eventId}?layout=stage|programme - Downloads stage setlist or programme as PDF
+	// PUT /api/event/subgroup-note/{eventId} - Sets or clears a subgroup's notes for the event
+	// PUT /api/event/reminders/{eventId} - Sets reminder times of the event (null uses the group's)
+	http.HandleFunc("/api/event/create", protected(eventHandler.Create))
+	http.HandleFunc("/api/event/info/", protected(eventHandler.GetInfo))
+	http.HandleFunc("/api/event/check-slot", protected(eventHandler.CheckSlot))
+	http.HandleFunc("/api/event/update/", protected(eventHandler.Update))
+	http.HandleFunc("/api/event/delete/", protected(eventHandler.Delete))
+	http.HandleFunc("/api/event/group/", protected(eventHandler.GetGroupEvents))
+	http.HandleFunc("/api/event/user", protected(eventHandler.GetUserEvents))
+	http.HandleFunc("/api/event/respond/", protected(eventHandler.Respond))
+	http.HandleFunc("/api/event/respond-link", enableCORS(eventHandler.RespondWithLink))
+	http.HandleFunc("/api/event/attendance/", protected(eventHandler.GetAttendance))
+	http.HandleFunc("/api/event/export/", protected(eventHandler.Export))
+	http.HandleFunc("/api/event/subgroup-note/", protected(eventHandler.SetSubgroupNote))
+	http.HandleFunc("/api/event/reminders/", protected(eventHandler.SetReminders))
+
+	// Setlist endpoints
+	// GET /api/setlist/{eventId} - Gets event's setlist with planned start times and running time
+	// POST /api/setlist/add/{eventId} - Adds track or break at position (or at the end)
+	// PUT /api/setlist/update/{entryId} - Updates setlist entry
+	// DELETE /api/setlist/delete/{entryId} - Removes setlist entry
+	// PUT /api/setlist/move/{entryId} - Moves setlist entry to position
+	// PUT /api/setlist/reorder/{eventId} - Reorders whole setlist
+	// GET /api/setlist/export/{eventId}?format=txt|csv - Downloads setlist
+	http.HandleFunc("/api/setlist/", protected(setlistHandler.GetSetlist))
+	http.HandleFunc("/api/setlist/add/", protected(setlistHandler.AddEntry))
+	http.HandleFunc("/api/setlist/update/", protected(setlistHandler.UpdateEntry))
+	http.HandleFunc("/api/setlist/delete/", protected(setlistHandler.DeleteEntry))
+	http.HandleFunc("/api/setlist/move/", protected(setlistHandler.MoveEntry))
+	http.HandleFunc("/api/setlist/reorder/", protected(setlistHandler.Reorder))
+	http.HandleFunc("/api/setlist/export/", protected(setlistHandler.Export))
+
+	// Calendar feed endpoints
+	// POST /api/calendar-feed/create - Creates iCalendar feed of user's or group's events
+	// GET /api/calendar-feed/list - Gets user's active feeds
+	// DELETE /api/calendar-feed/revoke/{feedId} - Revokes feed
+	// GET /api/calendar-feed/ics/{token}.ics - Serves feed to calendar apps (token-protected)
+	http.HandleFunc("/api/calendar-feed/create", protected(calendarFeedHandler.Create))
+	http.HandleFunc("/api/calendar-feed/list", protected(calendarFeedHandler.GetFeeds))
+	http.HandleFunc("/api/calendar-feed/revoke/", protected(calendarFeedHandler.Revoke))
+	http.HandleFunc("/api/calendar-feed/ics/", enableCORS(calendarFeedHandler.Serve))
 
 	// Announcement management endpoints
 	// POST /api/announcement/create - Creates new announcement
-	// DELETE /api/announcement/delete/{announcementId}/{userId} - Deletes announcement
-	// GET /api/announcement/user/{userId} - Gets user's announcements
-	// GET /api/announcement/group/{groupId}/{userId} - Gets group's announcements
-	http.HandleFunc("/api/announcement/create", enableCORS(announcementHandler.Create))
-	http.HandleFunc("/api/announcement/delete/", enableCORS(announcementHandler.Delete))
-	http.HandleFunc("/api/announcement/user/", enableCORS(announcementHandler.GetUserAnnouncements))
-	http.HandleFunc("/api/announcement/group/", enableCORS(announcementHandler.GetGroupAnnouncements))
+	// DELETE /api/announcement/delete/{announcementId} - Deletes announcement
+	// GET /api/announcement/user - Gets user's announcements
+	// GET /api/announcement/group/{groupId} - Gets group's announcements
+	http.HandleFunc("/api/announcement/create", protected(announcementHandler.Create))
+	http.HandleFunc("/api/announcement/delete/", protected(announcementHandler.Delete))
+	http.HandleFunc("/api/announcement/user", protected(announcementHandler.GetUserAnnouncements))
+	http.HandleFunc("/api/announcement/group/", protected(announcementHandler.GetGroupAnnouncements))
 
 	// Admin endpoints
-	// PUT /api/admin/users/reset-password/{userId} - Resets user password
+	// GET /api/admin/users - Lists and searches users
+	// POST /api/admin/users/reset-password/{userId} - Resets user password
+	// POST /api/admin/users/revoke-sessions/{userId} - Revokes all sessions of a user
+	// PUT /api/admin/users/disable/{userId} - Disables or enables a user
+	// PUT /api/admin/users/admin/{userId} - Grants or removes administrator rights
+	// DELETE /api/admin/users/delete/{userId} - Deletes a user
 	// GET /api/admin/stats - Gets system statistics
-	http.HandleFunc("/api/admin/users/reset-password/", enableCORS(adminHandler.ResetUserPassword))
-	http.HandleFunc("/api/admin/stats", enableCORS(adminHandler.GetSystemStats))
+	// GET /api/admin/jobs - Lists background jobs, filtered by status and type
+	// GET /api/admin/jobs/{jobId} - Gets background job with its last error
+	// POST /api/admin/jobs/retry/{jobId} - Retries dead background job
+	// POST /api/admin/storage/cleanup - Deletes unreferenced stored files (dry_run=true only reports them)
+	http.HandleFunc("/api/admin/users", adminOnly(adminHandler.ListUsers))
+	http.HandleFunc("/api/admin/users/reset-password/", adminOnly(adminHandler.ResetUserPassword))
+	http.HandleFunc("/api/admin/users/revoke-sessions/", adminOnly(adminHandler.RevokeUserSessions))
+	http.HandleFunc("/api/admin/users/disable/", adminOnly(adminHandler.SetUserDisabled))
+	http.HandleFunc("/api/admin/users/admin/", adminOnly(adminHandler.SetUserAdmin))
+	http.HandleFunc("/api/admin/users/delete/", adminOnly(adminHandler.DeleteUser))
+	http.HandleFunc("/api/admin/stats", adminOnly(adminHandler.GetSystemStats))
+	http.HandleFunc("/api/admin/jobs", adminOnly(adminHandler.ListJobs))
+	http.HandleFunc("/api/admin/jobs/", adminOnly(adminHandler.GetJob))
+	http.HandleFunc("/api/admin/jobs/retry/", adminOnly(adminHandler.RetryJob))
+	http.HandleFunc("/api/admin/storage/cleanup", adminOnly(adminHandler.CleanupStorage))
 
 	// Google Calendar integration endpoints
-	// GET /api/calendar/auth - Initiates OAuth flow
-	// GET /api/calendar/callback - Handles OAuth callback
-	http.HandleFunc("/api/calendar/auth", enableCORS(eventHandler.GoogleCalendarAuth))
-	http.HandleFunc("/api/calendar/callback", enableCORS(eventHandler.GoogleCalendarCallback))
+	// GET /api/calendar/auth - Returns OAuth consent URL for linking user's calendar
+	// GET /api/calendar/callback - Handles OAuth callback (identified by state)
+	// GET /api/calendar/status - Reports whether user's calendar is linked
+	// DELETE /api/calendar/unlink - Unlinks user's calendar and removes synced events
+	http.HandleFunc("/api/calendar/auth", protected(googleCalendarHandler.Auth))
+	http.HandleFunc("/api/calendar/callback", enableCORS(googleCalendarHandler.Callback))
+	http.HandleFunc("/api/calendar/status", protected(googleCalendarHandler.Status))
+	http.HandleFunc("/api/calendar/unlink", protected(googleCalendarHandler.Unlink))
 	fmt.Printf("Server starting on http://localhost:%s\n", port)
 	if err := http.ListenAndServe(":"+port, nil); err != nil {
 		log.Fatal(err)
@@ -1,28 +1,171 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"net"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
 	GoogleCalendarConfig *GoogleCalendarConfig
+	AuthConfig           *AuthConfig
+	JobConfig            *JobConfig
+	StorageConfig        *StorageConfig
+	UploadConfig         *UploadConfig
 }
 
 type GoogleCalendarConfig struct {
 	CredentialsFile string
 }
 
+type AuthConfig struct {
+	JWTSecret       string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	// TrustedProxies are the networks of reverse proxies whose
+	// X-Forwarded-For headers tell the address of clients.
+	TrustedProxies []*net.IPNet
+}
+
+type JobConfig struct {
+	Workers      int
+	PollInterval time.Duration
+}
+
+// Storage backends of uploaded files.
+const (
+	StorageBackendLocal = "local"
+	StorageBackendS3    = "s3"
+)
+
+type StorageConfig struct {
+	Backend     string
+	LocalDir    string
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	S3PathStyle bool
+}
+
+// UploadConfig limits uploaded notesheet files, in bytes.
+type UploadConfig struct {
+	MaxFileSize       int64
+	GroupStorageQuota int64
+}
+
+// MinJWTSecretLength is the shortest secret tokens may be signed with, in bytes.
+const MinJWTSecretLength = 32
+
 func LoadConfig() (*Config, error) {
+	jwtSecret := os.Getenv("JWT_SECRET")
+	if jwtSecret == "" {
+		return nil, errors.New("JWT_SECRET is not set")
+	}
+	if len(jwtSecret) < MinJWTSecretLength {
+		return nil, fmt.Errorf("JWT_SECRET must be at least %d bytes long", MinJWTSecretLength)
+	}
+
+	trustedProxies, err := parseNetworks(os.Getenv("TRUSTED_PROXIES"))
+	if err != nil {
+		return nil, fmt.Errorf("TRUSTED_PROXIES: %w", err)
+	}
+
 	return &Config{
 		GoogleCalendarConfig: &GoogleCalendarConfig{
 			CredentialsFile: getEnvOrDefault("GOOGLE_CALENDAR_CREDENTIALS", "internal/config/credentials.json"),
 		},
+		AuthConfig: &AuthConfig{
+			JWTSecret:       jwtSecret,
+			AccessTokenTTL:  getDurationOrDefault("ACCESS_TOKEN_TTL", 15*time.Minute),
+			RefreshTokenTTL: getDurationOrDefault("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+			TrustedProxies:  trustedProxies,
+		},
+		JobConfig: &JobConfig{
+			Workers:      getIntOrDefault("JOB_WORKERS", 4),
+			PollInterval: getDurationOrDefault("JOB_POLL_INTERVAL", 2*time.Second),
+		},
+		StorageConfig: &StorageConfig{
+			Backend:     getEnvOrDefault("STORAGE_BACKEND", StorageBackendLocal),
+			LocalDir:    getEnvOrDefault("STORAGE_LOCAL_DIR", "/app/uploads"),
+			S3Endpoint:  os.Getenv("S3_ENDPOINT"),
+			S3Region:    getEnvOrDefault("S3_REGION", "us-east-1"),
+			S3Bucket:    os.Getenv("S3_BUCKET"),
+			S3AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			S3SecretKey: os.Getenv("S3_SECRET_KEY"),
+			S3PathStyle: getBoolOrDefault("S3_PATH_STYLE", true),
+		},
+		UploadConfig: &UploadConfig{
+			MaxFileSize:       int64(getIntOrDefault("UPLOAD_MAX_FILE_SIZE_MB", 20)) << 20,
+			GroupStorageQuota: int64(getIntOrDefault("GROUP_STORAGE_QUOTA_MB", 1024)) << 20,
+		},
 	}, nil
 }
 
+// parseNetworks parses a comma-separated list of IP addresses and CIDR networks.
+func parseNetworks(value string) ([]*net.IPNet, error) {
+	var networks []*net.IPNet
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid address %q", entry)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid network %q", entry)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
 }
+
+func getDurationOrDefault(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
+
+func getIntOrDefault(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil && n > 0 {
+			return n
+		}
+	}
+	return defaultValue
+}
+
+func getBoolOrDefault(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return defaultValue
+}
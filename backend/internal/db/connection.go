@@ -24,8 +24,28 @@ func dropDB() {
 		&model.Event{},
 		&model.Track{},
 		&model.Notesheet{},
+		&model.Session{},
+		&model.PasswordResetToken{},
+		&model.EmailVerificationToken{},
+		&model.CustomRole{},
+		&model.SubgroupUser{},
+		&model.GroupInvitation{},
+		&model.JoinRequest{},
+		&model.EventUser{},
+		&model.EventException{},
+		&model.CalendarFeed{},
+		&model.GoogleToken{},
+		&model.GoogleCalendarEvent{},
+		&model.GoogleOAuthState{},
+		&model.Job{},
+		&model.Performance{},
+		&model.EventSubgroupNote{},
+		&model.Blob{},
+		&model.NotesheetVersion{},
+		&model.Instrument{},
 		"user_group",
 		"subgroup_user",
+		"instrument_user",
 		"notesheet_subgroup",
 		"announcement_subgroup",
 		"event_tracks",
@@ -36,6 +56,29 @@ func dropDB() {
 
 // createDB creates all required database tables using GORM AutoMigrate.
 func createDB() {
+	if err := db.SetupJoinTable(&model.Subgroup{}, "Users", &model.SubgroupUser{}); err != nil {
+		log.Fatal("join table setup failed: ", err)
+	}
+	if err := db.SetupJoinTable(&model.User{}, "Subgroups", &model.SubgroupUser{}); err != nil {
+		log.Fatal("join table setup failed: ", err)
+	}
+	if err := db.SetupJoinTable(&model.Event{}, "Users", &model.EventUser{}); err != nil {
+		log.Fatal("join table setup failed: ", err)
+	}
+	if err := db.SetupJoinTable(&model.Instrument{}, "Players", &model.InstrumentUser{}); err != nil {
+		log.Fatal("join table setup failed: ", err)
+	}
+
+	// Calendar copies used to be stored once per event; they are now stored per attendee.
+	if db.Migrator().HasIndex(&model.GoogleCalendarEvent{}, "idx_google_calendar_events_event_id") {
+		if err := db.Migrator().DropIndex(&model.GoogleCalendarEvent{}, "idx_google_calendar_events_event_id"); err != nil {
+			log.Fatal("dropping index failed: ", err)
+		}
+	}
+
+	// Accounts created before email addresses were verified are trusted as they are.
+	backfillEmailVerified := !db.Migrator().HasColumn(&model.User{}, "EmailVerified")
+
 	err := db.AutoMigrate(
 		&model.Group{},
 		&model.User{},
@@ -47,10 +90,54 @@ func createDB() {
 		&model.Notesheet{},
 		&model.GoogleToken{},
 		&model.GoogleCalendarEvent{},
+		&model.Session{},
+		&model.PasswordResetToken{},
+		&model.EmailVerificationToken{},
+		&model.CustomRole{},
+		&model.SubgroupUser{},
+		&model.GroupInvitation{},
+		&model.JoinRequest{},
+		&model.EventUser{},
+		&model.EventException{},
+		&model.CalendarFeed{},
+		&model.GoogleOAuthState{},
+		&model.Job{},
+		&model.Performance{},
+		&model.EventSubgroupNote{},
+		&model.Blob{},
+		&model.NotesheetVersion{},
+		&model.Instrument{},
+		&model.InstrumentUser{},
 	)
 	if err != nil {
 		log.Fatal("migrations failed: ", err)
 	}
+
+	if backfillEmailVerified {
+		if err := db.Exec(`UPDATE users SET email_verified = true, verified_at = NOW() WHERE email_verified = false`).Error; err != nil {
+			log.Fatal("email verification migration failed: ", err)
+		}
+	}
+
+	// Files of notesheets uploaded before versions were kept become their first version.
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`INSERT INTO notesheet_versions (notesheet_id, version, filepath, file_name, file_type, size, change_note, created_at)
+			SELECT id, 1, filepath, file_name, file_type,
+				COALESCE((SELECT size FROM blobs WHERE blobs.key = notesheets.filepath), 0), '', NOW() FROM notesheets
+			WHERE filepath <> '' AND current_version = 0`).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`UPDATE notesheets SET current_version = 1 WHERE filepath <> '' AND current_version = 0`).Error
+	}); err != nil {
+		log.Fatal("notesheet version migration failed: ", err)
+	}
+
+	// Jobs enqueued before links were issued by the jobs sending them carry
+	// their tokens in plain text.
+	if err := db.Exec(`UPDATE jobs SET payload = payload - 'token' - 'response_token'
+		WHERE payload - 'token' - 'response_token' <> payload`).Error; err != nil {
+		log.Fatal("job payload migration failed: ", err)
+	}
 	fmt.Println("migrations completed successfully")
 }
 
@@ -4,3 +4,32 @@ type SystemStats struct {
 	TotalUsers  int64 `json:"total_users"`
 	TotalGroups int64 `json:"total_groups"`
 }
+
+type UserSummary struct {
+	ID            uint   `json:"id"`
+	FirstName     string `json:"first_name"`
+	LastName      string `json:"last_name"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	IsAdmin       bool   `json:"is_admin"`
+	Disabled      bool   `json:"disabled"`
+}
+
+type UserList struct {
+	Users []UserSummary `json:"users"`
+	Total int64         `json:"total"`
+}
+
+// StorageCleanupReport lists the stored files garbage collection found
+// unreferenced, and deleted unless it was a dry run.
+type StorageCleanupReport struct {
+	DryRun             bool         `json:"dry_run"`
+	CorrectedRefCounts int64        `json:"corrected_ref_counts"`
+	Files              []StoredFile `json:"files"`
+	TotalBytes         int64        `json:"total_bytes"`
+}
+
+type StoredFile struct {
+	Key  string `json:"key"`
+	Size int64  `json:"size"`
+}
@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"band-manager-backend/internal/model"
+	"time"
+)
+
+type AttendeeInfo struct {
+	UserID      uint       `json:"user_id"`
+	FirstName   string     `json:"first_name"`
+	LastName    string     `json:"last_name"`
+	Status      string     `json:"status"`
+	Comment     string     `json:"comment"`
+	RespondedAt *time.Time `json:"responded_at"`
+}
+
+type SubgroupAttendance struct {
+	SubgroupID uint           `json:"subgroup_id"`
+	Name       string         `json:"name"`
+	Totals     map[string]int `json:"totals"`
+	Attendees  []AttendeeInfo `json:"attendees"`
+}
+
+type EventAttendance struct {
+	EventID   uint                 `json:"event_id"`
+	Totals    map[string]int       `json:"totals"`
+	Subgroups []SubgroupAttendance `json:"subgroups"`
+	// Ungrouped lists attendees who do not belong to any subgroup.
+	Ungrouped []AttendeeInfo `json:"ungrouped"`
+}
+
+// EventDetails holds the editable details of an event.
+type EventDetails struct {
+	Title       string
+	Description string
+	Location    string
+	Type        string
+	Date        time.Time
+	EndDate     time.Time
+	AllDay      bool
+	TimeZone    string
+}
+
+// EventConflict describes a member who is already booked in another event at
+// the time of an event being scheduled.
+type EventConflict struct {
+	UserID     uint      `json:"user_id"`
+	FirstName  string    `json:"first_name"`
+	LastName   string    `json:"last_name"`
+	EventID    uint      `json:"event_id"`
+	EventTitle string    `json:"event_title"`
+	EventType  string    `json:"event_type"`
+	Date       time.Time `json:"date"`
+	EndDate    time.Time `json:"end_date"`
+	GroupID    uint      `json:"group_id"`
+	GroupName  string    `json:"group_name"`
+}
+
+// SetlistEntryDetails holds the editable details of a setlist entry. Entries
+// without a track are breaks.
+type SetlistEntryDetails struct {
+	TrackID         *uint      `json:"track_id"`
+	Title           string     `json:"title"`
+	StartTime       *time.Time `json:"start_time"`
+	DurationSeconds int        `json:"duration_seconds"`
+	Key             string     `json:"key"`
+	Tempo           int        `json:"tempo"`
+	Notes           string     `json:"notes"`
+}
+
+// SetlistEntry is an entry of a setlist with its planned start.
+type SetlistEntry struct {
+	ID              uint       `json:"id"`
+	Position        int        `json:"position"`
+	TrackID         *uint      `json:"track_id"`
+	Title           string     `json:"title"`
+	IsBreak         bool       `json:"is_break"`
+	StartTime       *time.Time `json:"start_time"`
+	PlannedStart    time.Time  `json:"planned_start"`
+	DurationSeconds int        `json:"duration_seconds"`
+	Key             string     `json:"key"`
+	Tempo           int        `json:"tempo"`
+	Notes           string     `json:"notes"`
+}
+
+// Setlist is the running order of an event. TotalDurationSeconds adds up the
+// durations of all entries, breaks included; PlannedEnd is when the last one ends.
+type Setlist struct {
+	EventID              uint           `json:"event_id"`
+	Entries              []SetlistEntry `json:"entries"`
+	TotalDurationSeconds int            `json:"total_duration_seconds"`
+	PlannedEnd           *time.Time     `json:"planned_end"`
+}
+
+// SubgroupNote holds instructions for one subgroup for an event, such as
+// dress code or call time.
+type SubgroupNote struct {
+	SubgroupID   uint   `json:"subgroup_id"`
+	SubgroupName string `json:"subgroup_name"`
+	Notes        string `json:"notes"`
+}
+
+// EventInfo is an event together with its setlist and subgroup notes.
+type EventInfo struct {
+	*model.Event
+	Setlist       *Setlist       `json:"setlist"`
+	SubgroupNotes []SubgroupNote `json:"subgroup_notes"`
+}
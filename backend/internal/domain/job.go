@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"band-manager-backend/internal/model"
+	"time"
+)
+
+// Payloads of background jobs. They reference records by ID, so that jobs
+// work on the current state of the records when they run. Secret tokens are
+// never part of payloads; jobs sending links issue them when they run.
+
+// TokenEmailJob references an email verification or password reset token.
+type TokenEmailJob struct {
+	TokenID uint `json:"token_id"`
+}
+
+type InvitationEmailJob struct {
+	InvitationID uint `json:"invitation_id"`
+}
+
+type JoinRequestEmailJob struct {
+	RequestID uint `json:"request_id"`
+	Approved  bool `json:"approved"`
+}
+
+type EventEmailJob struct {
+	EventID uint `json:"event_id"`
+	UserID  uint `json:"user_id"`
+}
+
+// EventReminderJob reminds the members assigned to an event of the
+// occurrence starting at OccurrenceDate, Minutes before it starts.
+type EventReminderJob struct {
+	EventID        uint      `json:"event_id"`
+	OccurrenceDate time.Time `json:"occurrence_date"`
+	Minutes        int       `json:"minutes"`
+}
+
+type EventReminderEmailJob struct {
+	EventReminderJob
+	UserID uint `json:"user_id"`
+}
+
+// EventChangeEmailJob carries the time and place of the event before it was changed.
+type EventChangeEmailJob struct {
+	EventID          uint      `json:"event_id"`
+	UserID           uint      `json:"user_id"`
+	PreviousDate     time.Time `json:"previous_date"`
+	PreviousEndDate  time.Time `json:"previous_end_date"`
+	PreviousAllDay   bool      `json:"previous_all_day"`
+	PreviousLocation string    `json:"previous_location"`
+}
+
+// EventCancelEmailJob carries the details of the cancelled event, as its
+// record may be deleted. Series marks cancelling all occurrences from Date on.
+type EventCancelEmailJob struct {
+	UserID    uint      `json:"user_id"`
+	GroupName string    `json:"group_name"`
+	Title     string    `json:"title"`
+	Location  string    `json:"location"`
+	Date      time.Time `json:"date"`
+	EndDate   time.Time `json:"end_date"`
+	AllDay    bool      `json:"all_day"`
+	TimeZone  string    `json:"time_zone"`
+	Series    bool      `json:"series"`
+}
+
+type AnnouncementEmailJob struct {
+	AnnouncementID uint `json:"announcement_id"`
+	UserID         uint `json:"user_id"`
+}
+
+type CalendarSyncEventJob struct {
+	EventID uint `json:"event_id"`
+}
+
+// CalendarRemoveCopyJob carries the whole copy, as its record is deleted
+// along with the event it belongs to.
+type CalendarRemoveCopyJob struct {
+	CopyID     uint   `json:"copy_id"`
+	EventID    uint   `json:"event_id"`
+	UserID     uint   `json:"user_id"`
+	CalendarID string `json:"calendar_id"`
+}
+
+type CalendarSyncUpcomingJob struct {
+	UserID uint `json:"user_id"`
+}
+
+type JobList struct {
+	Jobs  []*model.Job `json:"jobs"`
+	Total int64        `json:"total"`
+}
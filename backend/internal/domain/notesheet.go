@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// NotesheetVersion describes an uploaded version of the file of a notesheet.
+type NotesheetVersion struct {
+	ID             uint      `json:"id"`
+	NotesheetID    uint      `json:"notesheet_id"`
+	Version        int       `json:"version"`
+	FileName       string    `json:"file_name"`
+	FileType       string    `json:"file_type"`
+	Size           int64     `json:"size"`
+	ChangeNote     string    `json:"change_note"`
+	UploadedByID   *uint     `json:"uploaded_by_id"`
+	UploadedByName string    `json:"uploaded_by_name"`
+	UploadedAt     time.Time `json:"uploaded_at"`
+	Current        bool      `json:"current"`
+}
+
+// StorageUsage reports how much storage a group uses for its notesheet
+// files, each stored file counted once, out of its quota, in bytes.
+type StorageUsage struct {
+	UsedBytes   int64 `json:"used_bytes"`
+	QuotaBytes  int64 `json:"quota_bytes"`
+	MaxFileSize int64 `json:"max_file_size"`
+}
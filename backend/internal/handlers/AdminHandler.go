@@ -4,6 +4,7 @@
 package handlers
 
 import (
+	"band-manager-backend/internal/services"
 	"band-manager-backend/internal/usecases"
 	"encoding/json"
 	"net/http"
@@ -16,12 +17,22 @@ type AdminHandler struct {
 	adminUsecase *usecases.AdminUsecase
 }
 
-func NewAdminHandler() *AdminHandler {
+func NewAdminHandler(storage services.Storage) *AdminHandler {
 	return &AdminHandler{
-		adminUsecase: usecases.NewAdminUsecase(),
+		adminUsecase: usecases.NewAdminUsecase(storage),
 	}
 }
 
+// IsAdmin reports whether the user is a system administrator.
+func (h *AdminHandler) IsAdmin(userID uint) bool {
+	return h.adminUsecase.IsAdmin(userID)
+}
+
+// BootstrapAdmin creates or promotes the first administrator if none exists yet.
+func (h *AdminHandler) BootstrapAdmin(email, password string) error {
+	return h.adminUsecase.BootstrapAdmin(email, password)
+}
+
 // ResetUserPassword handles POST /api/admin/users/reset-password/{userId}
 // Resets the password for a specified user.
 func (h *AdminHandler) ResetUserPassword(w http.ResponseWriter, r *http.Request) {
@@ -53,6 +64,32 @@ func (h *AdminHandler) ResetUserPassword(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(http.StatusOK)
 }
 
+// RevokeUserSessions handles POST /api/admin/users/revoke-sessions/{userId}
+// Revokes every active session of a specified user.
+func (h *AdminHandler) RevokeUserSessions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	pathParts := strings.Split(r.URL.Path, "/")
+	userID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.adminUsecase.RevokeUserSessions(uint(userID)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Sessions revoked successfully",
+	})
+}
+
 // GetSystemStats handles GET /api/admin/stats
 // Retrieves system-wide statistics including total users and groups.
 func (h *AdminHandler) GetSystemStats(w http.ResponseWriter, r *http.Request) {
@@ -70,3 +107,230 @@ func (h *AdminHandler) GetSystemStats(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(stats)
 }
+
+// ListUsers handles GET /api/admin/users?query={query}&page={page}&page_size={pageSize}
+// Returns a page of users, optionally filtered by name or email.
+func (h *AdminHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	page, _ := strconv.Atoi(query.Get("page"))
+	pageSize, _ := strconv.Atoi(query.Get("page_size"))
+
+	users, err := h.adminUsecase.ListUsers(query.Get("query"), page, pageSize)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(users)
+}
+
+// SetUserDisabled handles PUT /api/admin/users/disable/{userId}
+// Disables or re-enables a user's account.
+func (h *AdminHandler) SetUserDisabled(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	requesterID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	pathParts := strings.Split(r.URL.Path, "/")
+	userID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	var request struct {
+		Disabled bool `json:"disabled"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.adminUsecase.SetUserDisabled(requesterID, uint(userID), request.Disabled); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "User updated successfully",
+	})
+}
+
+// SetUserAdmin handles PUT /api/admin/users/admin/{userId}
+// Grants or removes administrator rights of a user.
+func (h *AdminHandler) SetUserAdmin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	requesterID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	pathParts := strings.Split(r.URL.Path, "/")
+	userID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	var request struct {
+		IsAdmin bool `json:"is_admin"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.adminUsecase.SetUserAdmin(requesterID, uint(userID), request.IsAdmin); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "User updated successfully",
+	})
+}
+
+// DeleteUser handles DELETE /api/admin/users/delete/{userId}
+// Removes a user's account.
+func (h *AdminHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	requesterID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	pathParts := strings.Split(r.URL.Path, "/")
+	userID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.adminUsecase.DeleteUser(requesterID, uint(userID)); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "User deleted successfully",
+	})
+}
+
+// ListJobs handles GET /api/admin/jobs?status={status}&type={type}&page={page}&page_size={pageSize}
+// Returns a page of background jobs, optionally filtered by status and type.
+func (h *AdminHandler) ListJobs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	page, _ := strconv.Atoi(query.Get("page"))
+	pageSize, _ := strconv.Atoi(query.Get("page_size"))
+
+	jobs, err := h.adminUsecase.ListJobs(query.Get("status"), query.Get("type"), page, pageSize)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(jobs)
+}
+
+// GetJob handles GET /api/admin/jobs/{jobId}
+// Returns a background job with its payload, attempts and last error.
+func (h *AdminHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	pathParts := strings.Split(r.URL.Path, "/")
+	jobID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid job ID", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.adminUsecase.GetJob(uint(jobID))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
+
+// RetryJob handles POST /api/admin/jobs/retry/{jobId}
+// Puts a dead job back in the queue with a fresh set of attempts.
+func (h *AdminHandler) RetryJob(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	pathParts := strings.Split(r.URL.Path, "/")
+	jobID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid job ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.adminUsecase.RetryJob(uint(jobID)); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Job queued for retry",
+	})
+}
+
+// CleanupStorage handles POST /api/admin/storage/cleanup?dry_run={true|false}
+// Deletes stored files no notesheet references and reports them. A dry run
+// only reports the files.
+func (h *AdminHandler) CleanupStorage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dry_run"))
+
+	report, err := h.adminUsecase.CleanupStorage(dryRun)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
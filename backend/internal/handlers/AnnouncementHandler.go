@@ -28,13 +28,18 @@ func (h *AnnouncementHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	senderID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
 	var request struct {
 		Title        string `json:"title"`
 		Description  string `json:"description"`
 		Priority     uint   `json:"priority"`
 		GroupID      uint   `json:"group_id"`
-		SenderID     uint   `json:"sender_id"`
 		RecipientIDs []uint `json:"recipient_ids"`
+		SubgroupIDs  []uint `json:"subgroup_ids"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
@@ -47,8 +52,9 @@ func (h *AnnouncementHandler) Create(w http.ResponseWriter, r *http.Request) {
 		request.Description,
 		request.Priority,
 		request.GroupID,
-		request.SenderID,
+		senderID,
 		request.RecipientIDs,
+		request.SubgroupIDs,
 	)
 
 	if err != nil {
@@ -60,7 +66,7 @@ func (h *AnnouncementHandler) Create(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(announcement)
 }
 
-// Delete handles DELETE /api/announcement/{id}/{userId}
+// Delete handles DELETE /api/announcement/delete/{announcementId}
 // Deletes an existing announcement if user has proper permissions.
 func (h *AnnouncementHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodDelete {
@@ -68,16 +74,19 @@ func (h *AnnouncementHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pathParts := strings.Split(r.URL.Path, "/")
-	announcementID, err := strconv.ParseUint(pathParts[len(pathParts)-2], 10, 64)
-	userID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
 
+	pathParts := strings.Split(r.URL.Path, "/")
+	announcementID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
 	if err != nil {
-		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		http.Error(w, "Invalid announcement ID", http.StatusBadRequest)
 		return
 	}
 
-	err = h.announcementUsecase.DeleteAnnouncement(uint(announcementID), uint(userID))
+	err = h.announcementUsecase.DeleteAnnouncement(uint(announcementID), userID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -89,21 +98,20 @@ func (h *AnnouncementHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// GetUserAnnouncements handles GET /api/announcement/user/{userId}
-// Returns all announcements available to the specified user.
+// GetUserAnnouncements handles GET /api/announcement/user
+// Returns all announcements available to the authenticated user.
 func (h *AnnouncementHandler) GetUserAnnouncements(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	userID, err := strconv.ParseUint(strings.Split(r.URL.Path, "/")[len(strings.Split(r.URL.Path, "/"))-1], 10, 64)
-	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
 		return
 	}
 
-	announcements, err := h.announcementUsecase.GetUserAnnouncements(uint(userID))
+	announcements, err := h.announcementUsecase.GetUserAnnouncements(userID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -115,7 +123,7 @@ func (h *AnnouncementHandler) GetUserAnnouncements(w http.ResponseWriter, r *htt
 	})
 }
 
-// GetGroupAnnouncements handles GET /api/announcement/group/{groupId}/{userId}
+// GetGroupAnnouncements handles GET /api/announcement/group/{groupId}
 // Returns all announcements for the specified group if user is a member.
 func (h *AnnouncementHandler) GetGroupAnnouncements(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -123,15 +131,19 @@ func (h *AnnouncementHandler) GetGroupAnnouncements(w http.ResponseWriter, r *ht
 		return
 	}
 
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
 	pathParts := strings.Split(r.URL.Path, "/")
-	groupID, err := strconv.ParseUint(pathParts[len(pathParts)-2], 10, 64)
-	userID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	groupID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
 	if err != nil {
-		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
 		return
 	}
 
-	announcements, err := h.announcementUsecase.GetGroupAnnouncements(uint(groupID), uint(userID))
+	announcements, err := h.announcementUsecase.GetGroupAnnouncements(uint(groupID), userID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -4,32 +4,53 @@
 package handlers
 
 import (
+	"band-manager-backend/internal/services"
 	"band-manager-backend/internal/usecases"
 	"encoding/json"
+	"errors"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 )
 
 // AuthHandler manages user authentication and registration.
 type AuthHandler struct {
-	authUsecase *usecases.AuthUsecase
+	authUsecase    *usecases.AuthUsecase
+	trustedProxies []*net.IPNet
 }
 
-func NewAuthHandler() *AuthHandler {
-	authUsecase := usecases.NewAuthUsecase()
+func NewAuthHandler(tokenService *services.TokenService, trustedProxies []*net.IPNet) *AuthHandler {
+	authUsecase := usecases.NewAuthUsecase(tokenService)
 	return &AuthHandler{
-		authUsecase: authUsecase,
+		authUsecase:    authUsecase,
+		trustedProxies: trustedProxies,
 	}
 }
 
+// Authenticate resolves the bearer token of a request into the caller's user and session IDs.
+func (h *AuthHandler) Authenticate(r *http.Request) (uint, uint, error) {
+	header := r.Header.Get("Authorization")
+	token, found := strings.CutPrefix(header, "Bearer ")
+	if !found || token == "" {
+		return 0, 0, errors.New("missing bearer token")
+	}
+	return h.authUsecase.Authenticate(token)
+}
+
 // Login handles POST /api/verify/login
-// Authenticates user and returns user details with group memberships.
+// Authenticates user and returns user details with an access and refresh token.
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	type LoginResponse struct {
-		ID        uint   `json:"id"`
-		FirstName string `json:"first_name"`
-		LastName  string `json:"last_name"`
-		Email     string `json:"email"`
-		Groups    []struct {
+		ID            uint   `json:"id"`
+		FirstName     string `json:"first_name"`
+		LastName      string `json:"last_name"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		AccessToken   string `json:"access_token"`
+		RefreshToken  string `json:"refresh_token"`
+		ExpiresIn     int64  `json:"expires_in"`
+		Groups        []struct {
 			ID   uint   `json:"id"`
 			Name string `json:"name"`
 			Role string `json:"role"`
@@ -51,23 +72,54 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := h.authUsecase.Login(request.Email, request.Password)
+	user, tokens, err := h.authUsecase.Login(request.Email, request.Password, r.UserAgent(), clientIP(r, h.trustedProxies))
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusUnauthorized)
 		return
 	}
 
 	response := LoginResponse{
-		ID:        user.ID,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		Email:     user.Email,
+		ID:            user.ID,
+		FirstName:     user.FirstName,
+		LastName:      user.LastName,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified,
+		AccessToken:   tokens.AccessToken,
+		RefreshToken:  tokens.RefreshToken,
+		ExpiresIn:     tokens.ExpiresIn,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
+// Refresh handles POST /api/verify/refresh
+// Exchanges a refresh token for a new access and refresh token.
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	tokens, err := h.authUsecase.Refresh(request.RefreshToken)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tokens)
+}
+
 // Register handles POST /api/verify/register
 // Creates a new user account with provided details.
 func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
@@ -97,3 +149,224 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]string{"message": "Registration successful"})
 }
+
+// VerifyEmail handles POST /api/verify/email
+// Confirms the user's email address using the token from a verification link.
+func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request struct {
+		Token string `json:"token"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.authUsecase.VerifyEmail(request.Token); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Email verified successfully",
+	})
+}
+
+// ResendVerification handles POST /api/verify/resend-verification
+// Sends a new verification link, limited to one per minute and five per hour.
+func (h *AuthHandler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request struct {
+		Email string `json:"email"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.authUsecase.ResendVerification(request.Email); err != nil {
+		if errors.Is(err, usecases.ErrTooManyRequests) {
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "If the account awaits verification, a new link has been sent",
+	})
+}
+
+// ForgotPassword handles POST /api/verify/forgot-password
+// Sends a password reset link to the given email address if an account exists.
+func (h *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request struct {
+		Email string `json:"email"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.authUsecase.RequestPasswordReset(request.Email); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "If the account exists, a reset link has been sent",
+	})
+}
+
+// ResetPassword handles POST /api/verify/reset-password
+// Sets a new password using the token from a reset link.
+func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request struct {
+		Token       string `json:"token"`
+		NewPassword string `json:"new_password"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.authUsecase.ResetPassword(request.Token, request.NewPassword); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Password reset successful",
+	})
+}
+
+// ListSessions handles GET /api/session/list
+// Returns the authenticated user's active sessions.
+func (h *AuthHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, sessionID, ok := authenticatedSession(w, r)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.authUsecase.ListSessions(userID, sessionID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"sessions": sessions,
+	})
+}
+
+// Logout handles POST /api/session/logout
+// Revokes the session the request was made with.
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, sessionID, ok := authenticatedSession(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.authUsecase.Logout(userID, sessionID); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Logged out successfully",
+	})
+}
+
+// RevokeSession handles DELETE /api/session/revoke/{sessionId}
+// Revokes one of the authenticated user's sessions.
+func (h *AuthHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	pathParts := strings.Split(r.URL.Path, "/")
+	sessionID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.authUsecase.RevokeSession(userID, uint(sessionID)); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Session revoked successfully",
+	})
+}
+
+// RevokeOtherSessions handles POST /api/session/revoke-others
+// Revokes every session of the authenticated user except the current one.
+func (h *AuthHandler) RevokeOtherSessions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, sessionID, ok := authenticatedSession(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.authUsecase.RevokeOtherSessions(userID, sessionID); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Other sessions revoked successfully",
+	})
+}
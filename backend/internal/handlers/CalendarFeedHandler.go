@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"band-manager-backend/internal/usecases"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// CalendarFeedHandler manages iCalendar feeds that calendar apps subscribe to.
+type CalendarFeedHandler struct {
+	calendarFeedUsecase *usecases.CalendarFeedUsecase
+}
+
+func NewCalendarFeedHandler() *CalendarFeedHandler {
+	return &CalendarFeedHandler{
+		calendarFeedUsecase: usecases.NewCalendarFeedUsecase(),
+	}
+}
+
+// Create handles POST /api/calendar-feed/create
+// Creates a feed of the user's events, or of a group's events if group_id is
+// given, and returns the feed path containing its token.
+func (h *CalendarFeedHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		GroupID *uint `json:"group_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	feed, token, err := h.calendarFeedUsecase.CreateFeed(request.GroupID, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"feed":  feed,
+		"token": token,
+		"path":  "/api/calendar-feed/ics/" + token + ".ics",
+	})
+}
+
+// GetFeeds handles GET /api/calendar-feed/list
+// Lists the user's feeds that have not been revoked.
+func (h *CalendarFeedHandler) GetFeeds(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	feeds, err := h.calendarFeedUsecase.GetUserFeeds(userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(feeds)
+}
+
+// Revoke handles DELETE /api/calendar-feed/revoke/{feedId}
+// Revokes one of the user's feeds, so that its link stops working.
+func (h *CalendarFeedHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	pathParts := strings.Split(r.URL.Path, "/")
+	feedID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid feed ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.calendarFeedUsecase.RevokeFeed(uint(feedID), userID); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Calendar feed revoked successfully",
+	})
+}
+
+// Serve handles GET /api/calendar-feed/ics/{token}.ics
+// Serves the iCalendar document of a feed to calendar apps; the token in the
+// path is the only credential.
+func (h *CalendarFeedHandler) Serve(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	pathParts := strings.Split(r.URL.Path, "/")
+	token := strings.TrimSuffix(pathParts[len(pathParts)-1], ".ics")
+	if token == "" {
+		http.Error(w, "Invalid feed token", http.StatusBadRequest)
+		return
+	}
+
+	calendar, err := h.calendarFeedUsecase.RenderFeed(token)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="calendar.ics"`)
+	w.Header().Set("Cache-Control", "private, max-age=900")
+	w.Write([]byte(calendar))
+}
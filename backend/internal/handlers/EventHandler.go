@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"band-manager-backend/internal/domain"
 	"band-manager-backend/internal/model"
 	"band-manager-backend/internal/services"
 	"band-manager-backend/internal/usecases"
+	"band-manager-backend/internal/usecases/helpers"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -15,35 +18,43 @@ import (
 // EventHandler manages musical event operations.
 type EventHandler struct {
 	eventUsecase *usecases.EventUsecase
-	gcService    *services.GoogleCalendarService
-	emailService *services.EmailService
 }
 
-func NewEventHandler(gcService *services.GoogleCalendarService, emailService *services.EmailService) *EventHandler {
+func NewEventHandler(gcService *services.GoogleCalendarService) *EventHandler {
 	return &EventHandler{
-		eventUsecase: usecases.NewEventUsecase(gcService, emailService),
-		gcService:    gcService,
-		emailService: emailService,
+		eventUsecase: usecases.NewEventUsecase(gcService),
 	}
 }
 
 // Create handles POST /api/event/create
 // Creates a new event with specified details, tracks, and participants.
+// Participants booked in other events at the same time are reported with
+// 409 Conflict unless force is set.
 func (h *EventHandler) Create(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
 	var request struct {
-		Title       string    `json:"title"`
-		Description string    `json:"description"`
-		Location    string    `json:"location"`
-		Date        time.Time `json:"date"`
-		GroupID     uint      `json:"group_id"`
-		TrackIDs    []uint    `json:"track_ids"`
-		UserIDs     []uint    `json:"user_ids"`
-		UserID      uint      `json:"user_id"`
+		Title          string    `json:"title"`
+		Description    string    `json:"description"`
+		Location       string    `json:"location"`
+		Type           string    `json:"type"`
+		Date           time.Time `json:"date"`
+		EndDate        time.Time `json:"end_date"`
+		AllDay         bool      `json:"all_day"`
+		TimeZone       string    `json:"time_zone"`
+		RecurrenceRule string    `json:"recurrence_rule"`
+		GroupID        uint      `json:"group_id"`
+		TrackIDs       []uint    `json:"track_ids"`
+		UserIDs        []uint    `json:"user_ids"`
+		Force          bool      `json:"force"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
@@ -52,17 +63,25 @@ func (h *EventHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	event, err := h.eventUsecase.CreateEvent(
-		request.Title,
-		request.Description,
-		request.Location,
-		request.Date,
+		domain.EventDetails{
+			Title:       request.Title,
+			Description: request.Description,
+			Location:    request.Location,
+			Type:        request.Type,
+			Date:        request.Date,
+			EndDate:     request.EndDate,
+			AllDay:      request.AllDay,
+			TimeZone:    request.TimeZone,
+		},
+		request.RecurrenceRule,
 		request.GroupID,
 		request.TrackIDs,
 		request.UserIDs,
-		request.UserID,
+		request.Force,
+		userID,
 	)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeEventError(w, err)
 		return
 	}
 
@@ -71,65 +90,152 @@ func (h *EventHandler) Create(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(event)
 }
 
-// GetInfo handles GET /api/event/info/{eventId}/{userId}
-// Retrieves detailed information about a specific event.
-func (h *EventHandler) GetInfo(w http.ResponseWriter, r *http.Request) {
+// CheckSlot handles GET /api/event/check-slot?group_id={groupId}&date={date}&end_date={date}&all_day={bool}&time_zone={zone}&recurrence_rule={rule}&user_ids={ids}&event_id={eventId}
+// Lists the conflicts an event at the proposed time would cause for the given
+// participants, or all group members, ignoring the event being edited if any.
+func (h *EventHandler) CheckSlot(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	pathParts := strings.Split(r.URL.Path, "/")
-	id, err := strconv.ParseUint(pathParts[len(pathParts)-2], 10, 64)
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	groupID, err := strconv.ParseUint(query.Get("group_id"), 10, 64)
 	if err != nil {
-		http.Error(w, "Invalid event ID", http.StatusBadRequest)
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+
+	date, err := timeQueryParam(r, "date")
+	if err != nil || date == nil {
+		http.Error(w, "Invalid date", http.StatusBadRequest)
 		return
 	}
+	details := domain.EventDetails{Date: *date, TimeZone: query.Get("time_zone")}
 
-	userID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	endDate, err := timeQueryParam(r, "end_date")
 	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		http.Error(w, "Invalid end date", http.StatusBadRequest)
 		return
 	}
+	if endDate != nil {
+		details.EndDate = *endDate
+	}
+
+	if value := query.Get("all_day"); value != "" {
+		if details.AllDay, err = strconv.ParseBool(value); err != nil {
+			http.Error(w, "Invalid all_day value", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var userIDs []uint
+	for _, value := range strings.Split(query.Get("user_ids"), ",") {
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+		userIDs = append(userIDs, uint(id))
+	}
+
+	var eventID uint64
+	if value := query.Get("event_id"); value != "" {
+		if eventID, err = strconv.ParseUint(value, 10, 64); err != nil {
+			http.Error(w, "Invalid event ID", http.StatusBadRequest)
+			return
+		}
+	}
 
-	event, err := h.eventUsecase.GetEvent(uint(id), uint(userID))
+	conflicts, err := h.eventUsecase.CheckSlot(uint(groupID), details, query.Get("recurrence_rule"), userIDs, uint(eventID), userID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(event)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"conflicts": conflicts,
+	})
 }
 
-// Update handles PUT /api/event/update/{eventId}/{userId}
-// Updates event details including tracks and participants.
-func (h *EventHandler) Update(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPut {
+// GetInfo handles GET /api/event/info/{eventId}
+// Retrieves detailed information about a specific event together with its
+// setlist and planned start times.
+func (h *EventHandler) GetInfo(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
 	pathParts := strings.Split(r.URL.Path, "/")
-	id, err := strconv.ParseUint(pathParts[len(pathParts)-2], 10, 64)
+	id, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
 	if err != nil {
 		http.Error(w, "Invalid event ID", http.StatusBadRequest)
 		return
 	}
 
-	userID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	info, err := h.eventUsecase.GetEventInfo(uint(id), userID)
 	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(info)
+}
+
+// Update handles PUT /api/event/update/{eventId}
+// Updates event details including tracks and participants. For recurring
+// events the required scope ("this", "following" or "all") and
+// occurrence_date select which occurrences are changed. Conflicts with
+// participants' other bookings are handled as in Create.
+func (h *EventHandler) Update(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	pathParts := strings.Split(r.URL.Path, "/")
+	id, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid event ID", http.StatusBadRequest)
 		return
 	}
 
 	var request struct {
-		Title       string    `json:"title"`
-		Description string    `json:"description"`
-		Location    string    `json:"location"`
-		Date        time.Time `json:"date"`
-		TrackIDs    []uint    `json:"track_ids"`
-		UserIDs     []uint    `json:"user_ids"`
+		Title          string     `json:"title"`
+		Description    string     `json:"description"`
+		Location       string     `json:"location"`
+		Type           string     `json:"type"`
+		Date           time.Time  `json:"date"`
+		EndDate        time.Time  `json:"end_date"`
+		AllDay         bool       `json:"all_day"`
+		TimeZone       string     `json:"time_zone"`
+		RecurrenceRule *string    `json:"recurrence_rule"`
+		Scope          string     `json:"scope"`
+		OccurrenceDate *time.Time `json:"occurrence_date"`
+		TrackIDs       []uint     `json:"track_ids"`
+		UserIDs        []uint     `json:"user_ids"`
+		Force          bool       `json:"force"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
@@ -139,16 +245,26 @@ func (h *EventHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	err = h.eventUsecase.UpdateEvent(
 		uint(id),
-		request.Title,
-		request.Description,
-		request.Location,
-		request.Date,
+		domain.EventDetails{
+			Title:       request.Title,
+			Description: request.Description,
+			Location:    request.Location,
+			Type:        request.Type,
+			Date:        request.Date,
+			EndDate:     request.EndDate,
+			AllDay:      request.AllDay,
+			TimeZone:    request.TimeZone,
+		},
+		request.RecurrenceRule,
+		request.Scope,
+		request.OccurrenceDate,
 		request.TrackIDs,
 		request.UserIDs,
-		uint(userID),
+		request.Force,
+		userID,
 	)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeEventError(w, err)
 		return
 	}
 
@@ -158,28 +274,39 @@ func (h *EventHandler) Update(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// Delete handles DELETE /api/event/delete/{eventId}/{userId}
-// Removes an event if user has proper permissions.
+// Delete handles DELETE /api/event/delete/{eventId}?scope={scope}&occurrence={date}
+// Removes an event if user has proper permissions. For recurring events scope
+// ("this", "following" or "all"), which is required, and occurrence select
+// which occurrences are cancelled.
 func (h *EventHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodDelete {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
 	pathParts := strings.Split(r.URL.Path, "/")
-	id, err := strconv.ParseUint(pathParts[len(pathParts)-2], 10, 64)
+	id, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
 	if err != nil {
 		http.Error(w, "Invalid event ID", http.StatusBadRequest)
 		return
 	}
 
-	userID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	occurrenceDate, err := timeQueryParam(r, "occurrence")
 	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		http.Error(w, "Invalid occurrence date", http.StatusBadRequest)
 		return
 	}
 
-	err = h.eventUsecase.DeleteEvent(uint(id), uint(userID))
+	err = h.eventUsecase.DeleteEvent(uint(id), r.URL.Query().Get("scope"), occurrenceDate, userID)
+	if errors.Is(err, usecases.ErrSeriesScopeRequired) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -191,28 +318,34 @@ func (h *EventHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// GetGroupEvents handles GET /api/event/group/{groupId}/{userId}
-// Returns all events for a specific group.
+// GetGroupEvents handles GET /api/event/group/{groupId}?from={date}&to={date}&type={type}
+// Returns the events of a group within the optional date range and of the
+// optional comma-separated types, with recurring events expanded into occurrences.
 func (h *EventHandler) GetGroupEvents(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
 	pathParts := strings.Split(r.URL.Path, "/")
-	groupID, err := strconv.ParseUint(pathParts[len(pathParts)-2], 10, 64)
+	groupID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
 	if err != nil {
 		http.Error(w, "Invalid group ID", http.StatusBadRequest)
 		return
 	}
 
-	userID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	from, to, err := dateRangeQueryParams(r)
 	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	events, err := h.eventUsecase.GetGroupEvents(uint(groupID), uint(userID))
+	events, err := h.eventUsecase.GetGroupEvents(uint(groupID), userID, from, to, eventTypesQueryParam(r))
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -224,22 +357,28 @@ func (h *EventHandler) GetGroupEvents(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// GetUserEvents handles GET /api/event/user/{userId}
-// Returns all events a user is participating in.
+// GetUserEvents handles GET /api/event/user?from={date}&to={date}&type={type}
+// Returns the events the authenticated user is participating in within the
+// optional date range and of the optional comma-separated types, with
+// recurring events expanded into occurrences.
 func (h *EventHandler) GetUserEvents(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	pathParts := strings.Split(r.URL.Path, "/")
-	userID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	from, to, err := dateRangeQueryParams(r)
 	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	events, err := h.eventUsecase.GetUserEvents(uint(userID))
+	events, err := h.eventUsecase.GetUserEvents(userID, from, to, eventTypesQueryParam(r))
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -251,7 +390,7 @@ func (h *EventHandler) GetUserEvents(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// GetEventTracks handles GET /api/event/tracks/{eventId}/{userId}
+// GetEventTracks handles GET /api/event/tracks/{eventId}
 // Returns all tracks associated with an event.
 func (h *EventHandler) GetEventTracks(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -259,20 +398,19 @@ func (h *EventHandler) GetEventTracks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pathParts := strings.Split(r.URL.Path, "/")
-	eventID, err := strconv.ParseUint(pathParts[len(pathParts)-2], 10, 64)
-	if err != nil {
-		http.Error(w, "Invalid event ID", http.StatusBadRequest)
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
 		return
 	}
 
-	userID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	pathParts := strings.Split(r.URL.Path, "/")
+	eventID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
 	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		http.Error(w, "Invalid event ID", http.StatusBadRequest)
 		return
 	}
 
-	tracks, err := h.eventUsecase.GetEventTracks(uint(eventID), uint(userID))
+	tracks, err := h.eventUsecase.GetEventTracks(uint(eventID), userID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -284,30 +422,273 @@ func (h *EventHandler) GetEventTracks(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// GoogleCalendarAuth handles GET /api/calendar/auth
-// Initiates Google Calendar authentication flow.
-func (h *EventHandler) GoogleCalendarAuth(w http.ResponseWriter, r *http.Request) {
-	authURL := h.gcService.GetAuthURL()
-	http.Redirect(w, r, authURL, http.StatusTemporaryRedirect)
+// Respond handles POST /api/event/respond/{eventId}
+// Records whether the authenticated member will attend the event.
+func (h *EventHandler) Respond(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	pathParts := strings.Split(r.URL.Path, "/")
+	eventID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid event ID", http.StatusBadRequest)
+		return
+	}
+
+	var request struct {
+		Status  string `json:"status"`
+		Comment string `json:"comment"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.eventUsecase.RespondToEvent(uint(eventID), userID, request.Status, request.Comment)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
 }
 
-// GoogleCalendarCallback handles GET /api/calendar/callback
-// Processes Google Calendar authentication callback.
-func (h *EventHandler) GoogleCalendarCallback(w http.ResponseWriter, r *http.Request) {
-	code := r.URL.Query().Get("code")
-	if code == "" {
-		http.Error(w, "Authorization code not found", http.StatusBadRequest)
+// RespondWithLink handles POST /api/event/respond-link
+// Records an attendance response submitted through a link from an event email.
+func (h *EventHandler) RespondWithLink(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	fmt.Println("Authorization code received:", code) // DEBUG
+	var request struct {
+		Token  string `json:"token"`
+		Status string `json:"status"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
 
-	err := h.gcService.SaveToken(code)
+	response, err := h.eventUsecase.RespondWithToken(request.Token, request.Status)
 	if err != nil {
-		fmt.Println("Error saving token:", err) // DEBUG
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetAttendance handles GET /api/event/attendance/{eventId}
+// Returns the attendance responses for an event rolled up per subgroup.
+func (h *EventHandler) GetAttendance(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	pathParts := strings.Split(r.URL.Path, "/")
+	eventID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid event ID", http.StatusBadRequest)
+		return
+	}
+
+	attendance, err := h.eventUsecase.GetEventAttendance(uint(eventID), userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(attendance)
+}
+
+// Export handles GET /api/event/export/{eventId}?layout={stage|programme}
+// Downloads a PDF of the event: a large-print stage setlist or a programme
+// (default) with time, location, setlist and subgroup notes.
+func (h *EventHandler) Export(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	eventID, err := lastPathID(r)
+	if err != nil {
+		http.Error(w, "Invalid event ID", http.StatusBadRequest)
+		return
+	}
+
+	layout := r.URL.Query().Get("layout")
+	if layout == "" {
+		layout = helpers.ExportLayoutProgramme
+	}
+
+	data, filename, err := h.eventUsecase.ExportEventPDF(eventID, layout, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Write(data)
+}
+
+// SetSubgroupNote handles PUT /api/event/subgroup-note/{eventId}
+// Sets the instructions for a subgroup for the event, printed in the
+// programme. Empty notes remove them.
+func (h *EventHandler) SetSubgroupNote(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	eventID, err := lastPathID(r)
+	if err != nil {
+		http.Error(w, "Invalid event ID", http.StatusBadRequest)
+		return
+	}
+
+	var request struct {
+		SubgroupID uint   `json:"subgroup_id"`
+		Notes      string `json:"notes"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.eventUsecase.SetSubgroupNote(eventID, request.SubgroupID, request.Notes, userID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SetReminders handles PUT /api/event/reminders/{eventId}
+// Sets how many minutes before the event members are reminded of it. Null
+// reminder_minutes uses the reminders of the group; an empty list turns
+// reminders off.
+func (h *EventHandler) SetReminders(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	eventID, err := lastPathID(r)
+	if err != nil {
+		http.Error(w, "Invalid event ID", http.StatusBadRequest)
+		return
+	}
+
+	var request struct {
+		ReminderMinutes []int `json:"reminder_minutes"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.eventUsecase.SetEventReminders(eventID, request.ReminderMinutes, userID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// timeQueryParam parses an optional RFC 3339 query parameter.
+func timeQueryParam(r *http.Request, name string) (*time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// dateRangeQueryParams parses the optional from and to query parameters.
+func dateRangeQueryParams(r *http.Request) (*time.Time, *time.Time, error) {
+	from, err := timeQueryParam(r, "from")
+	if err != nil {
+		return nil, nil, errors.New("Invalid from date")
+	}
+	to, err := timeQueryParam(r, "to")
+	if err != nil {
+		return nil, nil, errors.New("Invalid to date")
+	}
+	if from != nil && to != nil && to.Before(*from) {
+		return nil, nil, errors.New("Invalid date range")
+	}
+	return from, to, nil
+}
+
+// eventTypesQueryParam collects the event types given as repeated or comma-separated type parameters.
+func eventTypesQueryParam(r *http.Request) []string {
+	var eventTypes []string
+	for _, value := range r.URL.Query()["type"] {
+		for _, eventType := range strings.Split(value, ",") {
+			if eventType = strings.TrimSpace(eventType); eventType != "" {
+				eventTypes = append(eventTypes, eventType)
+			}
+		}
+	}
+	return eventTypes
+}
+
+// writeEventError reports scheduling conflicts as 409 Conflict with their
+// details, a missing series scope as 400 Bad Request and any other error
+// as a plain server error.
+func writeEventError(w http.ResponseWriter, err error) {
+	var conflictErr *usecases.EventConflictError
+	if errors.As(err, &conflictErr) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error":     conflictErr.Error(),
+			"conflicts": conflictErr.Conflicts,
+		})
+		return
+	}
+	if errors.Is(err, usecases.ErrSeriesScopeRequired) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	fmt.Println("Token successfully saved!") // DEBUG
-	w.Write([]byte("Successfully authorized!"))
+	http.Error(w, err.Error(), http.StatusInternalServerError)
 }
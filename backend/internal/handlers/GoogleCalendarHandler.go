@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"band-manager-backend/internal/services"
+	"band-manager-backend/internal/usecases"
+	"encoding/json"
+	"net/http"
+)
+
+// GoogleCalendarHandler manages linking users' Google calendars.
+type GoogleCalendarHandler struct {
+	calendarSyncUsecase *usecases.CalendarSyncUsecase
+}
+
+func NewGoogleCalendarHandler(gcService *services.GoogleCalendarService) *GoogleCalendarHandler {
+	return &GoogleCalendarHandler{
+		calendarSyncUsecase: usecases.NewCalendarSyncUsecase(gcService),
+	}
+}
+
+// Auth handles GET /api/calendar/auth
+// Returns the Google consent page URL for linking the user's calendar.
+func (h *GoogleCalendarHandler) Auth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	authURL, err := h.calendarSyncUsecase.GetAuthURL(userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"auth_url": authURL})
+}
+
+// Callback handles GET /api/calendar/callback
+// Completes linking the calendar of the user identified by the OAuth state.
+func (h *GoogleCalendarHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	if query.Get("error") != "" {
+		http.Error(w, "Authorization was denied", http.StatusBadRequest)
+		return
+	}
+
+	state := query.Get("state")
+	code := query.Get("code")
+	if state == "" || code == "" {
+		http.Error(w, "State and authorization code are required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.calendarSyncUsecase.LinkCalendar(state, code); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Write([]byte("Google Calendar linked successfully. You can close this page."))
+}
+
+// Status handles GET /api/calendar/status
+// Reports whether the user has linked a Google calendar.
+func (h *GoogleCalendarHandler) Status(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]bool{"linked": h.calendarSyncUsecase.IsLinked(userID)})
+}
+
+// Unlink handles DELETE /api/calendar/unlink
+// Removes the user's Google Calendar link along with the synced event copies.
+func (h *GoogleCalendarHandler) Unlink(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.calendarSyncUsecase.UnlinkCalendar(userID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
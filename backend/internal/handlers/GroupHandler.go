@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"band-manager-backend/internal/config"
 	"band-manager-backend/internal/usecases"
 	"encoding/json"
 	"fmt"
@@ -14,8 +15,8 @@ type GroupHandler struct {
 	groupUsecase *usecases.GroupUsecase
 }
 
-func NewGroupHandler() *GroupHandler {
-	groupUsecase := usecases.NewGroupUsecase()
+func NewGroupHandler(uploads *config.UploadConfig) *GroupHandler {
+	groupUsecase := usecases.NewGroupUsecase(uploads)
 	return &GroupHandler{
 		groupUsecase: groupUsecase,
 	}
@@ -31,10 +32,14 @@ func (h *GroupHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
 	var request struct {
 		Name        string `json:"name"`
 		Description string `json:"description"`
-		UserID      uint   `json:"user_id"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
@@ -42,7 +47,7 @@ func (h *GroupHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	userRole, groupID, err := h.groupUsecase.CreateGroup(request.Name, request.Description, request.UserID)
+	userRole, groupID, err := h.groupUsecase.CreateGroup(request.Name, request.Description, userID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -67,8 +72,12 @@ func (h *GroupHandler) Join(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
 	var request struct {
-		UserID      uint   `json:"user_id"`
 		AccessToken string `json:"access_token"`
 	}
 
@@ -77,7 +86,7 @@ func (h *GroupHandler) Join(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	userRole, groupID, groupName, err := h.groupUsecase.JoinGroup(request.UserID, request.AccessToken)
+	userRole, groupID, groupName, pending, err := h.groupUsecase.JoinGroup(userID, request.AccessToken)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -87,55 +96,93 @@ func (h *GroupHandler) Join(w http.ResponseWriter, r *http.Request) {
 		"role":     userRole,
 		"group_id": groupID,
 		"name":     groupName,
+		"pending":  pending,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
-// GetGroupInfo handles GET /api/group/{groupId}/{userId}
-// Retrieves group details including access token for managers.
+// GetGroupInfo handles GET /api/group/{groupId}
+// Retrieves group details including storage usage and access token for managers.
 func (h *GroupHandler) GetGroupInfo(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
 	pathParts := strings.Split(r.URL.Path, "/")
-	if len(pathParts) < 4 { // /api/group/{groupId}/{userId}
+	if len(pathParts) < 4 { // /api/group/{groupId}
 		http.Error(w, "Invalid URL format", http.StatusBadRequest)
 		return
 	}
 
-	groupID, err := strconv.ParseUint(pathParts[len(pathParts)-2], 10, 64)
+	groupID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
 	if err != nil {
 		http.Error(w, "Invalid group ID", http.StatusBadRequest)
 		return
 	}
 
-	userID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	details, err := h.groupUsecase.GetGroupInfo(userID, uint(groupID))
 	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	name, description, accessToken, err := h.groupUsecase.GetGroupInfo(uint(userID), uint(groupID))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(details)
+}
+
+// UpdateSettings handles PUT /api/group/settings/{groupId}
+// Changes whether joining the group by access token requires approval, the
+// time zone new events are created in and how many minutes before events
+// members are reminded of them. Omitted settings are left unchanged.
+func (h *GroupHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	pathParts := strings.Split(r.URL.Path, "/")
+	groupID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
 		return
 	}
 
-	response := map[string]interface{}{
-		"name":         name,
-		"description":  description,
-		"access_token": accessToken,
+	var request struct {
+		RequireApproval *bool   `json:"require_approval"`
+		TimeZone        *string `json:"time_zone"`
+		ReminderMinutes []int   `json:"reminder_minutes"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.groupUsecase.UpdateSettings(uint(groupID), request.RequireApproval, request.TimeZone, request.ReminderMinutes, userID); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Group settings updated successfully",
+	})
 }
 
-// RefreshAccessToken handles PUT /api/group/refresh-token/{groupId}/{userId}
+// RefreshAccessToken handles PUT /api/group/refresh-token/{groupId}
 // Generates new access token for a group if requester is manager.
 func (h *GroupHandler) RefreshAccessToken(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPut {
@@ -143,25 +190,24 @@ func (h *GroupHandler) RefreshAccessToken(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
 	pathParts := strings.Split(r.URL.Path, "/")
 	if len(pathParts) < 4 {
 		http.Error(w, "Invalid URL format", http.StatusBadRequest)
 		return
 	}
 
-	groupID, err := strconv.ParseUint(pathParts[len(pathParts)-2], 10, 64)
+	groupID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
 	if err != nil {
 		http.Error(w, "Invalid group ID", http.StatusBadRequest)
 		return
 	}
 
-	userID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
-	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
-		return
-	}
-
-	newToken, err := h.groupUsecase.RefreshAccessToken(uint(groupID), uint(userID))
+	newToken, err := h.groupUsecase.RefreshAccessToken(uint(groupID), userID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -174,7 +220,7 @@ func (h *GroupHandler) RefreshAccessToken(w http.ResponseWriter, r *http.Request
 	})
 }
 
-// GetGroupMembers handles GET /api/group/members/{groupId}/{userId}
+// GetGroupMembers handles GET /api/group/members/{groupId}
 // Returns list of group members with their roles.
 func (h *GroupHandler) GetGroupMembers(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -182,25 +228,24 @@ func (h *GroupHandler) GetGroupMembers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
 	pathParts := strings.Split(r.URL.Path, "/")
 	if len(pathParts) < 4 {
 		http.Error(w, "Invalid URL format", http.StatusBadRequest)
 		return
 	}
 
-	groupID, err := strconv.ParseUint(pathParts[len(pathParts)-2], 10, 64)
+	groupID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
 	if err != nil {
 		http.Error(w, "Invalid group ID", http.StatusBadRequest)
 		return
 	}
 
-	userID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
-	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
-		return
-	}
-
-	members, err := h.groupUsecase.GetGroupMembers(uint(groupID), uint(userID))
+	members, err := h.groupUsecase.GetGroupMembers(uint(groupID), userID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -212,22 +257,20 @@ func (h *GroupHandler) GetGroupMembers(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// GetUserGroups handles GET /api/group/user/{userId}
-// Returns all groups a user belongs to.
+// GetUserGroups handles GET /api/group/user
+// Returns all groups the authenticated user belongs to.
 func (h *GroupHandler) GetUserGroups(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	pathParts := strings.Split(r.URL.Path, "/")
-	userID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
-	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
 		return
 	}
 
-	groups, err := h.groupUsecase.GetUserGroups(uint(userID))
+	groups, err := h.groupUsecase.GetUserGroups(userID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -239,7 +282,7 @@ func (h *GroupHandler) GetUserGroups(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// RemoveMember handles DELETE /api/group/remove/{groupId}/{userId}/{requesterId}
+// RemoveMember handles DELETE /api/group/remove/{groupId}/{userId}
 // Removes a member from the group if requester has permissions.
 func (h *GroupHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodDelete {
@@ -247,8 +290,13 @@ func (h *GroupHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	requesterID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
 	pathParts := strings.Split(r.URL.Path, "/")
-	groupID, err := strconv.ParseUint(pathParts[len(pathParts)-3], 10, 64)
+	groupID, err := strconv.ParseUint(pathParts[len(pathParts)-2], 10, 64)
 	if err != nil {
 		http.Error(w, "Invalid group ID", http.StatusBadRequest)
 		return
@@ -260,48 +308,110 @@ func (h *GroupHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	requesterID, err := strconv.ParseUint(pathParts[len(pathParts)-2], 10, 64)
+	err = h.groupUsecase.RemoveMember(uint(groupID), uint(userID), requesterID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Member removed successfully",
+	})
+}
+
+// TransferOwnership handles POST /api/group/transfer/{groupId}/{userId}
+// Makes another member a manager and demotes the requesting manager to moderator.
+func (h *GroupHandler) TransferOwnership(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	requesterID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	pathParts := strings.Split(r.URL.Path, "/")
+	groupID, err := strconv.ParseUint(pathParts[len(pathParts)-2], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
 	if err != nil {
-		http.Error(w, "Invalid requester ID", http.StatusBadRequest)
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
 		return
 	}
 
-	err = h.groupUsecase.RemoveMember(uint(groupID), uint(userID), uint(requesterID))
+	err = h.groupUsecase.TransferOwnership(uint(groupID), uint(userID), requesterID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{
-		"message": "Member removed successfully",
+		"message": "Ownership transferred successfully",
 	})
 }
 
-// UpdateMemberRole handles PUT /api/group/role/{groupId}/{userId}/{requesterId}
-// Updates a member's role in the group.
-func (h *GroupHandler) UpdateMemberRole(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPut {
+// Leave handles POST /api/group/leave/{groupId}
+// Removes the authenticated user from the group.
+func (h *GroupHandler) Leave(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
 	pathParts := strings.Split(r.URL.Path, "/")
-	groupID, err := strconv.ParseUint(pathParts[len(pathParts)-3], 10, 64)
+	groupID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
 	if err != nil {
 		http.Error(w, "Invalid group ID", http.StatusBadRequest)
 		return
 	}
 
-	userID, err := strconv.ParseUint(pathParts[len(pathParts)-2], 10, 64)
+	if err := h.groupUsecase.LeaveGroup(uint(groupID), userID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Left group successfully",
+	})
+}
+
+// UpdateMemberRole handles PUT /api/group/role/{groupId}/{userId}
+// Updates a member's role in the group.
+func (h *GroupHandler) UpdateMemberRole(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	requesterID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	pathParts := strings.Split(r.URL.Path, "/")
+	groupID, err := strconv.ParseUint(pathParts[len(pathParts)-2], 10, 64)
 	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
 		return
 	}
 
-	requesterID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	userID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
 	if err != nil {
-		http.Error(w, "Invalid requester ID", http.StatusBadRequest)
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
 		return
 	}
 
@@ -314,7 +424,7 @@ func (h *GroupHandler) UpdateMemberRole(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	err = h.groupUsecase.UpdateMemberRole(uint(groupID), uint(userID), uint(requesterID), request.NewRole)
+	err = h.groupUsecase.UpdateMemberRole(uint(groupID), uint(userID), requesterID, request.NewRole)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"band-manager-backend/internal/model"
+	"band-manager-backend/internal/usecases"
+	"encoding/json"
+	"net/http"
+)
+
+// InstrumentHandler manages the instrument catalogues of band groups and
+// the instruments members play.
+type InstrumentHandler struct {
+	instrumentUsecase *usecases.InstrumentUsecase
+}
+
+func NewInstrumentHandler() *InstrumentHandler {
+	return &InstrumentHandler{
+		instrumentUsecase: usecases.NewInstrumentUsecase(),
+	}
+}
+
+type instrumentRequest struct {
+	Name          string `json:"name"`
+	Family        string `json:"family"`
+	Transposition string `json:"transposition"`
+	Clef          string `json:"clef"`
+}
+
+// GetGroupInstruments handles GET /api/instrument/group/{groupId}
+// Lists the instrument catalogue of a group with the members playing each instrument.
+func (h *InstrumentHandler) GetGroupInstruments(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	groupID, err := lastPathID(r)
+	if err != nil {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+
+	instruments, err := h.instrumentUsecase.GetGroupInstruments(groupID, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"instruments": instruments,
+	})
+}
+
+// Create handles POST /api/instrument/create/{groupId}
+// Adds an instrument with its family, transposition and clef to the group's catalogue.
+func (h *InstrumentHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	groupID, err := lastPathID(r)
+	if err != nil {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+
+	var request instrumentRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	instrument, err := h.instrumentUsecase.CreateInstrument(groupID, request.Name, request.Family, request.Transposition, request.Clef, userID)
+	if err != nil {
+		instrumentError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(instrument)
+}
+
+// Update handles PUT /api/instrument/update/{instrumentId}
+// Changes an instrument of the catalogue, renaming the parts for it.
+func (h *InstrumentHandler) Update(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	instrumentID, err := lastPathID(r)
+	if err != nil {
+		http.Error(w, "Invalid instrument ID", http.StatusBadRequest)
+		return
+	}
+
+	var request instrumentRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	instrument, err := h.instrumentUsecase.UpdateInstrument(instrumentID, request.Name, request.Family, request.Transposition, request.Clef, userID)
+	if err != nil {
+		instrumentError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(instrument)
+}
+
+// Delete handles DELETE /api/instrument/delete/{instrumentId}
+// Removes an instrument from the catalogue. Parts for it keep its name.
+func (h *InstrumentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	instrumentID, err := lastPathID(r)
+	if err != nil {
+		http.Error(w, "Invalid instrument ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.instrumentUsecase.DeleteInstrument(instrumentID, userID); err != nil {
+		instrumentError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Instrument deleted successfully",
+	})
+}
+
+// Mine handles GET and PUT /api/instrument/mine/{groupId}
+// Returns or replaces the instruments of the group's catalogue the user plays.
+func (h *InstrumentHandler) Mine(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	groupID, err := lastPathID(r)
+	if err != nil {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+
+	var instruments []*model.Instrument
+	if r.Method == http.MethodPut {
+		var request struct {
+			InstrumentIDs []uint `json:"instrument_ids"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		instruments, err = h.instrumentUsecase.SetUserInstruments(groupID, request.InstrumentIDs, userID)
+	} else {
+		instruments, err = h.instrumentUsecase.GetUserInstruments(groupID, userID)
+	}
+	if err != nil {
+		instrumentError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"instruments": instruments,
+	})
+}
+
+// instrumentError answers a request with the error managing instruments.
+func instrumentError(w http.ResponseWriter, err error) {
+	switch err.Error() {
+	case "instrument not found":
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case "access denied", "insufficient permissions":
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case "instrument already exists":
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"band-manager-backend/internal/usecases"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// InvitationHandler manages email invitations to band groups.
+type InvitationHandler struct {
+	invitationUsecase *usecases.InvitationUsecase
+}
+
+func NewInvitationHandler() *InvitationHandler {
+	return &InvitationHandler{
+		invitationUsecase: usecases.NewInvitationUsecase(),
+	}
+}
+
+// Create handles POST /api/invitation/create
+// Invites an email address to a group with a pre-selected role and subgroups.
+func (h *InvitationHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		GroupID     uint   `json:"group_id"`
+		Email       string `json:"email"`
+		Role        string `json:"role"`
+		SubgroupIDs []uint `json:"subgroup_ids"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	invitation, err := h.invitationUsecase.CreateInvitation(request.GroupID, request.Email, request.Role, request.SubgroupIDs, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(invitation)
+}
+
+// GetGroupInvitations handles GET /api/invitation/group/{groupId}
+// Lists the pending invitations of a group.
+func (h *InvitationHandler) GetGroupInvitations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	pathParts := strings.Split(r.URL.Path, "/")
+	groupID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+
+	invitations, err := h.invitationUsecase.GetPendingInvitations(uint(groupID), userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(invitations)
+}
+
+// Revoke handles DELETE /api/invitation/revoke/{invitationId}
+// Invalidates a pending invitation.
+func (h *InvitationHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	pathParts := strings.Split(r.URL.Path, "/")
+	invitationID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid invitation ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.invitationUsecase.RevokeInvitation(uint(invitationID), userID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Invitation revoked successfully",
+	})
+}
+
+// GetInfo handles GET /api/invitation/info?token={token}
+// Describes a pending invitation so the invitee can log in or register.
+func (h *InvitationHandler) GetInfo(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	info, err := h.invitationUsecase.GetInvitation(r.URL.Query().Get("token"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(info)
+}
+
+// Accept handles POST /api/invitation/accept
+// Adds the authenticated user to the group they were invited to.
+func (h *InvitationHandler) Accept(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Token string `json:"token"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	invitation, err := h.invitationUsecase.AcceptInvitation(request.Token, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"group_id": invitation.GroupID,
+		"role":     invitation.Role,
+	})
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"band-manager-backend/internal/usecases"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// JoinRequestHandler manages requests to join groups that require approval.
+type JoinRequestHandler struct {
+	joinRequestUsecase *usecases.JoinRequestUsecase
+}
+
+func NewJoinRequestHandler() *JoinRequestHandler {
+	return &JoinRequestHandler{
+		joinRequestUsecase: usecases.NewJoinRequestUsecase(),
+	}
+}
+
+// GetGroupRequests handles GET /api/join-request/group/{groupId}
+// Lists the pending join requests of a group.
+func (h *JoinRequestHandler) GetGroupRequests(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	pathParts := strings.Split(r.URL.Path, "/")
+	groupID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+
+	requests, err := h.joinRequestUsecase.GetPendingRequests(uint(groupID), userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(requests)
+}
+
+// Approve handles POST /api/join-request/approve/{requestId}
+// Adds the requester to the group with the chosen role and subgroups.
+func (h *JoinRequestHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	pathParts := strings.Split(r.URL.Path, "/")
+	requestID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid request ID", http.StatusBadRequest)
+		return
+	}
+
+	var request struct {
+		Role        string `json:"role"`
+		SubgroupIDs []uint `json:"subgroup_ids"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.joinRequestUsecase.ApproveRequest(uint(requestID), request.Role, request.SubgroupIDs, userID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Join request approved successfully",
+	})
+}
+
+// Reject handles POST /api/join-request/reject/{requestId}
+// Declines a pending join request.
+func (h *JoinRequestHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	pathParts := strings.Split(r.URL.Path, "/")
+	requestID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid request ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.joinRequestUsecase.RejectRequest(uint(requestID), userID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Join request rejected successfully",
+	})
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"band-manager-backend/internal/usecases"
+	"band-manager-backend/internal/usecases/helpers"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// RoleHandler manages the built-in and custom roles of band groups.
+type RoleHandler struct {
+	roleUsecase *usecases.RoleUsecase
+}
+
+func NewRoleHandler() *RoleHandler {
+	return &RoleHandler{
+		roleUsecase: usecases.NewRoleUsecase(),
+	}
+}
+
+type roleRequest struct {
+	Name        string               `json:"name"`
+	Permissions []helpers.Permission `json:"permissions"`
+}
+
+// GetGroupRoles handles GET /api/role/group/{groupId}
+// Lists the roles available in a group together with their permissions.
+func (h *RoleHandler) GetGroupRoles(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	groupID, err := lastPathID(r)
+	if err != nil {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+
+	roles, err := h.roleUsecase.GetGroupRoles(groupID, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(roles)
+}
+
+// Create handles POST /api/role/create/{groupId}
+// Defines a new custom role in the group.
+func (h *RoleHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	groupID, err := lastPathID(r)
+	if err != nil {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+
+	var request roleRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	role, err := h.roleUsecase.CreateRole(groupID, request.Name, request.Permissions, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(role)
+}
+
+// Update handles PUT /api/role/update/{roleId}
+// Renames a custom role or changes its permissions.
+func (h *RoleHandler) Update(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	roleID, err := lastPathID(r)
+	if err != nil {
+		http.Error(w, "Invalid role ID", http.StatusBadRequest)
+		return
+	}
+
+	var request roleRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	role, err := h.roleUsecase.UpdateRole(roleID, request.Name, request.Permissions, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(role)
+}
+
+// Delete handles DELETE /api/role/delete/{roleId}
+// Removes a custom role that is no longer assigned to any member.
+func (h *RoleHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	roleID, err := lastPathID(r)
+	if err != nil {
+		http.Error(w, "Invalid role ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.roleUsecase.DeleteRole(roleID, userID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Role deleted successfully",
+	})
+}
+
+// lastPathID parses the trailing numeric segment of the request path.
+func lastPathID(r *http.Request) (uint, error) {
+	pathParts := strings.Split(r.URL.Path, "/")
+	id, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	return uint(id), err
+}
@@ -0,0 +1,264 @@
+package handlers
+
+import (
+	"band-manager-backend/internal/domain"
+	"band-manager-backend/internal/usecases"
+	"band-manager-backend/internal/usecases/helpers"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// SetlistHandler manages the running order of events.
+type SetlistHandler struct {
+	setlistUsecase *usecases.SetlistUsecase
+}
+
+func NewSetlistHandler() *SetlistHandler {
+	return &SetlistHandler{
+		setlistUsecase: usecases.NewSetlistUsecase(),
+	}
+}
+
+// setlistEntryRequest is the body of requests adding or updating setlist entries.
+type setlistEntryRequest struct {
+	domain.SetlistEntryDetails
+	Position int `json:"position"`
+}
+
+// GetSetlist handles GET /api/setlist/{eventId}
+// Returns the setlist of an event with planned start times and total running time.
+func (h *SetlistHandler) GetSetlist(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	eventID, err := lastPathID(r)
+	if err != nil {
+		http.Error(w, "Invalid event ID", http.StatusBadRequest)
+		return
+	}
+
+	setlist, err := h.setlistUsecase.GetSetlist(eventID, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(setlist)
+}
+
+// AddEntry handles POST /api/setlist/add/{eventId}
+// Adds a track, or a break if track_id is omitted, at the given position of
+// the setlist or at its end.
+func (h *SetlistHandler) AddEntry(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	eventID, err := lastPathID(r)
+	if err != nil {
+		http.Error(w, "Invalid event ID", http.StatusBadRequest)
+		return
+	}
+
+	var request setlistEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	entry, err := h.setlistUsecase.AddEntry(eventID, request.SetlistEntryDetails, request.Position, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(entry)
+}
+
+// UpdateEntry handles PUT /api/setlist/update/{entryId}
+// Replaces the details of a setlist entry.
+func (h *SetlistHandler) UpdateEntry(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	entryID, err := lastPathID(r)
+	if err != nil {
+		http.Error(w, "Invalid entry ID", http.StatusBadRequest)
+		return
+	}
+
+	var request domain.SetlistEntryDetails
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	entry, err := h.setlistUsecase.UpdateEntry(entryID, request, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entry)
+}
+
+// DeleteEntry handles DELETE /api/setlist/delete/{entryId}
+// Removes an entry from the setlist.
+func (h *SetlistHandler) DeleteEntry(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	entryID, err := lastPathID(r)
+	if err != nil {
+		http.Error(w, "Invalid entry ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.setlistUsecase.DeleteEntry(entryID, userID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MoveEntry handles PUT /api/setlist/move/{entryId}
+// Moves an entry to a new position and returns the updated setlist.
+func (h *SetlistHandler) MoveEntry(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	entryID, err := lastPathID(r)
+	if err != nil {
+		http.Error(w, "Invalid entry ID", http.StatusBadRequest)
+		return
+	}
+
+	var request struct {
+		Position int `json:"position"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	setlist, err := h.setlistUsecase.MoveEntry(entryID, request.Position, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(setlist)
+}
+
+// Reorder handles PUT /api/setlist/reorder/{eventId}
+// Puts all entries of the setlist in the given order and returns the updated setlist.
+func (h *SetlistHandler) Reorder(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	eventID, err := lastPathID(r)
+	if err != nil {
+		http.Error(w, "Invalid event ID", http.StatusBadRequest)
+		return
+	}
+
+	var request struct {
+		EntryIDs []uint `json:"entry_ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	setlist, err := h.setlistUsecase.ReorderSetlist(eventID, request.EntryIDs, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(setlist)
+}
+
+// Export handles GET /api/setlist/export/{eventId}?format={txt|csv}
+// Downloads the setlist of an event as plain text (default) or CSV.
+func (h *SetlistHandler) Export(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	eventID, err := lastPathID(r)
+	if err != nil {
+		http.Error(w, "Invalid event ID", http.StatusBadRequest)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = helpers.SetlistFormatText
+	}
+
+	data, contentType, filename, err := h.setlistUsecase.ExportSetlist(eventID, format, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Write(data)
+}
@@ -27,11 +27,15 @@ func (h *SubgroupHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
 	var request struct {
 		GroupID     uint   `json:"group_id"`
 		Name        string `json:"name"`
 		Description string `json:"description"`
-		UserID      uint   `json:"user_id"` // Tymczasowo, później z JWT
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
@@ -39,7 +43,7 @@ func (h *SubgroupHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	subgroup, err := h.subgroupUsecase.CreateSubgroup(request.Name, request.Description, request.GroupID, request.UserID)
+	subgroup, err := h.subgroupUsecase.CreateSubgroup(request.Name, request.Description, request.GroupID, userID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -49,7 +53,7 @@ func (h *SubgroupHandler) Create(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(subgroup)
 }
 
-// GetInfo handles GET /api/subgroup/info/{subgroupId}/{userId}
+// GetInfo handles GET /api/subgroup/info/{subgroupId}
 // Retrieves detailed information about a specific subgroup.
 func (h *SubgroupHandler) GetInfo(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -57,25 +61,24 @@ func (h *SubgroupHandler) GetInfo(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
 	pathParts := strings.Split(r.URL.Path, "/")
 	if len(pathParts) < 4 {
 		http.Error(w, "Invalid URL", http.StatusBadRequest)
 		return
 	}
 
-	id, err := strconv.ParseUint(pathParts[len(pathParts)-2], 10, 64)
+	id, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
 	if err != nil {
 		http.Error(w, "Invalid subgroup ID", http.StatusBadRequest)
 		return
 	}
 
-	userID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
-	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
-		return
-	}
-
-	subgroup, err := h.subgroupUsecase.GetSubgroup(uint(id), uint(userID))
+	subgroup, err := h.subgroupUsecase.GetSubgroup(uint(id), userID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -85,7 +88,7 @@ func (h *SubgroupHandler) GetInfo(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(subgroup)
 }
 
-// Update handles PUT /api/subgroup/update/{subgroupId}/{userId}
+// Update handles PUT /api/subgroup/update/{subgroupId}
 // Updates subgroup details if user has proper permissions.
 func (h *SubgroupHandler) Update(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPut {
@@ -93,24 +96,23 @@ func (h *SubgroupHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
 	pathParts := strings.Split(r.URL.Path, "/")
 	if len(pathParts) < 4 {
 		http.Error(w, "Invalid URL", http.StatusBadRequest)
 		return
 	}
 
-	id, err := strconv.ParseUint(pathParts[len(pathParts)-2], 10, 64)
+	id, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
 	if err != nil {
 		http.Error(w, "Invalid subgroup ID", http.StatusBadRequest)
 		return
 	}
 
-	userID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
-	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
-		return
-	}
-
 	var request struct {
 		Name        string `json:"name"`
 		Description string `json:"description"`
@@ -121,7 +123,7 @@ func (h *SubgroupHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.subgroupUsecase.UpdateSubgroup(uint(id), request.Name, request.Description, uint(userID))
+	err = h.subgroupUsecase.UpdateSubgroup(uint(id), request.Name, request.Description, userID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -133,7 +135,7 @@ func (h *SubgroupHandler) Update(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// Delete handles DELETE /api/subgroup/delete/{subgroupId}/{userId}
+// Delete handles DELETE /api/subgroup/delete/{subgroupId}
 // Removes a subgroup if user has proper permissions.
 func (h *SubgroupHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodDelete {
@@ -141,25 +143,24 @@ func (h *SubgroupHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
 	pathParts := strings.Split(r.URL.Path, "/")
 	if len(pathParts) < 4 {
 		http.Error(w, "Invalid URL", http.StatusBadRequest)
 		return
 	}
 
-	id, err := strconv.ParseUint(pathParts[len(pathParts)-2], 10, 64)
+	id, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
 	if err != nil {
 		http.Error(w, "Invalid subgroup ID", http.StatusBadRequest)
 		return
 	}
 
-	userID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
-	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
-		return
-	}
-
-	err = h.subgroupUsecase.DeleteSubgroup(uint(id), uint(userID))
+	err = h.subgroupUsecase.DeleteSubgroup(uint(id), userID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -171,7 +172,7 @@ func (h *SubgroupHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// AddMembers handles POST /api/subgroup/members/add/{subgroupId}/{userId}
+// AddMembers handles POST /api/subgroup/members/add/{subgroupId}
 // Adds specified users to the subgroup.
 func (h *SubgroupHandler) AddMembers(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -179,24 +180,23 @@ func (h *SubgroupHandler) AddMembers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
 	pathParts := strings.Split(r.URL.Path, "/")
 	if len(pathParts) < 4 {
 		http.Error(w, "Invalid URL", http.StatusBadRequest)
 		return
 	}
 
-	id, err := strconv.ParseUint(pathParts[len(pathParts)-2], 10, 64)
+	id, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
 	if err != nil {
 		http.Error(w, "Invalid subgroup ID", http.StatusBadRequest)
 		return
 	}
 
-	userID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
-	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
-		return
-	}
-
 	var request struct {
 		UserIDs []uint `json:"user_ids"`
 	}
@@ -206,7 +206,7 @@ func (h *SubgroupHandler) AddMembers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.subgroupUsecase.AddMembers(uint(id), request.UserIDs, uint(userID))
+	err = h.subgroupUsecase.AddMembers(uint(id), request.UserIDs, userID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -218,7 +218,7 @@ func (h *SubgroupHandler) AddMembers(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// RemoveMember handles DELETE /api/subgroup/members/remove/{subgroupId}/{memberId}/{requesterId}
+// RemoveMember handles DELETE /api/subgroup/members/remove/{subgroupId}/{memberId}
 // Removes a member from the subgroup.
 func (h *SubgroupHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodDelete {
@@ -226,31 +226,82 @@ func (h *SubgroupHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	requestingUserID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
 	pathParts := strings.Split(r.URL.Path, "/")
 	if len(pathParts) < 5 {
 		http.Error(w, "Invalid URL", http.StatusBadRequest)
 		return
 	}
 
-	subgroupID, err := strconv.ParseUint(pathParts[len(pathParts)-3], 10, 64)
+	subgroupID, err := strconv.ParseUint(pathParts[len(pathParts)-2], 10, 64)
 	if err != nil {
 		http.Error(w, "Invalid subgroup ID", http.StatusBadRequest)
 		return
 	}
 
-	memberID, err := strconv.ParseUint(pathParts[len(pathParts)-2], 10, 64)
+	memberID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
 	if err != nil {
 		http.Error(w, "Invalid member ID", http.StatusBadRequest)
 		return
 	}
 
-	requestingUserID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	err = h.subgroupUsecase.RemoveMember(uint(subgroupID), uint(memberID), requestingUserID)
 	if err != nil {
-		http.Error(w, "Invalid requesting user ID", http.StatusBadRequest)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	err = h.subgroupUsecase.RemoveMember(uint(subgroupID), uint(memberID), uint(requestingUserID))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Member removed successfully",
+	})
+}
+
+// UpdateMemberRole handles PUT /api/subgroup/members/role/{subgroupId}/{memberId}
+// Makes a subgroup member a leader or a regular member.
+func (h *SubgroupHandler) UpdateMemberRole(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	requestingUserID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	pathParts := strings.Split(r.URL.Path, "/")
+	if len(pathParts) < 5 {
+		http.Error(w, "Invalid URL", http.StatusBadRequest)
+		return
+	}
+
+	subgroupID, err := strconv.ParseUint(pathParts[len(pathParts)-2], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid subgroup ID", http.StatusBadRequest)
+		return
+	}
+
+	memberID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid member ID", http.StatusBadRequest)
+		return
+	}
+
+	var request struct {
+		Role string `json:"role"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err = h.subgroupUsecase.UpdateMemberRole(uint(subgroupID), uint(memberID), request.Role, requestingUserID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -258,11 +309,11 @@ func (h *SubgroupHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{
-		"message": "Member removed successfully",
+		"message": "Member role updated successfully",
 	})
 }
 
-// GetGroupSubgroups handles GET /api/subgroup/group/{groupId}/{userId}
+// GetGroupSubgroups handles GET /api/subgroup/group/{groupId}
 // Returns all subgroups in a specific group.
 func (h *SubgroupHandler) GetGroupSubgroups(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -270,15 +321,19 @@ func (h *SubgroupHandler) GetGroupSubgroups(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
 	pathParts := strings.Split(r.URL.Path, "/")
-	groupID, err := strconv.ParseUint(pathParts[len(pathParts)-2], 10, 64)
-	userID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	groupID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
 	if err != nil {
-		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
 		return
 	}
 
-	subgroups, err := h.subgroupUsecase.GetGroupSubgroups(uint(groupID), uint(userID))
+	subgroups, err := h.subgroupUsecase.GetGroupSubgroups(uint(groupID), userID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -290,6 +345,7 @@ func (h *SubgroupHandler) GetGroupSubgroups(w http.ResponseWriter, r *http.Reque
 		Name          string `json:"name"`
 		Description   string `json:"description"`
 		Users         []uint `json:"users"`
+		LeaderIDs     []uint `json:"leader_ids"`
 		Notesheets    []uint `json:"notesheets"`
 		Announcements []uint `json:"announcements"`
 	}
@@ -318,6 +374,7 @@ func (h *SubgroupHandler) GetGroupSubgroups(w http.ResponseWriter, r *http.Reque
 			Name:          subgroup.Name,
 			Description:   subgroup.Description,
 			Users:         userIDs,
+			LeaderIDs:     subgroup.LeaderIDs,
 			Notesheets:    notesheetIDs,
 			Announcements: announcementIDs,
 		})
@@ -1,29 +1,29 @@
 package handlers
 
 import (
+	"band-manager-backend/internal/config"
 	"band-manager-backend/internal/model"
+	"band-manager-backend/internal/services"
 	"band-manager-backend/internal/usecases"
+	"band-manager-backend/internal/usecases/helpers"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
-	"os"
-	"path"
 	"strconv"
 	"strings"
-	"time"
 )
 
-const UPLOAD_DIR = "/app/uploads"
-
 // TrackHandler manages musical track operations.
 type TrackHandler struct {
 	trackUsecase *usecases.TrackUsecase
+	maxFileSize  int64
 }
 
-func NewTrackHandler() *TrackHandler {
+func NewTrackHandler(storage services.Storage, uploads *config.UploadConfig) *TrackHandler {
 	return &TrackHandler{
-		trackUsecase: usecases.NewTrackUsecase(),
+		trackUsecase: usecases.NewTrackUsecase(storage, uploads),
+		maxFileSize:  uploads.MaxFileSize,
 	}
 }
 
@@ -35,11 +35,15 @@ func (h *TrackHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
 	var request struct {
 		Title       string `json:"title"`
 		Description string `json:"description"`
 		GroupID     uint   `json:"group_id"`
-		UserID      uint   `json:"user_id"` // Tymczasowo, później z JWT
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
@@ -51,7 +55,7 @@ func (h *TrackHandler) Create(w http.ResponseWriter, r *http.Request) {
 		request.Title,
 		request.Description,
 		request.GroupID,
-		request.UserID,
+		userID,
 	)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -64,20 +68,25 @@ func (h *TrackHandler) Create(w http.ResponseWriter, r *http.Request) {
 }
 
 // AddNotesheet handles POST /api/track/notesheet
-// Adds a new notesheet to a track for specific subgroups.
+// Adds a new notesheet without a file to a track for specific subgroups, as a
+// part for an instrument of the group's catalogue or a named instrument.
+// Files are only added by uploading them.
 func (h *TrackHandler) AddNotesheet(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
 	var request struct {
-		TrackID     uint   `json:"track_id"`
-		UserID      uint   `json:"user_id"` // typ MIME pliku
-		FileName    string // oryginalna nazwa pliku
-		Filepath    string `json:"filepath"`
-		Instrument  string `json:"instrument"`
-		SubgroupIDs []uint `json:"subgroup_ids"`
+		TrackID      uint   `json:"track_id"`
+		Instrument   string `json:"instrument"`
+		InstrumentID *uint  `json:"instrument_id"`
+		SubgroupIDs  []uint `json:"subgroup_ids"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
@@ -87,10 +96,10 @@ func (h *TrackHandler) AddNotesheet(w http.ResponseWriter, r *http.Request) {
 
 	notesheet, err := h.trackUsecase.AddNotesheet(
 		request.TrackID,
-		"default",
-		request.Filepath,
+		request.InstrumentID,
+		request.Instrument,
 		request.SubgroupIDs,
-		request.UserID,
+		userID,
 	)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -102,28 +111,27 @@ func (h *TrackHandler) AddNotesheet(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(notesheet)
 }
 
-// GetUserNotesheets handles GET /api/track/user/notesheets/{trackId}/{userId}
-// Returns notesheets available to a specific user.
+// GetUserNotesheets handles GET /api/track/user/notesheets/{trackId}
+// Returns the notesheets of a track for the subgroups the user is in and the instruments they play.
 func (h *TrackHandler) GetUserNotesheets(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	pathParts := strings.Split(r.URL.Path, "/")
-	trackID, err := strconv.ParseUint(pathParts[len(pathParts)-2], 10, 64)
-	if err != nil {
-		http.Error(w, "Invalid track ID", http.StatusBadRequest)
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
 		return
 	}
 
-	userID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	pathParts := strings.Split(r.URL.Path, "/")
+	trackID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
 	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		http.Error(w, "Invalid track ID", http.StatusBadRequest)
 		return
 	}
 
-	notesheets, err := h.trackUsecase.GetUserNotesheets(uint(trackID), uint(userID))
+	notesheets, err := h.trackUsecase.GetUserNotesheets(uint(trackID), userID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -135,7 +143,7 @@ func (h *TrackHandler) GetUserNotesheets(w http.ResponseWriter, r *http.Request)
 	})
 }
 
-// GetGroupTracks handles GET /api/track/group/{groupId}/{userId}
+// GetGroupTracks handles GET /api/track/group/{groupId}
 // Returns all tracks in a group.
 func (h *TrackHandler) GetGroupTracks(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -143,16 +151,19 @@ func (h *TrackHandler) GetGroupTracks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pathParts := strings.Split(r.URL.Path, "/")
-	groupID, err := strconv.ParseUint(pathParts[len(pathParts)-2], 10, 64)
-	userID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
 
+	pathParts := strings.Split(r.URL.Path, "/")
+	groupID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
 	if err != nil {
-		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
 		return
 	}
 
-	tracks, err := h.trackUsecase.GetGroupTracks(uint(groupID), uint(userID))
+	tracks, err := h.trackUsecase.GetGroupTracks(uint(groupID), userID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -164,7 +175,7 @@ func (h *TrackHandler) GetGroupTracks(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// GetTrackNotesheets handles GET /api/track/notesheets/{trackId}/{userId}
+// GetTrackNotesheets handles GET /api/track/notesheets/{trackId}
 // Returns all notesheets for a track.
 func (h *TrackHandler) GetTrackNotesheets(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -172,20 +183,19 @@ func (h *TrackHandler) GetTrackNotesheets(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	pathParts := strings.Split(r.URL.Path, "/")
-	trackID, err := strconv.ParseUint(pathParts[len(pathParts)-2], 10, 64)
-	if err != nil {
-		http.Error(w, "Invalid track ID", http.StatusBadRequest)
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
 		return
 	}
 
-	userID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	pathParts := strings.Split(r.URL.Path, "/")
+	trackID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
 	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		http.Error(w, "Invalid track ID", http.StatusBadRequest)
 		return
 	}
 
-	notesheets, err := h.trackUsecase.GetTrackNotesheets(uint(trackID), uint(userID))
+	notesheets, err := h.trackUsecase.GetTrackNotesheets(uint(trackID), userID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -197,61 +207,92 @@ func (h *TrackHandler) GetTrackNotesheets(w http.ResponseWriter, r *http.Request
 	})
 }
 
-// UploadNotesheetFile handles POST /api/track/notesheet/upload/{notesheetId}/{userId}
-// Handles file upload for a notesheet.
-func (h *TrackHandler) UploadNotesheetFile(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
+// SetNotesheetInstrument handles PUT /api/track/notesheet/instrument/{notesheetId}
+// Changes which instrument of the group's catalogue, or named instrument, a notesheet is a part for.
+func (h *TrackHandler) SetNotesheetInstrument(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	pathParts := strings.Split(r.URL.Path, "/")
-	notesheetID, err := strconv.ParseUint(pathParts[len(pathParts)-2], 10, 64)
-	if err != nil {
-		http.Error(w, "Invalid notesheet ID", http.StatusBadRequest)
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
 		return
 	}
 
-	userID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	notesheetID, err := lastPathID(r)
 	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		http.Error(w, "Invalid notesheet ID", http.StatusBadRequest)
 		return
 	}
 
-	r.ParseMultipartForm(10 << 20) // 10MB limit
+	var request struct {
+		Instrument   string `json:"instrument"`
+		InstrumentID *uint  `json:"instrument_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
 
-	file, handler, err := r.FormFile("file")
+	notesheet, err := h.trackUsecase.SetNotesheetInstrument(notesheetID, request.InstrumentID, request.Instrument, userID)
 	if err != nil {
-		http.Error(w, "Error retrieving file", http.StatusBadRequest)
+		switch err.Error() {
+		case "notesheet not found":
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case "access denied", "insufficient permissions":
+			http.Error(w, err.Error(), http.StatusForbidden)
+		default:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
 		return
 	}
-	defer file.Close()
 
-	filename := fmt.Sprintf("%d_%s", time.Now().UnixNano(), handler.Filename)
-	filepath := path.Join(UPLOAD_DIR, filename)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(notesheet)
+}
+
+// UploadNotesheetFile handles POST /api/track/notesheet/upload/{notesheetId}
+// Uploads a new version of the file of a notesheet, described by the
+// optional change_note form field.
+func (h *TrackHandler) UploadNotesheetFile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
 
-	if err := os.MkdirAll(UPLOAD_DIR, 0755); err != nil {
-		http.Error(w, "Server error", http.StatusInternalServerError)
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
 		return
 	}
 
-	dst, err := os.Create(filepath)
+	pathParts := strings.Split(r.URL.Path, "/")
+	notesheetID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
 	if err != nil {
-		http.Error(w, "Error creating file", http.StatusInternalServerError)
+		http.Error(w, "Invalid notesheet ID", http.StatusBadRequest)
 		return
 	}
-	defer dst.Close()
 
-	if _, err := io.Copy(dst, file); err != nil {
-		http.Error(w, "Error saving file", http.StatusInternalServerError)
+	if !h.parseUploadForm(w, r) {
 		return
 	}
 
-	notesheet, err := h.trackUsecase.UpdateNotesheetFilepath(uint(notesheetID), uint(userID), filename)
+	file, handler, err := r.FormFile("file")
 	if err != nil {
+		http.Error(w, "Error retrieving file", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
 
-		os.Remove(filepath)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	notesheet, err := h.trackUsecase.UploadNotesheetFile(
+		uint(notesheetID),
+		userID,
+		handler.Filename,
+		file,
+		r.FormValue("change_note"),
+	)
+	if err != nil {
+		uploadError(w, err)
 		return
 	}
 
@@ -259,7 +300,7 @@ func (h *TrackHandler) UploadNotesheetFile(w http.ResponseWriter, r *http.Reques
 	json.NewEncoder(w).Encode(notesheet)
 }
 
-// DownloadNotesheetFile handles GET /api/track/notesheet/file/{notesheetId}/{userId}
+// DownloadNotesheetFile handles GET /api/track/notesheet/file/{notesheetId}
 // Serves notesheet file download.
 func (h *TrackHandler) DownloadNotesheetFile(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -267,50 +308,185 @@ func (h *TrackHandler) DownloadNotesheetFile(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
 	pathParts := strings.Split(r.URL.Path, "/")
-	notesheetID, err := strconv.ParseUint(pathParts[len(pathParts)-2], 10, 64)
+	notesheetID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
 	if err != nil {
 		http.Error(w, "Invalid notesheet ID", http.StatusBadRequest)
 		return
 	}
 
-	userID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	object, name, err := h.trackUsecase.OpenNotesheetFile(uint(notesheetID), userID)
+	serveNotesheetFile(w, r, object, name, err)
+}
+
+// GetNotesheetVersions handles GET /api/track/notesheet/versions/{notesheetId}
+// Returns the versions of the file of a notesheet, newest first, marking the current one.
+func (h *TrackHandler) GetNotesheetVersions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	notesheetID, err := lastPathID(r)
 	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		http.Error(w, "Invalid notesheet ID", http.StatusBadRequest)
 		return
 	}
 
-	notesheet, err := h.trackUsecase.GetNotesheet(uint(notesheetID), uint(userID))
+	versions, err := h.trackUsecase.GetNotesheetVersions(notesheetID, userID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	if notesheet.Filepath == "" {
-		http.Error(w, "No file uploaded for this notesheet", http.StatusNotFound)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"versions": versions,
+	})
+}
+
+// DownloadNotesheetVersion handles GET /api/track/notesheet/version/file/{versionId}
+// Serves the file of a specific version of a notesheet.
+func (h *TrackHandler) DownloadNotesheetVersion(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	versionID, err := lastPathID(r)
+	if err != nil {
+		http.Error(w, "Invalid version ID", http.StatusBadRequest)
+		return
+	}
+
+	object, name, err := h.trackUsecase.OpenNotesheetVersionFile(versionID, userID)
+	serveNotesheetFile(w, r, object, name, err)
+}
+
+// RestoreNotesheetVersion handles POST /api/track/notesheet/version/restore/{versionId}
+// Makes a previous version of the file of a notesheet the current one again.
+func (h *TrackHandler) RestoreNotesheetVersion(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
 		return
 	}
 
-	filepath := path.Join(UPLOAD_DIR, notesheet.Filepath)
+	versionID, err := lastPathID(r)
+	if err != nil {
+		http.Error(w, "Invalid version ID", http.StatusBadRequest)
+		return
+	}
+
+	notesheet, err := h.trackUsecase.RestoreNotesheetVersion(versionID, userID)
+	if err != nil {
+		switch err.Error() {
+		case "version not found":
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case "access denied", "insufficient permissions":
+			http.Error(w, err.Error(), http.StatusForbidden)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
 
-	if _, err := os.Stat(filepath); os.IsNotExist(err) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(notesheet)
+}
+
+// serveNotesheetFile serves an opened notesheet file, answering range
+// requests, or the error opening it.
+func serveNotesheetFile(w http.ResponseWriter, r *http.Request, object *services.ObjectReader, name string, err error) {
+	if errors.Is(err, services.ErrObjectNotFound) {
 		http.Error(w, "File not found", http.StatusNotFound)
 		return
 	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer object.Close()
+
+	if object.Info.ContentType != "" {
+		w.Header().Set("Content-Type", object.Info.ContentType)
+	}
+	http.ServeContent(w, r, name, object.Info.ModTime, object)
+}
+
+// uploadFormOverhead is how much larger than the file an upload form may
+// be, leaving room for the other form fields.
+const uploadFormOverhead = 1 << 20
+
+// parseUploadForm parses a multipart form with a file upload, answering
+// requests with malformed or too large forms with an error.
+func (h *TrackHandler) parseUploadForm(w http.ResponseWriter, r *http.Request) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxFileSize+uploadFormOverhead)
 
-	http.ServeFile(w, r, filepath)
+	err := r.ParseMultipartForm(10 << 20)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(w, fmt.Sprintf("File is too large, files may be at most %d MB", h.maxFileSize>>20), http.StatusRequestEntityTooLarge)
+		return false
+	}
+	if err != nil {
+		http.Error(w, "Error parsing form", http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// uploadError answers an upload with the error storing the file.
+func uploadError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, helpers.ErrEmptyFile):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, helpers.ErrUnsupportedFileFormat):
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+	case errors.Is(err, usecases.ErrFileTooLarge), errors.Is(err, usecases.ErrStorageQuotaExceeded):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+	case err.Error() == "access denied" || err.Error() == "insufficient permissions":
+		http.Error(w, err.Error(), http.StatusForbidden)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
 }
 
 // CreateNotesheetWithFile handles POST /api/track/notesheet/create
-// Creates a new notesheet with file upload in one operation.
+// Creates a new notesheet with file upload in one operation. The file becomes
+// the first version, described by the optional change_note form field. The
+// instrument is given by the instrument_id or instrument form field.
 func (h *TrackHandler) CreateNotesheetWithFile(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	if err := r.ParseMultipartForm(10 << 20); err != nil { // Poprawiony błąd składni
-		http.Error(w, "Error parsing form", http.StatusBadRequest)
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	if !h.parseUploadForm(w, r) {
 		return
 	}
 
@@ -327,12 +503,6 @@ func (h *TrackHandler) CreateNotesheetWithFile(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	userID, err := strconv.ParseUint(r.FormValue("user_id"), 10, 64)
-	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
-		return
-	}
-
 	var subgroupIDs []uint
 	if subgroupIDsStr := r.FormValue("subgroup_ids"); subgroupIDsStr != "" {
 		if err := json.Unmarshal([]byte(subgroupIDsStr), &subgroupIDs); err != nil {
@@ -341,38 +511,29 @@ func (h *TrackHandler) CreateNotesheetWithFile(w http.ResponseWriter, r *http.Re
 		}
 	}
 
-	filename := fmt.Sprintf("%d_%s", time.Now().UnixNano(), handler.Filename)
-	filepath := path.Join(UPLOAD_DIR, filename)
-
-	if err := os.MkdirAll(UPLOAD_DIR, 0755); err != nil {
-		http.Error(w, "Server error", http.StatusInternalServerError)
-		return
-	}
-
-	dst, err := os.Create(filepath)
-	if err != nil {
-		http.Error(w, "Error creating file", http.StatusInternalServerError)
-		return
-	}
-	defer dst.Close()
-
-	if _, err := io.Copy(dst, file); err != nil {
-		os.Remove(filepath)
-		http.Error(w, "Error saving file", http.StatusInternalServerError)
-		return
+	var instrumentID *uint
+	if instrumentIDStr := r.FormValue("instrument_id"); instrumentIDStr != "" {
+		id, err := strconv.ParseUint(instrumentIDStr, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid instrument ID", http.StatusBadRequest)
+			return
+		}
+		catalogueID := uint(id)
+		instrumentID = &catalogueID
 	}
 
-	notesheet, err := h.trackUsecase.AddNotesheet(
+	notesheet, err := h.trackUsecase.CreateNotesheetWithFile(
 		uint(trackID),
-		handler.Header.Get("Content-Type"),
-		filename,
+		instrumentID,
+		r.FormValue("instrument"),
 		subgroupIDs,
-		uint(userID),
+		userID,
+		handler.Filename,
+		file,
+		r.FormValue("change_note"),
 	)
-
 	if err != nil {
-		os.Remove(filepath)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		uploadError(w, err)
 		return
 	}
 
@@ -381,7 +542,7 @@ func (h *TrackHandler) CreateNotesheetWithFile(w http.ResponseWriter, r *http.Re
 	json.NewEncoder(w).Encode(notesheet)
 }
 
-// DeleteTrack handles DELETE /api/track/delete/{trackId}/{userId}
+// DeleteTrack handles DELETE /api/track/delete/{trackId}
 // Removes a track and its associated resources.
 func (h *TrackHandler) DeleteTrack(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodDelete {
@@ -389,20 +550,19 @@ func (h *TrackHandler) DeleteTrack(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pathParts := strings.Split(r.URL.Path, "/")
-	trackID, err := strconv.ParseUint(pathParts[len(pathParts)-2], 10, 64)
-	if err != nil {
-		http.Error(w, "Invalid track ID", http.StatusBadRequest)
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
 		return
 	}
 
-	userID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
+	pathParts := strings.Split(r.URL.Path, "/")
+	trackID, err := strconv.ParseUint(pathParts[len(pathParts)-1], 10, 64)
 	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		http.Error(w, "Invalid track ID", http.StatusBadRequest)
 		return
 	}
 
-	err = h.trackUsecase.DeleteTrack(uint(trackID), uint(userID))
+	err = h.trackUsecase.DeleteTrack(uint(trackID), userID)
 	if err != nil {
 		if err.Error() == "access denied" || err.Error() == "insufficient permissions" {
 			http.Error(w, err.Error(), http.StatusForbidden)
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"band-manager-backend/internal/usecases"
+	"encoding/json"
+	"net/http"
+)
+
+// UserHandler manages the preferences of the authenticated user.
+type UserHandler struct {
+	userUsecase *usecases.UserUsecase
+}
+
+func NewUserHandler() *UserHandler {
+	return &UserHandler{
+		userUsecase: usecases.NewUserUsecase(),
+	}
+}
+
+// Preferences handles GET and PUT /api/user/preferences
+// Returns or changes whether the user gets event reminders. Omitted
+// preferences are left unchanged.
+func (h *UserHandler) Preferences(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	if r.Method == http.MethodPut {
+		var request struct {
+			EventReminders *bool `json:"event_reminders"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		if request.EventReminders != nil {
+			if err := h.userUsecase.SetEventReminders(userID, *request.EventReminders); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+	}
+
+	eventReminders, err := h.userUsecase.GetEventReminders(userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]bool{
+		"event_reminders": eventReminders,
+	})
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"band-manager-backend/internal/usecases/helpers"
+	"context"
+	"net"
+	"net/http"
+)
+
+type contextKey string
+
+const (
+	userIDKey    contextKey = "userID"
+	sessionIDKey contextKey = "sessionID"
+)
+
+// WithUserID returns a copy of ctx carrying the authenticated user's ID.
+func WithUserID(ctx context.Context, userID uint) context.Context {
+	return context.WithValue(ctx, userIDKey, userID)
+}
+
+// UserIDFromContext returns the authenticated user's ID stored by the auth middleware.
+func UserIDFromContext(ctx context.Context) (uint, bool) {
+	userID, ok := ctx.Value(userIDKey).(uint)
+	return userID, ok
+}
+
+// WithSessionID returns a copy of ctx carrying the ID of the caller's session.
+func WithSessionID(ctx context.Context, sessionID uint) context.Context {
+	return context.WithValue(ctx, sessionIDKey, sessionID)
+}
+
+// SessionIDFromContext returns the caller's session ID stored by the auth middleware.
+func SessionIDFromContext(ctx context.Context) (uint, bool) {
+	sessionID, ok := ctx.Value(sessionIDKey).(uint)
+	return sessionID, ok
+}
+
+// authenticatedUserID reads the caller's ID from the request context
+// and writes 401 Unauthorized if the request was not authenticated.
+func authenticatedUserID(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return 0, false
+	}
+	return userID, true
+}
+
+// authenticatedSession reads the caller's user and session IDs from the request
+// context and writes 401 Unauthorized if the request was not authenticated.
+func authenticatedSession(w http.ResponseWriter, r *http.Request) (uint, uint, bool) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return 0, 0, false
+	}
+	sessionID, ok := SessionIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return 0, 0, false
+	}
+	return userID, sessionID, true
+}
+
+// clientIP returns the address of the client, honouring X-Forwarded-For
+// only for requests from the trusted proxies.
+func clientIP(r *http.Request, trustedProxies []*net.IPNet) string {
+	return helpers.ClientIP(r.RemoteAddr, r.Header.Get("X-Forwarded-For"), trustedProxies)
+}
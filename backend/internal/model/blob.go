@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// Blob is an uploaded file stored once under a key derived from the SHA-256
+// of its content, however many notesheets reference it. RefCount counts the
+// notesheets and notesheet versions referencing the blob's key; blobs left
+// without references are removed by garbage collection.
+type Blob struct {
+	Hash        string    `gorm:"primarykey;size:64" json:"hash"`
+	Key         string    `gorm:"not null;uniqueIndex" json:"key"`
+	Size        int64     `gorm:"not null" json:"size"`
+	ContentType string    `json:"content_type"`
+	RefCount    int       `gorm:"not null;default:0" json:"ref_count"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// CalendarFeed represents a token-protected iCalendar subscription of a user,
+// either to all of the user's events or to the events of one group.
+type CalendarFeed struct {
+	ID        uint       `gorm:"primarykey" json:"id"`
+	UserID    uint       `gorm:"not null;index" json:"user_id"`
+	GroupID   *uint      `gorm:"index" json:"group_id"`
+	TokenHash string     `gorm:"uniqueIndex;not null" json:"-"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	User      User       `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Group     *Group     `gorm:"foreignKey:GroupID;constraint:OnDelete:CASCADE" json:"-"`
+}
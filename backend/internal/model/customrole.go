@@ -0,0 +1,12 @@
+package model
+
+import "github.com/lib/pq"
+
+// CustomRole represents a group-defined role granting a chosen set of permissions.
+type CustomRole struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	GroupID     uint           `gorm:"not null;uniqueIndex:idx_custom_role_group_name" json:"group_id"`
+	Name        string         `gorm:"not null;uniqueIndex:idx_custom_role_group_name" json:"name"`
+	Permissions pq.StringArray `gorm:"type:text[]" json:"permissions"`
+	Group       Group          `gorm:"foreignKey:GroupID;constraint:OnDelete:CASCADE" json:"-"`
+}
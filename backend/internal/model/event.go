@@ -1,18 +1,37 @@
 package model
 
-import "time"
+import (
+	"time"
 
-// Event represents a musical event or rehearsal.
+	"github.com/lib/pq"
+)
+
+// Event represents a musical event or rehearsal. Date is the start of the
+// event; all-day events start and end at midnight of their first and last day
+// in the event's time zone. Events without their own reminder minutes use
+// those of their group.
 type Event struct {
-	ID                  uint                `gorm:"primarykey" json:"id"`
-	Title               string              `gorm:"not null" json:"title"`
-	Location            string              `gorm:"not null" json:"location"`
-	Description         string              `json:"description"`
-	Date                time.Time           `json:"date"`
-	GroupID             uint                `gorm:"not null" json:"group_id"`
-	Group               Group               `gorm:"foreignKey:GroupID" json:"group"`
-	Tracks              []*Track            `gorm:"many2many:event_tracks;constraint:OnDelete:CASCADE" json:"tracks"`
-	Users               []*User             `gorm:"many2many:event_users;constraint:OnDelete:CASCADE" json:"users"`
-	Performances        []Performance       `gorm:"constraint:OnDelete:CASCADE" json:"performances"`
-	GoogleCalendarEvent GoogleCalendarEvent `gorm:"constraint:OnDelete:CASCADE" json:"google_calendar_event,omitempty"`
+	ID                   uint                  `gorm:"primarykey" json:"id"`
+	Title                string                `gorm:"not null" json:"title"`
+	Location             string                `gorm:"not null" json:"location"`
+	Description          string                `json:"description"`
+	Date                 time.Time             `json:"date"`
+	EndDate              time.Time             `json:"end_date"`
+	AllDay               bool                  `gorm:"not null;default:false" json:"all_day"`
+	TimeZone             string                `gorm:"not null;default:'Europe/Warsaw'" json:"time_zone"`
+	Type                 string                `gorm:"not null;default:'rehearsal';index" json:"type"`
+	RecurrenceRule       string                `json:"recurrence_rule"`
+	SeriesID             *uint                 `gorm:"index" json:"series_id"`
+	OriginalDate         *time.Time            `json:"original_date"`
+	OccurrenceDate       *time.Time            `gorm:"-" json:"occurrence_date,omitempty"`
+	ReminderMinutes      pq.Int64Array         `gorm:"type:bigint[]" json:"reminder_minutes"`
+	GroupID              uint                  `gorm:"not null" json:"group_id"`
+	Group                Group                 `gorm:"foreignKey:GroupID" json:"group"`
+	Tracks               []*Track              `gorm:"many2many:event_tracks;constraint:OnDelete:CASCADE" json:"tracks"`
+	Users                []*User               `gorm:"many2many:event_users;constraint:OnDelete:CASCADE" json:"users"`
+	Performances         []Performance         `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	SubgroupNotes        []EventSubgroupNote   `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	GoogleCalendarEvents []GoogleCalendarEvent `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	Exceptions           []EventException      `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	Series               *Event                `gorm:"foreignKey:SeriesID;constraint:OnDelete:CASCADE" json:"-"`
 }
@@ -0,0 +1,10 @@
+package model
+
+import "time"
+
+// EventException marks an occurrence of a recurring event that was cancelled or edited separately.
+type EventException struct {
+	ID             uint      `gorm:"primarykey" json:"id"`
+	EventID        uint      `gorm:"not null;uniqueIndex:idx_event_exception" json:"event_id"`
+	OccurrenceDate time.Time `gorm:"not null;uniqueIndex:idx_event_exception" json:"occurrence_date"`
+}
@@ -0,0 +1,11 @@
+package model
+
+// EventSubgroupNote holds instructions for one subgroup for an event, such as
+// dress code or call time, printed in the event programme.
+type EventSubgroupNote struct {
+	ID         uint     `gorm:"primarykey" json:"id"`
+	EventID    uint     `gorm:"not null;uniqueIndex:idx_event_subgroup_note" json:"event_id"`
+	SubgroupID uint     `gorm:"not null;uniqueIndex:idx_event_subgroup_note" json:"subgroup_id"`
+	Notes      string   `gorm:"not null" json:"notes"`
+	Subgroup   Subgroup `gorm:"foreignKey:SubgroupID;constraint:OnDelete:CASCADE" json:"-"`
+}
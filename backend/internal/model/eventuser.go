@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// EventUser represents a user assigned to an event and their attendance response.
+type EventUser struct {
+	EventID           uint       `gorm:"primarykey" json:"event_id"`
+	UserID            uint       `gorm:"primarykey" json:"user_id"`
+	RSVPStatus        string     `gorm:"not null;default:unanswered" json:"rsvp_status"`
+	RSVPComment       string     `json:"rsvp_comment"`
+	RespondedAt       *time.Time `json:"responded_at"`
+	ResponseTokenHash string     `gorm:"index" json:"-"`
+}
+
+// TableName keeps the join table name used by the Event.Users association.
+func (EventUser) TableName() string {
+	return "event_users"
+}
@@ -2,10 +2,12 @@ package model
 
 import "time"
 
-// GoogleCalendarEvent represents synced Google Calendar event data.
+// GoogleCalendarEvent links an event to its copy in the Google calendar of
+// one attendee. CalendarID is the ID Google assigned to the copy.
 type GoogleCalendarEvent struct {
 	ID         uint      `gorm:"primarykey" json:"id"`
-	EventID    uint      `gorm:"not null;uniqueIndex" json:"event_id"`
+	EventID    uint      `gorm:"not null;uniqueIndex:idx_google_calendar_event_user" json:"event_id"`
+	UserID     uint      `gorm:"uniqueIndex:idx_google_calendar_event_user" json:"user_id"`
 	CalendarID string    `gorm:"not null" json:"calendar_id"`
 	LastSynced time.Time `json:"last_synced"`
 }
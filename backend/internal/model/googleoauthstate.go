@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// GoogleOAuthState stores a hashed single-use OAuth state nonce, tying a
+// Google authorization callback to the user who started it.
+type GoogleOAuthState struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	UserID    uint      `gorm:"not null;index" json:"user_id"`
+	StateHash string    `gorm:"unique;not null" json:"-"`
+	ExpiresAt time.Time `gorm:"not null" json:"expires_at"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	User      User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
@@ -2,7 +2,7 @@ package model
 
 import "time"
 
-// GoogleToken stores Google OAuth tokens for calendar integration.
+// GoogleToken stores the Google OAuth token of a user who linked their calendar.
 type GoogleToken struct {
 	ID           uint      `gorm:"primarykey"`
 	UserID       uint      `gorm:"uniqueIndex"`
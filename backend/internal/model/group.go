@@ -1,15 +1,21 @@
 package model
 
-// Group represents a band or musical organization.
+import "github.com/lib/pq"
+
+// Group represents a band or musical organization. Members are reminded of
+// its events the given numbers of minutes before they start.
 type Group struct {
-	ID            uint            `gorm:"primarykey" json:"id"`
-	Name          string          `gorm:"not null" json:"name"`
-	AccessToken   string          `gorm:"unique;not null" json:"access_token"`
-	Description   string          `json:"description"`
-	Users         []*User         `gorm:"many2many:user_group;constraint:OnDelete:CASCADE" json:"users"`
-	Subgroups     []Subgroup      `gorm:"constraint:OnDelete:CASCADE" json:"subgroups"`
-	Announcements []Announcement  `gorm:"constraint:OnDelete:CASCADE" json:"announcements"`
-	Events        []Event         `gorm:"constraint:OnDelete:CASCADE" json:"events"`
-	Tracks        []Track         `gorm:"constraint:OnDelete:CASCADE" json:"tracks"`
-	UserRoles     []UserGroupRole `gorm:"foreignKey:GroupID;constraint:OnDelete:CASCADE" json:"user_roles"`
+	ID              uint            `gorm:"primarykey" json:"id"`
+	Name            string          `gorm:"not null" json:"name"`
+	AccessToken     string          `gorm:"unique;not null" json:"access_token"`
+	Description     string          `json:"description"`
+	RequireApproval bool            `gorm:"not null;default:false" json:"require_approval"`
+	TimeZone        string          `gorm:"not null;default:'Europe/Warsaw'" json:"time_zone"`
+	ReminderMinutes pq.Int64Array   `gorm:"type:bigint[];default:'{1440}'" json:"reminder_minutes"`
+	Users           []*User         `gorm:"many2many:user_group;constraint:OnDelete:CASCADE" json:"users"`
+	Subgroups       []Subgroup      `gorm:"constraint:OnDelete:CASCADE" json:"subgroups"`
+	Announcements   []Announcement  `gorm:"constraint:OnDelete:CASCADE" json:"announcements"`
+	Events          []Event         `gorm:"constraint:OnDelete:CASCADE" json:"events"`
+	Tracks          []Track         `gorm:"constraint:OnDelete:CASCADE" json:"tracks"`
+	UserRoles       []UserGroupRole `gorm:"foreignKey:GroupID;constraint:OnDelete:CASCADE" json:"user_roles"`
 }
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// GroupInvitation represents a single-use invitation for an email address to join a group.
+type GroupInvitation struct {
+	ID          uint          `gorm:"primarykey" json:"id"`
+	GroupID     uint          `gorm:"not null;index" json:"group_id"`
+	Email       string        `gorm:"not null" json:"email"`
+	Role        string        `gorm:"not null" json:"role"`
+	SubgroupIDs pq.Int64Array `gorm:"type:bigint[]" json:"subgroup_ids"`
+	InvitedByID uint          `gorm:"not null" json:"invited_by_id"`
+	TokenHash   string        `gorm:"uniqueIndex;not null" json:"-"`
+	ExpiresAt   time.Time     `gorm:"not null" json:"expires_at"`
+	AcceptedAt  *time.Time    `json:"accepted_at"`
+	AcceptedBy  *uint         `json:"accepted_by"`
+	RevokedAt   *time.Time    `json:"revoked_at"`
+	CreatedAt   time.Time     `json:"created_at"`
+	Group       Group         `gorm:"foreignKey:GroupID;constraint:OnDelete:CASCADE" json:"-"`
+	InvitedBy   User          `gorm:"foreignKey:InvitedByID;constraint:OnDelete:CASCADE" json:"-"`
+}
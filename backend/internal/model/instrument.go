@@ -0,0 +1,26 @@
+package model
+
+// Instrument is an entry of a group's instrument catalogue. Notesheets are
+// parts for an instrument and members declare which instruments they play.
+type Instrument struct {
+	ID            uint    `gorm:"primarykey" json:"id"`
+	GroupID       uint    `gorm:"not null;uniqueIndex:idx_instrument_group_name" json:"group_id"`
+	Name          string  `gorm:"not null;uniqueIndex:idx_instrument_group_name" json:"name"`
+	Family        string  `gorm:"not null" json:"family"`
+	Transposition string  `gorm:"not null;default:C" json:"transposition"`
+	Clef          string  `gorm:"not null" json:"clef"`
+	Group         Group   `gorm:"foreignKey:GroupID;constraint:OnDelete:CASCADE" json:"-"`
+	Players       []*User `gorm:"many2many:instrument_user;constraint:OnDelete:CASCADE" json:"-"`
+	PlayerIDs     []uint  `gorm:"-" json:"player_ids"`
+}
+
+// InstrumentUser records that a member plays an instrument.
+type InstrumentUser struct {
+	InstrumentID uint `gorm:"primarykey" json:"instrument_id"`
+	UserID       uint `gorm:"primarykey" json:"user_id"`
+}
+
+// TableName keeps the join table name used by the Instrument.Players association.
+func (InstrumentUser) TableName() string {
+	return "instrument_user"
+}
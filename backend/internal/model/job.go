@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// Job is a unit of background work, such as sending an email, that is
+// retried until it succeeds or runs out of attempts. Jobs with the same
+// idempotency key are only enqueued once.
+type Job struct {
+	ID             uint       `gorm:"primarykey" json:"id"`
+	Type           string     `gorm:"not null;index" json:"type"`
+	Payload        string     `gorm:"type:jsonb;not null" json:"payload"`
+	Status         string     `gorm:"not null;default:'pending';index:idx_job_status_run_at" json:"status"`
+	Attempts       int        `gorm:"not null;default:0" json:"attempts"`
+	MaxAttempts    int        `gorm:"not null" json:"max_attempts"`
+	RunAt          time.Time  `gorm:"not null;index:idx_job_status_run_at" json:"run_at"`
+	LockedAt       *time.Time `json:"locked_at"`
+	LockedBy       string     `json:"locked_by"`
+	LastError      string     `json:"last_error"`
+	IdempotencyKey *string    `gorm:"uniqueIndex" json:"idempotency_key"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	CompletedAt    *time.Time `json:"completed_at"`
+}
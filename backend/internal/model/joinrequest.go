@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// JoinRequest represents a user's request to join a group that requires approval.
+type JoinRequest struct {
+	ID          uint       `gorm:"primarykey" json:"id"`
+	GroupID     uint       `gorm:"not null;index" json:"group_id"`
+	UserID      uint       `gorm:"not null;index" json:"user_id"`
+	Status      string     `gorm:"not null;default:pending" json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	DecidedAt   *time.Time `json:"decided_at"`
+	DecidedByID *uint      `json:"decided_by_id"`
+	Group       Group      `gorm:"foreignKey:GroupID;constraint:OnDelete:CASCADE" json:"-"`
+	User        User       `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
@@ -1,13 +1,19 @@
 package model
 
-// Notesheet represents sheet music associated with a track.
+// Notesheet represents sheet music associated with a track. Its file is
+// that of CurrentVersion, 0 while no file was uploaded. A part for an
+// instrument of the group's catalogue references it by InstrumentID and
+// carries its name in Instrument.
 type Notesheet struct {
-	ID         uint        `gorm:"primarykey" json:"id"`
-	Filepath   string      `gorm:"not null" json:"filepath"`
-	Instrument string      `gorm:"not null" json:"instrument"`
-	TrackId    uint        `gorm:"not null;" json:"track_id"`
-	Subgroups  []*Subgroup `gorm:"many2many:notesheet_subgroup;constraint:OnDelete:CASCADE" json:"subgroups"`
-	Track      Track       `gorm:"foreignKey:TrackId;constraint:OnDelete:CASCADE" json:"track"`
-	FileType   string      `json:"file_type"`
-	FileName   string      `json:"file_name"`
+	ID                  uint        `gorm:"primarykey" json:"id"`
+	Filepath            string      `gorm:"not null" json:"filepath"`
+	Instrument          string      `gorm:"not null" json:"instrument"`
+	InstrumentID        *uint       `gorm:"index" json:"instrument_id"`
+	CatalogueInstrument *Instrument `gorm:"foreignKey:InstrumentID;constraint:OnDelete:SET NULL" json:"catalogue_instrument,omitempty"`
+	TrackId             uint        `gorm:"not null;" json:"track_id"`
+	Subgroups           []*Subgroup `gorm:"many2many:notesheet_subgroup;constraint:OnDelete:CASCADE" json:"subgroups"`
+	Track               Track       `gorm:"foreignKey:TrackId;constraint:OnDelete:CASCADE" json:"track"`
+	FileType            string      `json:"file_type"`
+	FileName            string      `json:"file_name"`
+	CurrentVersion      int         `gorm:"not null;default:0" json:"current_version"`
 }
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// NotesheetVersion is an uploaded revision of the file of a notesheet,
+// kept when newer revisions are uploaded. Versions are numbered from 1 for
+// each notesheet; the file of the notesheet is that of its current version.
+type NotesheetVersion struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	NotesheetID  uint      `gorm:"not null;uniqueIndex:idx_notesheet_version" json:"notesheet_id"`
+	Version      int       `gorm:"not null;uniqueIndex:idx_notesheet_version" json:"version"`
+	Filepath     string    `gorm:"not null" json:"filepath"`
+	FileName     string    `json:"file_name"`
+	FileType     string    `json:"file_type"`
+	Size         int64     `gorm:"not null;default:0" json:"size"`
+	ChangeNote   string    `json:"change_note"`
+	UploadedByID *uint     `json:"uploaded_by_id"`
+	UploadedBy   *User     `gorm:"foreignKey:UploadedByID;constraint:OnDelete:SET NULL" json:"-"`
+	Notesheet    Notesheet `gorm:"foreignKey:NotesheetID;constraint:OnDelete:CASCADE" json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -2,9 +2,21 @@ package model
 
 import "time"
 
-// Performance represents a track performed at an event.
+// Performance is an entry of the setlist of an event: a track performed at
+// the event or, without a track, a break. Entries are performed in the order
+// of their positions, starting at 1.
 type Performance struct {
-	EventID   uint      `gorm:"primaryKey;not null" json:"event_id"`
-	TrackID   uint      `gorm:"primaryKey;not null" json:"track_id"`
-	StartTime time.Time `gorm:"not null" json:"start_time"`
+	ID       uint   `gorm:"primarykey" json:"id"`
+	EventID  uint   `gorm:"not null;index" json:"event_id"`
+	TrackID  *uint  `gorm:"index" json:"track_id"`
+	Position int    `gorm:"not null" json:"position"`
+	Title    string `json:"title"`
+	// StartTime pins the planned start of the entry; entries without one
+	// start when the previous entry ends.
+	StartTime       *time.Time `json:"start_time"`
+	DurationSeconds int        `gorm:"not null;default:0" json:"duration_seconds"`
+	Key             string     `json:"key"`
+	Tempo           int        `json:"tempo"`
+	Notes           string     `json:"notes"`
+	Track           *Track     `gorm:"foreignKey:TrackID" json:"track,omitempty"`
 }
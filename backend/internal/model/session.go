@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// Session represents a logged-in device of a user. Only the refresh token
+// issued last for it, identified by RefreshTokenID, is accepted.
+type Session struct {
+	ID             uint       `gorm:"primarykey" json:"id"`
+	UserID         uint       `gorm:"not null;index" json:"user_id"`
+	UserAgent      string     `json:"user_agent"`
+	IPAddress      string     `json:"ip_address"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	LastSeenAt     time.Time  `gorm:"not null" json:"last_seen_at"`
+	ExpiresAt      time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt      *time.Time `json:"revoked_at"`
+	RefreshTokenID string     `gorm:"not null;default:''" json:"-"`
+	User           User       `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
@@ -9,4 +9,5 @@ type Subgroup struct {
 	Users         []*User         `gorm:"many2many:subgroup_user;constraint:OnDelete:CASCADE" json:"users"`
 	Notesheets    []*Notesheet    `gorm:"many2many:notesheet_subgroup;constraint:OnDelete:CASCADE" json:"notesheets"`
 	Announcements []*Announcement `gorm:"many2many:announcement_subgroup;constraint:OnDelete:CASCADE" json:"announcements"`
+	LeaderIDs     []uint          `gorm:"-" json:"leader_ids"`
 }
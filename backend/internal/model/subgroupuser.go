@@ -0,0 +1,13 @@
+package model
+
+// SubgroupUser represents a user's membership and role within a subgroup.
+type SubgroupUser struct {
+	SubgroupID uint   `gorm:"primarykey" json:"subgroup_id"`
+	UserID     uint   `gorm:"primarykey" json:"user_id"`
+	Role       string `gorm:"not null;default:member" json:"role"`
+}
+
+// TableName keeps the join table name used by the Subgroup.Users association.
+func (SubgroupUser) TableName() string {
+	return "subgroup_user"
+}
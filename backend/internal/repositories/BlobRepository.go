@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"band-manager-backend/internal/db"
+	"band-manager-backend/internal/model"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BlobRepository handles database operations for stored file blobs.
+type BlobRepository struct {
+	db *gorm.DB
+}
+
+func NewBlobRepository() *BlobRepository {
+	return &BlobRepository{
+		db: db.GetDB(),
+	}
+}
+
+// ReserveBlob records a blob about to be stored, or marks an existing one as
+// just used, and loads its record. Garbage collection leaves blobs used
+// recently alone, so the blob is kept while a notesheet is made to
+// reference it.
+func (r *BlobRepository) ReserveBlob(blob *model.Blob) error {
+	if err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "hash"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"updated_at": time.Now()}),
+	}).Create(blob).Error; err != nil {
+		return err
+	}
+	return r.db.First(blob, "hash = ?", blob.Hash).Error
+}
+
+// RecountReferences sets the reference counts of blobs to the number of
+// notesheets and notesheet versions referencing them and returns how many
+// counts were wrong.
+func (r *BlobRepository) RecountReferences() (int64, error) {
+	const references = "((SELECT COUNT(*) FROM notesheets WHERE notesheets.filepath = blobs.key) + " +
+		"(SELECT COUNT(*) FROM notesheet_versions WHERE notesheet_versions.filepath = blobs.key))"
+	result := r.db.Model(&model.Blob{}).
+		Where("ref_count <> "+references).
+		UpdateColumn("ref_count", gorm.Expr(references))
+	return result.RowsAffected, result.Error
+}
+
+// GetUnreferencedBlobs returns blobs whose reference count is zero and that
+// were last used before the given time.
+func (r *BlobRepository) GetUnreferencedBlobs(usedBefore time.Time) ([]*model.Blob, error) {
+	var blobs []*model.Blob
+	err := r.db.Where("updated_at < ?", usedBefore).
+		Where("ref_count = 0").
+		Order("created_at").
+		Find(&blobs).Error
+	return blobs, err
+}
+
+// DeleteUnreferencedBlob deletes the blob's file with deleteFile and then
+// its record, if the blob's reference count is still zero and it is unused
+// since the given time. The record stays locked meanwhile, so the blob can
+// neither be reserved for a new upload nor referenced while its file is
+// deleted. It reports whether the blob was deleted.
+func (r *BlobRepository) DeleteUnreferencedBlob(hash string, usedBefore time.Time, deleteFile func(key string) error) (bool, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var blob model.Blob
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("hash = ? AND ref_count = 0 AND updated_at < ?", hash, usedBefore).
+			First(&blob).Error; err != nil {
+			return err
+		}
+
+		if err := deleteFile(blob.Key); err != nil {
+			return err
+		}
+		return tx.Delete(&blob).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// acquireBlob counts a new reference to the blob stored under the key. Keys
+// of files stored before blobs were introduced match no blob.
+func acquireBlob(tx *gorm.DB, key string) error {
+	return tx.Model(&model.Blob{}).Where("key = ?", key).Updates(map[string]interface{}{
+		"ref_count":  gorm.Expr("ref_count + 1"),
+		"updated_at": time.Now(),
+	}).Error
+}
+
+// releaseBlob counts a reference to the blob stored under the key as removed.
+func releaseBlob(tx *gorm.DB, key string) error {
+	return tx.Model(&model.Blob{}).Where("key = ? AND ref_count > 0", key).Updates(map[string]interface{}{
+		"ref_count":  gorm.Expr("ref_count - 1"),
+		"updated_at": time.Now(),
+	}).Error
+}
@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"band-manager-backend/internal/db"
+	"band-manager-backend/internal/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CalendarFeedRepository handles database operations for iCalendar feeds.
+type CalendarFeedRepository struct {
+	db *gorm.DB
+}
+
+func NewCalendarFeedRepository() *CalendarFeedRepository {
+	return &CalendarFeedRepository{
+		db: db.GetDB(),
+	}
+}
+
+// CreateFeed persists a new calendar feed.
+func (r *CalendarFeedRepository) CreateFeed(feed *model.CalendarFeed) error {
+	return r.db.Create(feed).Error
+}
+
+// GetFeedByID retrieves a calendar feed by its ID.
+func (r *CalendarFeedRepository) GetFeedByID(id uint) (*model.CalendarFeed, error) {
+	var feed model.CalendarFeed
+	if err := r.db.First(&feed, id).Error; err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// GetActiveFeed retrieves an unrevoked calendar feed by its token hash.
+func (r *CalendarFeedRepository) GetActiveFeed(tokenHash string) (*model.CalendarFeed, error) {
+	var feed model.CalendarFeed
+	err := r.db.Preload("User").Preload("Group").
+		Where("token_hash = ? AND revoked_at IS NULL", tokenHash).
+		First(&feed).Error
+	if err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// GetUserActiveFeeds retrieves all unrevoked calendar feeds of a user.
+func (r *CalendarFeedRepository) GetUserActiveFeeds(userID uint) ([]*model.CalendarFeed, error) {
+	var feeds []*model.CalendarFeed
+	err := r.db.Where("user_id = ? AND revoked_at IS NULL", userID).
+		Order("created_at DESC").
+		Find(&feeds).Error
+	return feeds, err
+}
+
+// RevokeFeed marks a calendar feed as revoked.
+func (r *CalendarFeedRepository) RevokeFeed(id uint) error {
+	return r.db.Model(&model.CalendarFeed{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+}
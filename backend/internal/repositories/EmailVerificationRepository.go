@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"band-manager-backend/internal/db"
+	"band-manager-backend/internal/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EmailVerificationRepository handles database operations for email verification tokens.
+type EmailVerificationRepository struct {
+	db *gorm.DB
+}
+
+func NewEmailVerificationRepository() *EmailVerificationRepository {
+	return &EmailVerificationRepository{
+		db: db.GetDB(),
+	}
+}
+
+// CreateToken persists a new email verification token.
+func (r *EmailVerificationRepository) CreateToken(token *model.EmailVerificationToken) error {
+	return r.db.Create(token).Error
+}
+
+// GetTokenByID retrieves a token by its ID.
+func (r *EmailVerificationRepository) GetTokenByID(id uint) (*model.EmailVerificationToken, error) {
+	var token model.EmailVerificationToken
+	if err := r.db.First(&token, id).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// RenewTokenHash replaces the hash of an unused, unexpired token, so that
+// only the token it is the hash of is valid. It reports whether the token
+// could still be used.
+func (r *EmailVerificationRepository) RenewTokenHash(id uint, tokenHash string) (bool, error) {
+	result := r.db.Model(&model.EmailVerificationToken{}).
+		Where("id = ? AND used_at IS NULL AND expires_at > ?", id, time.Now()).
+		Update("token_hash", tokenHash)
+	return result.RowsAffected == 1, result.Error
+}
+
+// ConsumeToken marks an unused, unexpired token as used and returns it.
+// Checking and using the token is a single statement, so that concurrent
+// requests cannot use it twice; gorm.ErrRecordNotFound is returned when
+// there is no such token.
+func (r *EmailVerificationRepository) ConsumeToken(tokenHash string) (*model.EmailVerificationToken, error) {
+	var token model.EmailVerificationToken
+	now := time.Now()
+	result := r.db.Model(&token).Clauses(clause.Returning{}).
+		Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected != 1 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &token, nil
+}
+
+// CountUserTokensSince returns how many tokens were issued to a user after the given time.
+func (r *EmailVerificationRepository) CountUserTokensSince(userID uint, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.EmailVerificationToken{}).
+		Where("user_id = ? AND created_at > ?", userID, since).
+		Count(&count).Error
+	return count, err
+}
+
+// InvalidateUserTokens marks every outstanding token of a user as used.
+func (r *EmailVerificationRepository) InvalidateUserTokens(userID uint) error {
+	return r.db.Model(&model.EmailVerificationToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", time.Now()).Error
+}
@@ -3,8 +3,11 @@ package repositories
 import (
 	"band-manager-backend/internal/db"
 	"band-manager-backend/internal/model"
+	"time"
 
+	"github.com/lib/pq"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // EventRepository handles database operations for events.
@@ -45,7 +48,7 @@ func (r *EventRepository) CreateEvent(event *model.Event) error {
 // GetEventByID retrieves an event by its ID with related entities.
 func (r *EventRepository) GetEventByID(id uint) (*model.Event, error) {
 	var event model.Event
-	if err := r.db.Preload("Group").Preload("Users").Preload("Tracks").First(&event, id).Error; err != nil {
+	if err := r.db.Preload("Group").Preload("Users").Preload("Tracks").Preload("Exceptions").First(&event, id).Error; err != nil {
 		return nil, err
 	}
 	return &event, nil
@@ -61,25 +64,107 @@ func (r *EventRepository) DeleteEvent(id uint) error {
 	return r.db.Delete(&model.Event{}, id).Error
 }
 
-// GetGroupEvents retrieves all events for a specific group.
-func (r *EventRepository) GetGroupEvents(groupID uint) ([]*model.Event, error) {
+// GetGroupEvents retrieves the events of a group that may occur within the
+// optional date range: single events overlapping it and every recurring
+// series starting before its end, limited to the given event types if any.
+func (r *EventRepository) GetGroupEvents(groupID uint, from, to *time.Time, eventTypes []string) ([]*model.Event, error) {
 	var events []*model.Event
-	if err := r.db.Preload("Users").Where("group_id = ?", groupID).Find(&events).Error; err != nil {
+	query := ofTypes(inDateRange(r.db.Preload("Users").Preload("Exceptions"), from, to), eventTypes).
+		Where("group_id = ?", groupID)
+	if err := query.Order("date").Find(&events).Error; err != nil {
 		return nil, err
 	}
 	return events, nil
 }
 
-// GetGroupEvents retrieves all events for a specific group.
-func (r *EventRepository) GetUserEvents(userID uint) ([]*model.Event, error) {
+// GetUserEvents retrieves the events a user is assigned to that may occur
+// within the optional date range, limited to the given event types if any.
+func (r *EventRepository) GetUserEvents(userID uint, from, to *time.Time, eventTypes []string) ([]*model.Event, error) {
 	var events []*model.Event
-	err := r.db.Preload("Users").
+	err := ofTypes(inDateRange(r.db.Preload("Users").Preload("Exceptions"), from, to), eventTypes).
 		Joins("JOIN event_users ON event_users.event_id = events.id").
 		Where("event_users.user_id = ?", userID).
+		Order("date").
 		Find(&events).Error
 	return events, err
 }
 
+// GetGroupCalendarEvents retrieves the events of a group ending after from,
+// with every recurring series, for publishing in a calendar feed.
+func (r *EventRepository) GetGroupCalendarEvents(groupID uint, from time.Time) ([]*model.Event, error) {
+	var events []*model.Event
+	err := calendarQuery(r.db, from).
+		Where("group_id = ?", groupID).
+		Find(&events).Error
+	return events, err
+}
+
+// GetUserCalendarEvents retrieves the events a user is assigned to ending
+// after from, with every recurring series, for publishing in a calendar feed.
+func (r *EventRepository) GetUserCalendarEvents(userID uint, from time.Time) ([]*model.Event, error) {
+	var events []*model.Event
+	err := calendarQuery(r.db, from).
+		Joins("JOIN event_users ON event_users.event_id = events.id").
+		Where("event_users.user_id = ?", userID).
+		Find(&events).Error
+	return events, err
+}
+
+// GetEventsInRange retrieves the events of all groups that may occur within
+// the date range, with their groups and exceptions.
+func (r *EventRepository) GetEventsInRange(from, to time.Time) ([]*model.Event, error) {
+	var events []*model.Event
+	err := inDateRange(r.db.Preload("Group").Preload("Exceptions"), &from, &to).
+		Order("date").
+		Find(&events).Error
+	return events, err
+}
+
+// UpdateReminderMinutes changes how many minutes before an event reminders
+// are sent. Nil makes the event use the reminders of its group.
+func (r *EventRepository) UpdateReminderMinutes(eventID uint, minutes []int64) error {
+	return r.db.Model(&model.Event{}).
+		Where("id = ?", eventID).
+		Update("reminder_minutes", pq.Int64Array(minutes)).Error
+}
+
+// calendarQuery loads events with everything shown in a calendar feed.
+func calendarQuery(query *gorm.DB, from time.Time) *gorm.DB {
+	return inDateRange(query.Preload("Group").Preload("Tracks").Preload("Performances.Track").Preload("Exceptions"), &from, nil).
+		Order("date")
+}
+
+// GetUsersEvents retrieves the events any of the users is assigned to that
+// may occur within the date range, in any group.
+func (r *EventRepository) GetUsersEvents(userIDs []uint, from, to time.Time) ([]*model.Event, error) {
+	var events []*model.Event
+	err := inDateRange(r.db.Preload("Users").Preload("Group").Preload("Exceptions"), &from, &to).
+		Where("events.id IN (?)", r.db.Table("event_users").Select("event_id").Where("user_id IN ?", userIDs)).
+		Order("date").
+		Find(&events).Error
+	return events, err
+}
+
+// inDateRange limits single events to those overlapping the range and
+// recurring series to those starting before its end.
+func inDateRange(query *gorm.DB, from, to *time.Time) *gorm.DB {
+	if to != nil {
+		query = query.Where("events.date <= ?", *to)
+	}
+	if from != nil {
+		query = query.Where("(events.recurrence_rule <> '' OR COALESCE(events.end_date, events.date) >= ?)", *from)
+	}
+	return query
+}
+
+// ofTypes limits events to the given types, if any.
+func ofTypes(query *gorm.DB, eventTypes []string) *gorm.DB {
+	if len(eventTypes) == 0 {
+		return query
+	}
+	return query.Where("events.type IN ?", eventTypes)
+}
+
 // GetUserEvents retrieves all events a user is participating in.
 func (r *EventRepository) AddTracksToEvent(eventID uint, trackIDs []uint) error {
 	var tracks []*model.Track
@@ -101,3 +186,142 @@ func (r *EventRepository) GetEventTracks(eventID uint) ([]*model.Track, error) {
 	}
 	return event.Tracks, nil
 }
+
+// GetAttendee retrieves a user's assignment to an event.
+func (r *EventRepository) GetAttendee(eventID, userID uint) (*model.EventUser, error) {
+	var attendee model.EventUser
+	if err := r.db.Where("event_id = ? AND user_id = ?", eventID, userID).First(&attendee).Error; err != nil {
+		return nil, err
+	}
+	return &attendee, nil
+}
+
+// GetAttendeeByResponseToken retrieves the assignment a respond-link token was issued for.
+func (r *EventRepository) GetAttendeeByResponseToken(tokenHash string) (*model.EventUser, error) {
+	var attendee model.EventUser
+	if err := r.db.Where("response_token_hash = ?", tokenHash).First(&attendee).Error; err != nil {
+		return nil, err
+	}
+	return &attendee, nil
+}
+
+// GetEventAttendees retrieves the assignments and responses of all users of an event.
+func (r *EventRepository) GetEventAttendees(eventID uint) ([]*model.EventUser, error) {
+	var attendees []*model.EventUser
+	err := r.db.Where("event_id = ?", eventID).Find(&attendees).Error
+	return attendees, err
+}
+
+// UpdateRSVP stores a user's attendance response to an event.
+func (r *EventRepository) UpdateRSVP(eventID, userID uint, status, comment string) error {
+	return r.db.Model(&model.EventUser{}).
+		Where("event_id = ? AND user_id = ?", eventID, userID).
+		Updates(map[string]interface{}{
+			"rsvp_status":  status,
+			"rsvp_comment": comment,
+			"responded_at": time.Now(),
+		}).Error
+}
+
+// SetResponseToken stores the hash of the respond-link token sent to a user.
+func (r *EventRepository) SetResponseToken(eventID, userID uint, tokenHash string) error {
+	return r.db.Model(&model.EventUser{}).
+		Where("event_id = ? AND user_id = ?", eventID, userID).
+		Update("response_token_hash", tokenHash).Error
+}
+
+// AddException excludes an occurrence from a recurring event.
+func (r *EventRepository) AddException(eventID uint, occurrenceDate time.Time) error {
+	return r.db.Create(&model.EventException{
+		EventID:        eventID,
+		OccurrenceDate: occurrenceDate,
+	}).Error
+}
+
+// DeleteSeriesOverrides removes the separately edited occurrences of a series
+// and its exceptions from the given occurrence date on.
+func (r *EventRepository) DeleteSeriesOverrides(seriesID uint, from time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("series_id = ? AND original_date >= ?", seriesID, from).
+			Delete(&model.Event{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("event_id = ? AND occurrence_date >= ?", seriesID, from).
+			Delete(&model.EventException{}).Error
+	})
+}
+
+// GetSeriesOverrideIDs retrieves the IDs of the separately edited
+// occurrences of a series, from the given occurrence date on if any.
+func (r *EventRepository) GetSeriesOverrideIDs(seriesID uint, from *time.Time) ([]uint, error) {
+	var ids []uint
+	query := r.db.Model(&model.Event{}).Where("series_id = ?", seriesID)
+	if from != nil {
+		query = query.Where("original_date >= ?", *from)
+	}
+	err := query.Pluck("id", &ids).Error
+	return ids, err
+}
+
+// MoveSeriesOverrides reassigns the separately edited occurrences and exceptions
+// of a series from the given occurrence date on to another series.
+func (r *EventRepository) MoveSeriesOverrides(fromSeriesID, toSeriesID uint, from time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.Event{}).
+			Where("series_id = ? AND original_date >= ?", fromSeriesID, from).
+			Update("series_id", toSeriesID).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.EventException{}).
+			Where("event_id = ? AND occurrence_date >= ?", fromSeriesID, from).
+			Update("event_id", toSeriesID).Error
+	})
+}
+
+// GetSubgroupNotes retrieves the subgroup notes of an event with their
+// subgroups, ordered by subgroup name.
+func (r *EventRepository) GetSubgroupNotes(eventID uint) ([]model.EventSubgroupNote, error) {
+	var notes []model.EventSubgroupNote
+	err := r.db.Preload("Subgroup").
+		Joins("JOIN subgroups ON subgroups.id = event_subgroup_notes.subgroup_id").
+		Where("event_subgroup_notes.event_id = ?", eventID).
+		Order("subgroups.name").
+		Find(&notes).Error
+	return notes, err
+}
+
+// SetSubgroupNote creates or replaces the note of a subgroup for an event.
+func (r *EventRepository) SetSubgroupNote(eventID, subgroupID uint, notes string) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "event_id"}, {Name: "subgroup_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"notes"}),
+	}).Create(&model.EventSubgroupNote{
+		EventID:    eventID,
+		SubgroupID: subgroupID,
+		Notes:      notes,
+	}).Error
+}
+
+// DeleteSubgroupNote removes the note of a subgroup for an event.
+func (r *EventRepository) DeleteSubgroupNote(eventID, subgroupID uint) error {
+	return r.db.Where("event_id = ? AND subgroup_id = ?", eventID, subgroupID).
+		Delete(&model.EventSubgroupNote{}).Error
+}
+
+// CopySubgroupNotes copies the subgroup notes of one event to another, for
+// occurrences of a series edited separately.
+func (r *EventRepository) CopySubgroupNotes(fromEventID, toEventID uint) error {
+	var notes []model.EventSubgroupNote
+	if err := r.db.Where("event_id = ?", fromEventID).Find(&notes).Error; err != nil {
+		return err
+	}
+	if len(notes) == 0 {
+		return nil
+	}
+
+	for i := range notes {
+		notes[i].ID = 0
+		notes[i].EventID = toEventID
+	}
+	return r.db.Create(&notes).Error
+}
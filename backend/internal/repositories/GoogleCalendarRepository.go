@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"band-manager-backend/internal/db"
+	"band-manager-backend/internal/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GoogleCalendarRepository handles database operations for linked Google calendars.
+type GoogleCalendarRepository struct {
+	db *gorm.DB
+}
+
+func NewGoogleCalendarRepository() *GoogleCalendarRepository {
+	return &GoogleCalendarRepository{
+		db: db.GetDB(),
+	}
+}
+
+// CreateState persists a new OAuth state nonce.
+func (r *GoogleCalendarRepository) CreateState(state *model.GoogleOAuthState) error {
+	return r.db.Create(state).Error
+}
+
+// ConsumeState deletes an unexpired OAuth state nonce by its hash and returns it.
+func (r *GoogleCalendarRepository) ConsumeState(stateHash string) (*model.GoogleOAuthState, error) {
+	var state model.GoogleOAuthState
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("state_hash = ? AND expires_at > ?", stateHash, time.Now()).
+			First(&state).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&state).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// DeleteExpiredStates removes OAuth state nonces that can no longer be used.
+func (r *GoogleCalendarRepository) DeleteExpiredStates() error {
+	return r.db.Where("expires_at <= ?", time.Now()).Delete(&model.GoogleOAuthState{}).Error
+}
+
+// GetToken retrieves the Google token of a user.
+func (r *GoogleCalendarRepository) GetToken(userID uint) (*model.GoogleToken, error) {
+	var token model.GoogleToken
+	if err := r.db.Where("user_id = ?", userID).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// GetTokens retrieves the Google tokens of those of the users who linked a calendar.
+func (r *GoogleCalendarRepository) GetTokens(userIDs []uint) ([]*model.GoogleToken, error) {
+	var tokens []*model.GoogleToken
+	if len(userIDs) == 0 {
+		return tokens, nil
+	}
+	err := r.db.Where("user_id IN ?", userIDs).Find(&tokens).Error
+	return tokens, err
+}
+
+// SaveToken stores the Google token of a user, replacing any previous one.
+func (r *GoogleCalendarRepository) SaveToken(token *model.GoogleToken) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"access_token", "token_type", "refresh_token", "expiry"}),
+	}).Create(token).Error
+}
+
+// DeleteToken removes the Google token of a user along with the records of
+// the events synced to the user's calendar.
+func (r *GoogleCalendarRepository) DeleteToken(userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.GoogleCalendarEvent{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&model.GoogleToken{}).Error
+	})
+}
+
+// GetSyncedEvents retrieves the calendar copies of the events.
+func (r *GoogleCalendarRepository) GetSyncedEvents(eventIDs []uint) ([]*model.GoogleCalendarEvent, error) {
+	var synced []*model.GoogleCalendarEvent
+	if len(eventIDs) == 0 {
+		return synced, nil
+	}
+	err := r.db.Where("event_id IN ?", eventIDs).Find(&synced).Error
+	return synced, err
+}
+
+// GetUserSyncedEvents retrieves the calendar copies in the calendar of a user.
+func (r *GoogleCalendarRepository) GetUserSyncedEvents(userID uint) ([]*model.GoogleCalendarEvent, error) {
+	var synced []*model.GoogleCalendarEvent
+	err := r.db.Where("user_id = ?", userID).Find(&synced).Error
+	return synced, err
+}
+
+// SaveSyncedEvent records a calendar copy of an event, replacing the previous record for the attendee.
+func (r *GoogleCalendarRepository) SaveSyncedEvent(synced *model.GoogleCalendarEvent) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "event_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"calendar_id", "last_synced"}),
+	}).Create(synced).Error
+}
+
+// DeleteSyncedEvent removes the record of a calendar copy.
+func (r *GoogleCalendarRepository) DeleteSyncedEvent(id uint) error {
+	return r.db.Delete(&model.GoogleCalendarEvent{}, id).Error
+}
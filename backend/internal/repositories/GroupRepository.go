@@ -6,9 +6,23 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/lib/pq"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrLastRoleHolder is returned when a member would lose a role that a group
+// must keep and no other member of the group holds.
+var ErrLastRoleHolder = errors.New("last member holding the role")
+
+// ErrRoleNotHeld is returned when a member is to hand over a role they no
+// longer hold.
+var ErrRoleNotHeld = errors.New("member does not hold the role")
+
+// ErrNotGroupMember is returned when a role is to be given to a user who is
+// not a member of the group.
+var ErrNotGroupMember = errors.New("user not in group")
+
 // GroupRepository handles database operations for groups.
 type GroupRepository struct {
 	db *gorm.DB
@@ -86,17 +100,157 @@ func (r *GroupRepository) GetGroupMembers(groupID uint) ([]*model.User, error) {
 	return users, nil
 }
 
-// RemoveUserFromGroup removes a user from a group.
-func (r *GroupRepository) RemoveUserFromGroup(userID uint, groupID uint) error {
+// RemoveUserFromGroup removes a user from a group and its subgroups and
+// forgets which of its instruments they play. It returns ErrLastRoleHolder
+// instead if the user is the last member holding keptRole.
+func (r *GroupRepository) RemoveUserFromGroup(userID uint, groupID uint, keptRole string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := requireOtherHolder(tx, groupID, userID, keptRole); err != nil {
+			return err
+		}
+		if err := tx.Delete(&model.SubgroupUser{},
+			"user_id = ? AND subgroup_id IN (SELECT id FROM subgroups WHERE group_id = ?)", userID, groupID).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&model.InstrumentUser{},
+			"user_id = ? AND instrument_id IN (SELECT id FROM instruments WHERE group_id = ?)", userID, groupID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.UserGroupRole{}, "user_id = ? AND group_id = ?", userID, groupID).Error
+	})
+}
+
+// TransferRole gives the new holder a role and moves the previous holder to
+// another role atomically. Both memberships are locked first, and it returns
+// ErrRoleNotHeld if the previous holder no longer holds the role or
+// ErrNotGroupMember if the new holder is no longer a member.
+func (r *GroupRepository) TransferRole(groupID, fromUserID, toUserID uint, role, previousHolderRole string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var memberships []model.UserGroupRole
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("group_id = ? AND user_id IN ?", groupID, []uint{fromUserID, toUserID}).
+			Order("user_id").
+			Find(&memberships).Error; err != nil {
+			return err
+		}
+
+		fromHoldsRole, toIsMember := false, false
+		for _, membership := range memberships {
+			switch membership.UserID {
+			case fromUserID:
+				fromHoldsRole = membership.Role == role
+			case toUserID:
+				toIsMember = true
+			}
+		}
+		if !fromHoldsRole {
+			return ErrRoleNotHeld
+		}
+		if !toIsMember {
+			return ErrNotGroupMember
+		}
+
+		if err := tx.Model(&model.UserGroupRole{}).
+			Where("user_id = ? AND group_id = ?", toUserID, groupID).
+			Update("role", role).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.UserGroupRole{}).
+			Where("user_id = ? AND group_id = ?", fromUserID, groupID).
+			Update("role", previousHolderRole).Error
+	})
+}
+
+// UpdateUserRole updates a user's role within a group. It returns
+// ErrLastRoleHolder instead if that takes keptRole from its last holder.
+func (r *GroupRepository) UpdateUserRole(userID uint, groupID uint, newRole, keptRole string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if newRole != keptRole {
+			if err := requireOtherHolder(tx, groupID, userID, keptRole); err != nil {
+				return err
+			}
+		}
+		return tx.Model(&model.UserGroupRole{}).
+			Where("user_id = ? AND group_id = ?", userID, groupID).
+			Update("role", newRole).Error
+	})
+}
 
-	return r.db.Delete(&model.UserGroupRole{}, "user_id = ? AND group_id = ?", userID, groupID).Error
+// requireOtherHolder locks the memberships of a group holding the role, so
+// that concurrent changes to them wait for the transaction, and returns
+// ErrLastRoleHolder if the user holds the role and no other member does.
+func requireOtherHolder(tx *gorm.DB, groupID, userID uint, role string) error {
+	var holderIDs []uint
+	if err := tx.Model(&model.UserGroupRole{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("group_id = ? AND role = ?", groupID, role).
+		Pluck("user_id", &holderIDs).Error; err != nil {
+		return err
+	}
+	if len(holderIDs) == 1 && holderIDs[0] == userID {
+		return ErrLastRoleHolder
+	}
+	return nil
 }
 
-// UpdateUserRole updates a user's role within a group.
-func (r *GroupRepository) UpdateUserRole(userID uint, groupID uint, newRole string) error {
-	return r.db.Model(&model.UserGroupRole{}).
-		Where("user_id = ? AND group_id = ?", userID, groupID).
-		Update("role", newRole).Error
+// handOverRole gives the role to another member in every group where the
+// user is its only holder: to the member holding successorRole, or else any
+// member, with the lowest user ID. Groups without other members are skipped.
+func handOverRole(tx *gorm.DB, userID uint, role, successorRole string) error {
+	var groupIDs []uint
+	if err := tx.Model(&model.UserGroupRole{}).
+		Where("user_id = ? AND role = ?", userID, role).
+		Pluck("group_id", &groupIDs).Error; err != nil {
+		return err
+	}
+
+	for _, groupID := range groupIDs {
+		if err := requireOtherHolder(tx, groupID, userID, role); !errors.Is(err, ErrLastRoleHolder) {
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		var successor model.UserGroupRole
+		err := tx.Where("group_id = ? AND user_id <> ?", groupID, userID).
+			Order(clause.OrderBy{Expression: clause.Expr{SQL: "role = ? DESC, user_id", Vars: []interface{}{successorRole}}}).
+			First(&successor).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&model.UserGroupRole{}).
+			Where("user_id = ? AND group_id = ?", successor.UserID, groupID).
+			Update("role", role).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateRequireApproval changes whether joining a group requires approval.
+func (r *GroupRepository) UpdateRequireApproval(groupID uint, requireApproval bool) error {
+	return r.db.Model(&model.Group{}).
+		Where("id = ?", groupID).
+		Update("require_approval", requireApproval).Error
+}
+
+// UpdateTimeZone changes the time zone new events of a group are created in.
+func (r *GroupRepository) UpdateTimeZone(groupID uint, timeZone string) error {
+	return r.db.Model(&model.Group{}).
+		Where("id = ?", groupID).
+		Update("time_zone", timeZone).Error
+}
+
+// UpdateReminderMinutes changes how many minutes before its events members
+// of a group are reminded of them.
+func (r *GroupRepository) UpdateReminderMinutes(groupID uint, minutes []int64) error {
+	return r.db.Model(&model.Group{}).
+		Where("id = ?", groupID).
+		Update("reminder_minutes", pq.Int64Array(minutes)).Error
 }
 
 // UpdateAccessToken updates a group's access token.
@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"band-manager-backend/internal/db"
+	"band-manager-backend/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// InstrumentRepository handles database operations for the instrument
+// catalogues of groups and the instruments members play.
+type InstrumentRepository struct {
+	db *gorm.DB
+}
+
+func NewInstrumentRepository() *InstrumentRepository {
+	return &InstrumentRepository{
+		db: db.GetDB(),
+	}
+}
+
+// CreateInstrument persists a new catalogue instrument.
+func (r *InstrumentRepository) CreateInstrument(instrument *model.Instrument) error {
+	return r.db.Create(instrument).Error
+}
+
+// GetInstrumentByID retrieves a catalogue instrument by its ID.
+func (r *InstrumentRepository) GetInstrumentByID(id uint) (*model.Instrument, error) {
+	var instrument model.Instrument
+	if err := r.db.First(&instrument, id).Error; err != nil {
+		return nil, err
+	}
+	return &instrument, nil
+}
+
+// GetGroupInstrumentByName retrieves an instrument of a group's catalogue by its name.
+func (r *InstrumentRepository) GetGroupInstrumentByName(groupID uint, name string) (*model.Instrument, error) {
+	var instrument model.Instrument
+	if err := r.db.Where("group_id = ? AND name = ?", groupID, name).First(&instrument).Error; err != nil {
+		return nil, err
+	}
+	return &instrument, nil
+}
+
+// GetGroupInstruments retrieves the instrument catalogue of a group with
+// the IDs of the members playing each instrument.
+func (r *InstrumentRepository) GetGroupInstruments(groupID uint) ([]*model.Instrument, error) {
+	var instruments []*model.Instrument
+	if err := r.db.Where("group_id = ?", groupID).Order("family, name").Find(&instruments).Error; err != nil {
+		return nil, err
+	}
+
+	var players []model.InstrumentUser
+	if err := r.db.Where("instrument_id IN (SELECT id FROM instruments WHERE group_id = ?)", groupID).
+		Order("user_id").Find(&players).Error; err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]*model.Instrument, len(instruments))
+	for _, instrument := range instruments {
+		instrument.PlayerIDs = []uint{}
+		byID[instrument.ID] = instrument
+	}
+	for _, player := range players {
+		if instrument := byID[player.InstrumentID]; instrument != nil {
+			instrument.PlayerIDs = append(instrument.PlayerIDs, player.UserID)
+		}
+	}
+	return instruments, nil
+}
+
+// UpdateInstrument saves a catalogue instrument, renaming the parts for it.
+func (r *InstrumentRepository) UpdateInstrument(instrument *model.Instrument) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(instrument).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.Notesheet{}).
+			Where("instrument_id = ?", instrument.ID).
+			Update("instrument", instrument.Name).Error
+	})
+}
+
+// DeleteInstrument removes a catalogue instrument. Parts for it keep its
+// name but no longer reference it, and members no longer play it.
+func (r *InstrumentRepository) DeleteInstrument(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.Notesheet{}).
+			Where("instrument_id = ?", id).
+			Update("instrument_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&model.InstrumentUser{}, "instrument_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.Instrument{}, id).Error
+	})
+}
+
+// GetUserInstruments retrieves the instruments of a group's catalogue a member plays.
+func (r *InstrumentRepository) GetUserInstruments(groupID, userID uint) ([]*model.Instrument, error) {
+	var instruments []*model.Instrument
+	err := r.db.Joins("JOIN instrument_user ON instrument_user.instrument_id = instruments.id").
+		Where("instruments.group_id = ? AND instrument_user.user_id = ?", groupID, userID).
+		Order("instruments.family, instruments.name").
+		Find(&instruments).Error
+	return instruments, err
+}
+
+// SetUserInstruments replaces the instruments of a group's catalogue a
+// member plays.
+func (r *InstrumentRepository) SetUserInstruments(groupID, userID uint, instrumentIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&model.InstrumentUser{},
+			"user_id = ? AND instrument_id IN (SELECT id FROM instruments WHERE group_id = ?)", userID, groupID).Error; err != nil {
+			return err
+		}
+		if len(instrumentIDs) == 0 {
+			return nil
+		}
+
+		players := make([]model.InstrumentUser, 0, len(instrumentIDs))
+		for _, instrumentID := range instrumentIDs {
+			players = append(players, model.InstrumentUser{InstrumentID: instrumentID, UserID: userID})
+		}
+		return tx.Create(&players).Error
+	})
+}
@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"band-manager-backend/internal/db"
+	"band-manager-backend/internal/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// InvitationRepository handles database operations for group invitations.
+type InvitationRepository struct {
+	db *gorm.DB
+}
+
+func NewInvitationRepository() *InvitationRepository {
+	return &InvitationRepository{
+		db: db.GetDB(),
+	}
+}
+
+// CreateInvitation persists a new invitation.
+func (r *InvitationRepository) CreateInvitation(invitation *model.GroupInvitation) error {
+	return r.db.Create(invitation).Error
+}
+
+// GetInvitationByID retrieves an invitation by its ID.
+func (r *InvitationRepository) GetInvitationByID(id uint) (*model.GroupInvitation, error) {
+	var invitation model.GroupInvitation
+	if err := r.db.First(&invitation, id).Error; err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// RenewTokenHash replaces the token hash of an invitation that can still be
+// accepted, so that only the token it is the hash of is valid. It reports
+// whether the invitation could still be accepted.
+func (r *InvitationRepository) RenewTokenHash(id uint, tokenHash string) (bool, error) {
+	result := r.db.Model(&model.GroupInvitation{}).
+		Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?", id, time.Now()).
+		Update("token_hash", tokenHash)
+	return result.RowsAffected == 1, result.Error
+}
+
+// GetPendingInvitation retrieves an unaccepted, unrevoked and unexpired invitation by its token hash.
+func (r *InvitationRepository) GetPendingInvitation(tokenHash string) (*model.GroupInvitation, error) {
+	var invitation model.GroupInvitation
+	err := r.db.Preload("Group").
+		Where("token_hash = ? AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?", tokenHash, time.Now()).
+		First(&invitation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// GetGroupPendingInvitations retrieves all invitations of a group that can still be accepted.
+func (r *InvitationRepository) GetGroupPendingInvitations(groupID uint) ([]*model.GroupInvitation, error) {
+	var invitations []*model.GroupInvitation
+	err := r.db.Where("group_id = ? AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?", groupID, time.Now()).
+		Order("created_at DESC").
+		Find(&invitations).Error
+	return invitations, err
+}
+
+// RevokeInvitation marks an invitation as revoked.
+func (r *InvitationRepository) RevokeInvitation(id uint) error {
+	return r.db.Model(&model.GroupInvitation{}).
+		Where("id = ? AND accepted_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokePendingInvitations revokes the outstanding invitations of an email address to a group.
+func (r *InvitationRepository) RevokePendingInvitations(groupID uint, email string) error {
+	return r.db.Model(&model.GroupInvitation{}).
+		Where("group_id = ? AND LOWER(email) = LOWER(?) AND accepted_at IS NULL AND revoked_at IS NULL", groupID, email).
+		Update("revoked_at", time.Now()).Error
+}
+
+// AcceptInvitation adds the user to the group and its subgroups with the
+// invited role and marks the invitation as used, in a single transaction.
+// Subgroups deleted since the invitation was sent are skipped.
+func (r *InvitationRepository) AcceptInvitation(invitation *model.GroupInvitation, userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&model.GroupInvitation{}).
+			Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL", invitation.ID).
+			Updates(map[string]interface{}{"accepted_at": now, "accepted_by": userID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Create(&model.UserGroupRole{
+			UserID:  userID,
+			GroupID: invitation.GroupID,
+			Role:    invitation.Role,
+		}).Error; err != nil {
+			return err
+		}
+
+		if len(invitation.SubgroupIDs) == 0 {
+			return nil
+		}
+
+		var subgroupIDs []uint
+		if err := tx.Model(&model.Subgroup{}).
+			Where("id IN ? AND group_id = ?", []int64(invitation.SubgroupIDs), invitation.GroupID).
+			Pluck("id", &subgroupIDs).Error; err != nil {
+			return err
+		}
+
+		for _, subgroupID := range subgroupIDs {
+			if err := tx.Create(&model.SubgroupUser{
+				SubgroupID: subgroupID,
+				UserID:     userID,
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
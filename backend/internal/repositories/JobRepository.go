@@ -0,0 +1,205 @@
+package repositories
+
+import (
+	"band-manager-backend/internal/db"
+	"band-manager-backend/internal/model"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	JobPending   = "pending"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	// JobDead marks jobs that ran out of attempts and wait for an administrator.
+	JobDead = "dead"
+)
+
+// ErrJobLockLost is returned when a worker records the outcome of a job it no
+// longer holds the lock of.
+var ErrJobLockLost = errors.New("lost the lock on the job")
+
+// JobRepository handles database operations for background jobs.
+type JobRepository struct {
+	db *gorm.DB
+}
+
+func NewJobRepository() *JobRepository {
+	return &JobRepository{
+		db: db.GetDB(),
+	}
+}
+
+// CreateJob persists a pending job. A job whose idempotency key is already
+// taken is silently skipped.
+func (r *JobRepository) CreateJob(job *model.Job) error {
+	job.Status = JobPending
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "idempotency_key"}},
+		DoNothing: true,
+	}).Create(job).Error
+}
+
+// ClaimJob locks the pending job that is due the longest for the worker and
+// counts the attempt. Jobs locked by other workers are skipped, so several
+// instances can share the queue. It returns nil when no job is due.
+func (r *JobRepository) ClaimJob(workerID string) (*model.Job, error) {
+	var job model.Job
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND run_at <= ?", JobPending, time.Now()).
+			Order("run_at").
+			First(&job).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		job.Status = JobRunning
+		job.Attempts++
+		job.LockedAt = &now
+		job.LockedBy = workerID
+		return tx.Model(&job).Updates(map[string]interface{}{
+			"status":    job.Status,
+			"attempts":  job.Attempts,
+			"locked_at": job.LockedAt,
+			"locked_by": job.LockedBy,
+		}).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// CompleteJob marks a job the worker is running as succeeded.
+func (r *JobRepository) CompleteJob(jobID uint, workerID string) error {
+	now := time.Now()
+	return r.finishJob(jobID, workerID, map[string]interface{}{
+		"status":       JobSucceeded,
+		"locked_at":    nil,
+		"locked_by":    "",
+		"last_error":   "",
+		"completed_at": &now,
+	})
+}
+
+// RetryJobAt records the failure of a job the worker is running and puts it
+// back in the queue.
+func (r *JobRepository) RetryJobAt(jobID uint, workerID, lastError string, runAt time.Time) error {
+	return r.finishJob(jobID, workerID, map[string]interface{}{
+		"status":     JobPending,
+		"run_at":     runAt,
+		"locked_at":  nil,
+		"locked_by":  "",
+		"last_error": lastError,
+	})
+}
+
+// KillJob records the failure of a job the worker is running that will not
+// be retried automatically.
+func (r *JobRepository) KillJob(jobID uint, workerID, lastError string) error {
+	return r.finishJob(jobID, workerID, map[string]interface{}{
+		"status":     JobDead,
+		"locked_at":  nil,
+		"locked_by":  "",
+		"last_error": lastError,
+	})
+}
+
+// finishJob records the outcome of a job if the worker still holds its lock.
+// It returns ErrJobLockLost otherwise, as the job has been released to the
+// queue and may be running elsewhere.
+func (r *JobRepository) finishJob(jobID uint, workerID string, updates map[string]interface{}) error {
+	result := r.db.Model(&model.Job{}).
+		Where("id = ? AND status = ? AND locked_by = ?", jobID, JobRunning, workerID).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrJobLockLost
+	}
+	return nil
+}
+
+// ReleaseStaleJobs returns jobs that have been running since before the given
+// time, whose workers presumably stopped, to the queue. Jobs without attempts
+// left are marked dead instead.
+func (r *JobRepository) ReleaseStaleJobs(lockedBefore time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		stale := tx.Model(&model.Job{}).Where("status = ? AND locked_at < ?", JobRunning, lockedBefore).Session(&gorm.Session{})
+		if err := stale.Where("attempts >= max_attempts").Updates(map[string]interface{}{
+			"status":     JobDead,
+			"locked_at":  nil,
+			"locked_by":  "",
+			"last_error": "worker stopped while running the job",
+		}).Error; err != nil {
+			return err
+		}
+		return stale.Updates(map[string]interface{}{
+			"status":    JobPending,
+			"run_at":    time.Now(),
+			"locked_at": nil,
+			"locked_by": "",
+		}).Error
+	})
+}
+
+// DeleteSucceededJobs removes jobs that succeeded before the given time.
+func (r *JobRepository) DeleteSucceededJobs(completedBefore time.Time) error {
+	return r.db.Where("status = ? AND completed_at < ?", JobSucceeded, completedBefore).Delete(&model.Job{}).Error
+}
+
+// GetJobByID retrieves a job by its ID.
+func (r *JobRepository) GetJobByID(id uint) (*model.Job, error) {
+	var job model.Job
+	if err := r.db.First(&job, id).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// ListJobs returns a page of jobs, newest first, optionally filtered by
+// status and type, along with the total number of matching jobs.
+func (r *JobRepository) ListJobs(status, jobType string, offset, limit int) ([]*model.Job, int64, error) {
+	query := r.db.Model(&model.Job{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if jobType != "" {
+		query = query.Where("type = ?", jobType)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var jobs []*model.Job
+	if err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&jobs).Error; err != nil {
+		return nil, 0, err
+	}
+	return jobs, total, nil
+}
+
+// RequeueJob puts a dead job back in the queue with a fresh set of attempts.
+func (r *JobRepository) RequeueJob(jobID uint) error {
+	result := r.db.Model(&model.Job{}).Where("id = ? AND status = ?", jobID, JobDead).Updates(map[string]interface{}{
+		"status":   JobPending,
+		"attempts": 0,
+		"run_at":   time.Now(),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
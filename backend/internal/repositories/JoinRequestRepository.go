@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"band-manager-backend/internal/db"
+	"band-manager-backend/internal/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	JoinRequestPending  = "pending"
+	JoinRequestApproved = "approved"
+	JoinRequestRejected = "rejected"
+)
+
+// JoinRequestRepository handles database operations for group join requests.
+type JoinRequestRepository struct {
+	db *gorm.DB
+}
+
+func NewJoinRequestRepository() *JoinRequestRepository {
+	return &JoinRequestRepository{
+		db: db.GetDB(),
+	}
+}
+
+// CreateRequest persists a new pending join request.
+func (r *JoinRequestRepository) CreateRequest(request *model.JoinRequest) error {
+	request.Status = JoinRequestPending
+	return r.db.Create(request).Error
+}
+
+// GetRequestByID retrieves a join request by its ID with the requesting user.
+func (r *JoinRequestRepository) GetRequestByID(id uint) (*model.JoinRequest, error) {
+	var request model.JoinRequest
+	if err := r.db.Preload("User").Preload("Group").First(&request, id).Error; err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// GetPendingRequest retrieves the pending request of a user for a group.
+func (r *JoinRequestRepository) GetPendingRequest(groupID, userID uint) (*model.JoinRequest, error) {
+	var request model.JoinRequest
+	err := r.db.Where("group_id = ? AND user_id = ? AND status = ?", groupID, userID, JoinRequestPending).
+		First(&request).Error
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// GetGroupPendingRequests retrieves all pending requests of a group with the requesting users.
+func (r *JoinRequestRepository) GetGroupPendingRequests(groupID uint) ([]*model.JoinRequest, error) {
+	var requests []*model.JoinRequest
+	err := r.db.Preload("User").
+		Where("group_id = ? AND status = ?", groupID, JoinRequestPending).
+		Order("created_at").
+		Find(&requests).Error
+	return requests, err
+}
+
+// ApproveRequest adds the user to the group and subgroups and marks the request approved.
+func (r *JoinRequestRepository) ApproveRequest(request *model.JoinRequest, role string, subgroupIDs []uint, decidedByID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := r.decide(tx, request, JoinRequestApproved, decidedByID); err != nil {
+			return err
+		}
+
+		if err := tx.Create(&model.UserGroupRole{
+			UserID:  request.UserID,
+			GroupID: request.GroupID,
+			Role:    role,
+		}).Error; err != nil {
+			return err
+		}
+
+		for _, subgroupID := range subgroupIDs {
+			if err := tx.Create(&model.SubgroupUser{
+				SubgroupID: subgroupID,
+				UserID:     request.UserID,
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// RejectRequest marks a pending request as rejected.
+func (r *JoinRequestRepository) RejectRequest(request *model.JoinRequest, decidedByID uint) error {
+	return r.decide(r.db, request, JoinRequestRejected, decidedByID)
+}
+
+// decide moves a request out of the pending state, failing if it was already decided.
+func (r *JoinRequestRepository) decide(tx *gorm.DB, request *model.JoinRequest, status string, decidedByID uint) error {
+	result := tx.Model(&model.JoinRequest{}).
+		Where("id = ? AND status = ?", request.ID, JoinRequestPending).
+		Updates(map[string]interface{}{
+			"status":        status,
+			"decided_at":    time.Now(),
+			"decided_by_id": decidedByID,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"band-manager-backend/internal/db"
+	"band-manager-backend/internal/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PasswordResetRepository handles database operations for password reset tokens.
+type PasswordResetRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordResetRepository() *PasswordResetRepository {
+	return &PasswordResetRepository{
+		db: db.GetDB(),
+	}
+}
+
+// CreateToken persists a new password reset token.
+func (r *PasswordResetRepository) CreateToken(token *model.PasswordResetToken) error {
+	return r.db.Create(token).Error
+}
+
+// GetTokenByID retrieves a token by its ID.
+func (r *PasswordResetRepository) GetTokenByID(id uint) (*model.PasswordResetToken, error) {
+	var token model.PasswordResetToken
+	if err := r.db.First(&token, id).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// RenewTokenHash replaces the hash of an unused, unexpired token, so that
+// only the token it is the hash of is valid. It reports whether the token
+// could still be used.
+func (r *PasswordResetRepository) RenewTokenHash(id uint, tokenHash string) (bool, error) {
+	result := r.db.Model(&model.PasswordResetToken{}).
+		Where("id = ? AND used_at IS NULL AND expires_at > ?", id, time.Now()).
+		Update("token_hash", tokenHash)
+	return result.RowsAffected == 1, result.Error
+}
+
+// ConsumeToken marks an unused, unexpired token as used and returns it.
+// Checking and using the token is a single statement, so that concurrent
+// requests cannot use it twice; gorm.ErrRecordNotFound is returned when
+// there is no such token.
+func (r *PasswordResetRepository) ConsumeToken(tokenHash string) (*model.PasswordResetToken, error) {
+	var token model.PasswordResetToken
+	now := time.Now()
+	result := r.db.Model(&token).Clauses(clause.Returning{}).
+		Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected != 1 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &token, nil
+}
+
+// InvalidateUserTokens marks every outstanding token of a user as used.
+func (r *PasswordResetRepository) InvalidateUserTokens(userID uint) error {
+	return r.db.Model(&model.PasswordResetToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", time.Now()).Error
+}
@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"band-manager-backend/internal/db"
+	"band-manager-backend/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// RoleRepository handles database operations for custom group roles.
+type RoleRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository() *RoleRepository {
+	return &RoleRepository{
+		db: db.GetDB(),
+	}
+}
+
+// CreateRole persists a new custom role.
+func (r *RoleRepository) CreateRole(role *model.CustomRole) error {
+	return r.db.Create(role).Error
+}
+
+// GetRoleByID retrieves a custom role by its ID.
+func (r *RoleRepository) GetRoleByID(id uint) (*model.CustomRole, error) {
+	var role model.CustomRole
+	if err := r.db.First(&role, id).Error; err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// GetGroupRoleByName retrieves a custom role of a group by its name.
+func (r *RoleRepository) GetGroupRoleByName(groupID uint, name string) (*model.CustomRole, error) {
+	var role model.CustomRole
+	if err := r.db.Where("group_id = ? AND name = ?", groupID, name).First(&role).Error; err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// GetGroupRoles retrieves all custom roles defined in a group.
+func (r *RoleRepository) GetGroupRoles(groupID uint) ([]*model.CustomRole, error) {
+	var roles []*model.CustomRole
+	err := r.db.Where("group_id = ?", groupID).Order("name").Find(&roles).Error
+	return roles, err
+}
+
+// UpdateRole saves a custom role and renames it on every member holding it.
+func (r *RoleRepository) UpdateRole(role *model.CustomRole, oldName string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(role).Error; err != nil {
+			return err
+		}
+		if role.Name == oldName {
+			return nil
+		}
+		return tx.Model(&model.UserGroupRole{}).
+			Where("group_id = ? AND role = ?", role.GroupID, oldName).
+			Update("role", role.Name).Error
+	})
+}
+
+// DeleteRole removes a custom role.
+func (r *RoleRepository) DeleteRole(id uint) error {
+	return r.db.Delete(&model.CustomRole{}, id).Error
+}
+
+// CountRoleMembers returns how many members of a group hold the role.
+func (r *RoleRepository) CountRoleMembers(groupID uint, name string) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.UserGroupRole{}).
+		Where("group_id = ? AND role = ?", groupID, name).
+		Count(&count).Error
+	return count, err
+}
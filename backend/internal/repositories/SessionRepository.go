@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"band-manager-backend/internal/db"
+	"band-manager-backend/internal/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SessionRepository handles database operations for user sessions.
+type SessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository() *SessionRepository {
+	return &SessionRepository{
+		db: db.GetDB(),
+	}
+}
+
+// CreateSession persists a new session to the database.
+func (r *SessionRepository) CreateSession(session *model.Session) error {
+	return r.db.Create(session).Error
+}
+
+// GetActiveSession retrieves a session that is neither revoked nor expired.
+func (r *SessionRepository) GetActiveSession(id uint) (*model.Session, error) {
+	var session model.Session
+	err := r.db.Where("id = ? AND revoked_at IS NULL AND expires_at > ?", id, time.Now()).
+		First(&session).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// GetUserActiveSessions retrieves all active sessions of a user, most recently used first.
+func (r *SessionRepository) GetUserActiveSessions(userID uint) ([]*model.Session, error) {
+	var sessions []*model.Session
+	err := r.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at desc").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// TouchSession updates the last activity time of a session.
+func (r *SessionRepository) TouchSession(id uint, lastSeenAt time.Time) error {
+	return r.db.Model(&model.Session{}).
+		Where("id = ?", id).
+		Update("last_seen_at", lastSeenAt).Error
+}
+
+// RotateRefreshToken replaces the refresh token of an active session and
+// moves its expiry forward, if previousTokenID is still its refresh token.
+// It reports whether it was, so that a refresh token works only once even
+// for concurrent requests.
+func (r *SessionRepository) RotateRefreshToken(id uint, previousTokenID, tokenID string, expiresAt time.Time) (bool, error) {
+	now := time.Now()
+	result := r.db.Model(&model.Session{}).
+		Where("id = ? AND refresh_token_id = ? AND revoked_at IS NULL AND expires_at > ?", id, previousTokenID, now).
+		Updates(map[string]interface{}{
+			"refresh_token_id": tokenID,
+			"expires_at":       expiresAt,
+			"last_seen_at":     now,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+// RevokeSession marks a single session as revoked.
+func (r *SessionRepository) RevokeSession(id uint) error {
+	return r.db.Model(&model.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeUserSessions revokes every session of a user except the given one.
+// Passing exceptID 0 revokes all of them.
+func (r *SessionRepository) RevokeUserSessions(userID uint, exceptID uint) error {
+	return r.db.Model(&model.Session{}).
+		Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, exceptID).
+		Update("revoked_at", time.Now()).Error
+}
@@ -0,0 +1,129 @@
+package repositories
+
+import (
+	"band-manager-backend/internal/db"
+	"band-manager-backend/internal/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SetlistRepository handles database operations for setlist entries of events.
+type SetlistRepository struct {
+	db *gorm.DB
+}
+
+func NewSetlistRepository() *SetlistRepository {
+	return &SetlistRepository{
+		db: db.GetDB(),
+	}
+}
+
+// GetSetlist retrieves the setlist entries of an event with their tracks in running order.
+func (r *SetlistRepository) GetSetlist(eventID uint) ([]model.Performance, error) {
+	var entries []model.Performance
+	err := r.db.Preload("Track").Where("event_id = ?", eventID).Order("position").Find(&entries).Error
+	return entries, err
+}
+
+// GetEntryByID retrieves a setlist entry by its ID.
+func (r *SetlistRepository) GetEntryByID(id uint) (*model.Performance, error) {
+	var entry model.Performance
+	if err := r.db.First(&entry, id).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// CreateEntry inserts an entry at its position, moving the following entries
+// down. Entries without a valid position are appended.
+func (r *SetlistRepository) CreateEntry(entry *model.Performance) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&model.Performance{}).Where("event_id = ?", entry.EventID).Count(&count).Error; err != nil {
+			return err
+		}
+
+		if entry.Position < 1 || entry.Position > int(count) {
+			entry.Position = int(count) + 1
+		} else if err := tx.Model(&model.Performance{}).
+			Where("event_id = ? AND position >= ?", entry.EventID, entry.Position).
+			Update("position", gorm.Expr("position + 1")).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(entry).Error
+	})
+}
+
+// UpdateEntry saves the details of an entry.
+func (r *SetlistRepository) UpdateEntry(entry *model.Performance) error {
+	return r.db.Save(entry).Error
+}
+
+// DeleteEntry removes an entry and closes the gap in the positions.
+func (r *SetlistRepository) DeleteEntry(entry *model.Performance) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&model.Performance{}, entry.ID).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.Performance{}).
+			Where("event_id = ? AND position > ?", entry.EventID, entry.Position).
+			Update("position", gorm.Expr("position - 1")).Error
+	})
+}
+
+// MoveEntry moves an entry to a new position, shifting the entries in between.
+func (r *SetlistRepository) MoveEntry(entry *model.Performance, position int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		shifted := tx.Model(&model.Performance{}).Where("event_id = ? AND id <> ?", entry.EventID, entry.ID)
+		var err error
+		if position < entry.Position {
+			err = shifted.Where("position >= ? AND position < ?", position, entry.Position).
+				Update("position", gorm.Expr("position + 1")).Error
+		} else {
+			err = shifted.Where("position > ? AND position <= ?", entry.Position, position).
+				Update("position", gorm.Expr("position - 1")).Error
+		}
+		if err != nil {
+			return err
+		}
+		return tx.Model(&model.Performance{}).Where("id = ?", entry.ID).Update("position", position).Error
+	})
+}
+
+// SetOrder numbers the entries of an event in the given order of their IDs.
+func (r *SetlistRepository) SetOrder(eventID uint, entryIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i, entryID := range entryIDs {
+			if err := tx.Model(&model.Performance{}).
+				Where("id = ? AND event_id = ?", entryID, eventID).
+				Update("position", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// CopySetlist copies the setlist of one event to another, for occurrences
+// of a series edited separately. Pinned start times are moved by offset.
+func (r *SetlistRepository) CopySetlist(fromEventID, toEventID uint, offset time.Duration) error {
+	var entries []model.Performance
+	if err := r.db.Where("event_id = ?", fromEventID).Order("position").Find(&entries).Error; err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	for i := range entries {
+		entries[i].ID = 0
+		entries[i].EventID = toEventID
+		if entries[i].StartTime != nil {
+			start := entries[i].StartTime.Add(offset)
+			entries[i].StartTime = &start
+		}
+	}
+	return r.db.Create(&entries).Error
+}
@@ -72,6 +72,32 @@ func (r *SubgroupRepository) RemoveMember(subgroupID uint, userID uint) error {
 	return r.db.Model(&subgroup).Association("Users").Delete(&user)
 }
 
+// GetMemberRole retrieves a user's role within a subgroup.
+func (r *SubgroupRepository) GetMemberRole(subgroupID, userID uint) (string, error) {
+	var membership model.SubgroupUser
+	err := r.db.Where("subgroup_id = ? AND user_id = ?", subgroupID, userID).First(&membership).Error
+	if err != nil {
+		return "", err
+	}
+	return membership.Role, nil
+}
+
+// UpdateMemberRole changes a user's role within a subgroup.
+func (r *SubgroupRepository) UpdateMemberRole(subgroupID, userID uint, role string) error {
+	return r.db.Model(&model.SubgroupUser{}).
+		Where("subgroup_id = ? AND user_id = ?", subgroupID, userID).
+		Update("role", role).Error
+}
+
+// GetLeaderIDs retrieves the IDs of the leaders of a subgroup.
+func (r *SubgroupRepository) GetLeaderIDs(subgroupID uint, leaderRole string) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&model.SubgroupUser{}).
+		Where("subgroup_id = ? AND role = ?", subgroupID, leaderRole).
+		Pluck("user_id", &ids).Error
+	return ids, err
+}
+
 // GetGroupSubgroups retrieves all subgroups for a specific group.
 func (r *SubgroupRepository) GetGroupSubgroups(groupID uint) ([]*model.Subgroup, error) {
 	var subgroups []*model.Subgroup
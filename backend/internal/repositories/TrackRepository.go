@@ -5,6 +5,7 @@ import (
 	"band-manager-backend/internal/model"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // TrackRepository handles database operations for tracks and notesheets.
@@ -37,18 +38,37 @@ func (r *TrackRepository) UpdateTrack(track *model.Track) error {
 	return r.db.Save(track).Error
 }
 
-// DeleteTrack removes a track and its associated resources.
+// DeleteTrack removes a track and its associated resources, releasing the
+// files of its notesheets.
 func (r *TrackRepository) DeleteTrack(id uint) error {
 	var track model.Track
 	if err := r.db.First(&track, id).Error; err != nil {
 		return err
 	}
 
-	if err := r.db.Model(&track).Association("Events").Clear(); err != nil {
-		return err
-	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var filepaths, versionFilepaths []string
+		if err := tx.Model(&model.Notesheet{}).Where("track_id = ?", id).Pluck("filepath", &filepaths).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.NotesheetVersion{}).
+			Joins("JOIN notesheets ON notesheets.id = notesheet_versions.notesheet_id").
+			Where("notesheets.track_id = ?", id).
+			Pluck("notesheet_versions.filepath", &versionFilepaths).Error; err != nil {
+			return err
+		}
+		for _, filepath := range append(filepaths, versionFilepaths...) {
+			if err := releaseBlob(tx, filepath); err != nil {
+				return err
+			}
+		}
 
-	return r.db.Delete(&track).Error
+		if err := tx.Model(&track).Association("Events").Clear(); err != nil {
+			return err
+		}
+
+		return tx.Delete(&track).Error
+	})
 }
 
 // GetGroupTracks retrieves all tracks for a specific group.
@@ -62,29 +82,59 @@ func (r *TrackRepository) GetGroupTracks(groupID uint) ([]*model.Track, error) {
 	return tracks, nil
 }
 
-// AddNotesheetToTrack creates a new notesheet and associates it with a track.
-func (r *TrackRepository) AddNotesheetToTrack(notesheet *model.Notesheet, subgroupIDs []uint) error {
-	if err := r.db.Create(notesheet).Error; err != nil {
-		return err
-	}
-
-	if len(subgroupIDs) > 0 {
-		var subgroups []model.Subgroup
-		if err := r.db.Find(&subgroups, subgroupIDs).Error; err != nil {
+// AddNotesheetToTrack creates a new notesheet and associates it with a track,
+// counting the reference to its file. A notesheet with a file gets it as its
+// first version, described by firstVersion if not nil.
+func (r *TrackRepository) AddNotesheetToTrack(notesheet *model.Notesheet, subgroupIDs []uint, firstVersion *model.NotesheetVersion) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if notesheet.Filepath != "" {
+			notesheet.CurrentVersion = 1
+		}
+		if err := tx.Create(notesheet).Error; err != nil {
 			return err
 		}
-		return r.db.Model(notesheet).Association("Subgroups").Append(&subgroups)
-	}
-	return nil
+		if err := acquireBlob(tx, notesheet.Filepath); err != nil {
+			return err
+		}
+
+		if notesheet.Filepath != "" {
+			if firstVersion == nil {
+				firstVersion = &model.NotesheetVersion{}
+			}
+			firstVersion.NotesheetID = notesheet.ID
+			firstVersion.Version = 1
+			firstVersion.Filepath = notesheet.Filepath
+			firstVersion.FileName = notesheet.FileName
+			firstVersion.FileType = notesheet.FileType
+			if err := tx.Create(firstVersion).Error; err != nil {
+				return err
+			}
+			if err := acquireBlob(tx, firstVersion.Filepath); err != nil {
+				return err
+			}
+		}
+
+		if len(subgroupIDs) > 0 {
+			var subgroups []model.Subgroup
+			if err := tx.Find(&subgroups, subgroupIDs).Error; err != nil {
+				return err
+			}
+			return tx.Model(notesheet).Association("Subgroups").Append(&subgroups)
+		}
+		return nil
+	})
 }
 
-// GetUserNotesheets retrieves notesheets available to a specific user.
+// GetUserNotesheets retrieves the notesheets of a track for the subgroups a
+// user is in and for the instruments they play.
 func (r *TrackRepository) GetUserNotesheets(trackID, userID uint) ([]*model.Notesheet, error) {
 	var notesheets []*model.Notesheet
-	err := r.db.Joins("JOIN notesheet_subgroup ON notesheets.id = notesheet_subgroup.notesheet_id").
-		Joins("JOIN subgroup_user ON notesheet_subgroup.subgroup_id = subgroup_user.subgroup_id").
-		Where("subgroup_user.user_id = ? AND notesheets.track_id = ?", userID, trackID).
-		Distinct().
+	err := r.db.Preload("CatalogueInstrument").
+		Where("notesheets.track_id = ?", trackID).
+		Where("(EXISTS (SELECT 1 FROM notesheet_subgroup "+
+			"JOIN subgroup_user ON notesheet_subgroup.subgroup_id = subgroup_user.subgroup_id "+
+			"WHERE notesheet_subgroup.notesheet_id = notesheets.id AND subgroup_user.user_id = ?) "+
+			"OR notesheets.instrument_id IN (SELECT instrument_id FROM instrument_user WHERE user_id = ?))", userID, userID).
 		Find(&notesheets).Error
 	return notesheets, err
 }
@@ -92,22 +142,134 @@ func (r *TrackRepository) GetUserNotesheets(trackID, userID uint) ([]*model.Note
 // GetTrackNotesheets retrieves all notesheets for a track.
 func (r *TrackRepository) GetTrackNotesheets(trackID uint) ([]*model.Notesheet, error) {
 	var notesheets []*model.Notesheet
-	err := r.db.Where("track_id = ?", trackID).Find(&notesheets).Error
+	err := r.db.Preload("CatalogueInstrument").Where("track_id = ?", trackID).Find(&notesheets).Error
 	return notesheets, err
 }
 
-// UpdateNotesheetFilepath updates the file path of a notesheet.
-func (r *TrackRepository) UpdateNotesheetFilepath(notesheetID uint, filepath string) error {
-	return r.db.Model(&model.Notesheet{}).
-		Where("id = ?", notesheetID).
-		Update("filepath", filepath).
-		Error
+// SetNotesheetInstrument changes which instrument a notesheet is a part for.
+func (r *TrackRepository) SetNotesheetInstrument(notesheet *model.Notesheet) error {
+	return r.db.Model(notesheet).Updates(map[string]interface{}{
+		"instrument":    notesheet.Instrument,
+		"instrument_id": notesheet.InstrumentID,
+	}).Error
+}
+
+// AddNotesheetVersion adds a new version of the file of a notesheet,
+// numbered after the latest one, and makes it the current version.
+func (r *TrackRepository) AddNotesheetVersion(version *model.NotesheetVersion) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var notesheet model.Notesheet
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&notesheet, version.NotesheetID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&model.NotesheetVersion{}).
+			Where("notesheet_id = ?", notesheet.ID).
+			Select("COALESCE(MAX(version), 0) + 1").
+			Scan(&version.Version).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(version).Error; err != nil {
+			return err
+		}
+		if err := acquireBlob(tx, version.Filepath); err != nil {
+			return err
+		}
+
+		return setCurrentVersion(tx, &notesheet, version)
+	})
+}
+
+// RestoreNotesheetVersion makes a version of the file of its notesheet the
+// current one again.
+func (r *TrackRepository) RestoreNotesheetVersion(version *model.NotesheetVersion) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var notesheet model.Notesheet
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&notesheet, version.NotesheetID).Error; err != nil {
+			return err
+		}
+		return setCurrentVersion(tx, &notesheet, version)
+	})
+}
+
+// GetNotesheetVersions retrieves the versions of the file of a notesheet,
+// newest first, with their uploaders.
+func (r *TrackRepository) GetNotesheetVersions(notesheetID uint) ([]*model.NotesheetVersion, error) {
+	var versions []*model.NotesheetVersion
+	err := r.db.Preload("UploadedBy").
+		Where("notesheet_id = ?", notesheetID).
+		Order("version DESC").
+		Find(&versions).Error
+	return versions, err
+}
+
+// GetNotesheetVersion retrieves a version of the file of a notesheet.
+func (r *TrackRepository) GetNotesheetVersion(id uint) (*model.NotesheetVersion, error) {
+	var version model.NotesheetVersion
+	if err := r.db.First(&version, id).Error; err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// GetNotesheetFilepaths returns the file paths of all notesheets and their versions.
+func (r *TrackRepository) GetNotesheetFilepaths() ([]string, error) {
+	var filepaths []string
+	err := r.db.Raw("SELECT filepath FROM notesheets WHERE filepath <> '' UNION SELECT filepath FROM notesheet_versions").
+		Scan(&filepaths).Error
+	return filepaths, err
+}
+
+// groupNotesheetVersions joins the versions of the notesheets of the group's tracks.
+const groupNotesheetVersions = "FROM notesheet_versions " +
+	"JOIN notesheets ON notesheets.id = notesheet_versions.notesheet_id " +
+	"JOIN tracks ON tracks.id = notesheets.track_id " +
+	"WHERE tracks.group_id = ?"
+
+// GetGroupStorageUsage returns the total size of the files of all versions
+// of the notesheets of a group, counting files used by several versions once.
+func (r *TrackRepository) GetGroupStorageUsage(groupID uint) (int64, error) {
+	var usage int64
+	err := r.db.Raw("SELECT COALESCE(SUM(size), 0) FROM (SELECT MAX(notesheet_versions.size) AS size "+
+		groupNotesheetVersions+" GROUP BY notesheet_versions.filepath) AS files", groupID).
+		Scan(&usage).Error
+	return usage, err
+}
+
+// GroupHasFile reports whether a version of a notesheet of the group uses the file.
+func (r *TrackRepository) GroupHasFile(groupID uint, filepath string) (bool, error) {
+	var exists bool
+	err := r.db.Raw("SELECT EXISTS (SELECT 1 "+groupNotesheetVersions+" AND notesheet_versions.filepath = ?)", groupID, filepath).
+		Scan(&exists).Error
+	return exists, err
+}
+
+// setCurrentVersion gives the notesheet the file of the version, moving the
+// reference from the previous file to it.
+func setCurrentVersion(tx *gorm.DB, notesheet *model.Notesheet, version *model.NotesheetVersion) error {
+	previous := notesheet.Filepath
+	if err := tx.Model(notesheet).Updates(map[string]interface{}{
+		"filepath":        version.Filepath,
+		"file_name":       version.FileName,
+		"file_type":       version.FileType,
+		"current_version": version.Version,
+	}).Error; err != nil {
+		return err
+	}
+
+	if previous == version.Filepath {
+		return nil
+	}
+	if err := acquireBlob(tx, version.Filepath); err != nil {
+		return err
+	}
+	return releaseBlob(tx, previous)
 }
 
-// GetNotesheet retrieves a notesheet by its ID.
+// GetNotesheet retrieves a notesheet by its ID with its target subgroups.
 func (r *TrackRepository) GetNotesheet(id uint) (*model.Notesheet, error) {
 	var notesheet model.Notesheet
-	if err := r.db.First(&notesheet, id).Error; err != nil {
+	if err := r.db.Preload("Subgroups").Preload("CatalogueInstrument").First(&notesheet, id).Error; err != nil {
 		return nil, err
 	}
 	return &notesheet, nil
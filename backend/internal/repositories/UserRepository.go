@@ -5,6 +5,7 @@ import (
 	"band-manager-backend/internal/model"
 	"errors"
 	"log"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
@@ -54,14 +55,19 @@ func (r *UserRepository) UpdateUser(user *model.User) error {
 	return nil
 }
 
-// DeleteUser removes a user from the database.
-func (r *UserRepository) DeleteUser(userID uint) error {
-	result := r.db.Delete(&model.User{}, userID)
-	if result.Error != nil {
-		return errors.New("nie udało się usunąć użytkownika")
-	}
-
-	return nil
+// DeleteUser removes a user from the database. In the groups where the user
+// is the only holder of keptRole, it is handed over first, to the member
+// holding successorRole or else any member with the lowest user ID.
+func (r *UserRepository) DeleteUser(userID uint, keptRole, successorRole string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := handOverRole(tx, userID, keptRole, successorRole); err != nil {
+			return err
+		}
+		if err := tx.Delete(&model.User{}, userID).Error; err != nil {
+			return errors.New("nie udało się usunąć użytkownika")
+		}
+		return nil
+	})
 }
 
 // GetUserByID retrieves a user by their ID.
@@ -96,6 +102,65 @@ func (r *UserRepository) ResetPassword(userID uint, newPassword string) error {
 	return r.db.Model(&model.User{}).Where("id = ?", userID).Update("password_hash", string(hashedPassword)).Error
 }
 
+// MarkEmailVerified flags a user's email address as confirmed.
+func (r *UserRepository) MarkEmailVerified(userID uint) error {
+	return r.db.Model(&model.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"email_verified": true,
+			"verified_at":    time.Now(),
+		}).Error
+}
+
+// SearchUsers retrieves a page of users whose name or email matches the query.
+func (r *UserRepository) SearchUsers(query string, offset, limit int) ([]*model.User, int64, error) {
+	tx := r.db.Model(&model.User{})
+	if query != "" {
+		pattern := "%" + query + "%"
+		tx = tx.Where("first_name ILIKE ? OR last_name ILIKE ? OR email ILIKE ?", pattern, pattern, pattern)
+	}
+
+	var total int64
+	if err := tx.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []*model.User
+	err := tx.Order("id").Offset(offset).Limit(limit).Find(&users).Error
+	return users, total, err
+}
+
+// SetUserAdmin grants or removes the system administrator flag.
+func (r *UserRepository) SetUserAdmin(userID uint, isAdmin bool) error {
+	return r.db.Model(&model.User{}).Where("id = ?", userID).Update("is_admin", isAdmin).Error
+}
+
+// SetUserDisabled enables or disables a user's account. Before an account is
+// disabled, keptRole is handed over in the groups where the user is its only
+// holder, as DeleteUser does.
+func (r *UserRepository) SetUserDisabled(userID uint, disabled bool, keptRole, successorRole string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if disabled {
+			if err := handOverRole(tx, userID, keptRole, successorRole); err != nil {
+				return err
+			}
+		}
+		return tx.Model(&model.User{}).Where("id = ?", userID).Update("disabled", disabled).Error
+	})
+}
+
+// SetEventReminders turns event reminders for a user on or off.
+func (r *UserRepository) SetEventReminders(userID uint, enabled bool) error {
+	return r.db.Model(&model.User{}).Where("id = ?", userID).Update("event_reminders", enabled).Error
+}
+
+// CountAdmins returns the number of system administrators.
+func (r *UserRepository) CountAdmins() (int64, error) {
+	var count int64
+	err := r.db.Model(&model.User{}).Where("is_admin = ?", true).Count(&count).Error
+	return count, err
+}
+
 // GetTotalUsers returns the total number of users in the system.
 func (r *UserRepository) GetTotalUsers() (int64, error) {
 	var count int64
@@ -5,80 +5,267 @@ import (
 	"encoding/base64"
 	"fmt"
 	"net/smtp"
+	"net/url"
 	"os"
+	"time"
 )
 
 type EmailService struct {
-	from     string
-	password string
-	smtpHost string
-	smtpPort string
+	from        string
+	password    string
+	smtpHost    string
+	smtpPort    string
+	frontendURL string
 }
 
 func NewEmailService() *EmailService {
+	frontendHost := os.Getenv("FRONTEND_HOST")
+	if frontendHost == "" {
+		frontendHost = "localhost"
+	}
+
+	frontendPort := os.Getenv("FRONTEND_PORT")
+	if frontendPort == "" {
+		frontendPort = "3000"
+	}
+
 	return &EmailService{
-		from:     os.Getenv("EMAIL_FROM"),
-		password: os.Getenv("APP_PASSWORD"),
-		smtpHost: os.Getenv("SMTP_HOST"),
-		smtpPort: os.Getenv("SMTP_PORT"),
+		from:        os.Getenv("EMAIL_FROM"),
+		password:    os.Getenv("APP_PASSWORD"),
+		smtpHost:    os.Getenv("SMTP_HOST"),
+		smtpPort:    os.Getenv("SMTP_PORT"),
+		frontendURL: fmt.Sprintf("http://%s:%s", frontendHost, frontendPort),
 	}
 }
 
-func (s *EmailService) SendEventEmail(event *model.Event, recipients []*model.User) error {
-	fmt.Printf("Config: host=%s, port=%s, from=%s\n", s.smtpHost, s.smtpPort, s.from)
-	if len(recipients) == 0 {
-		return nil
+// sendMail sends a single plain text UTF-8 message.
+func (s *EmailService) sendMail(to string, subject string, body string) error {
+	auth := smtp.PlainAuth("", s.from, s.password, s.smtpHost)
+	encodedSubject := fmt.Sprintf("=?UTF-8?B?%s?=", base64.StdEncoding.EncodeToString([]byte(subject)))
+
+	msg := []byte(fmt.Sprintf(
+		"To: %s\r\n"+
+			"From: %s\r\n"+
+			"Subject: %s\r\n"+
+			"MIME-Version: 1.0\r\n"+
+			"Content-Type: text/plain; charset=UTF-8\r\n"+
+			"Content-Transfer-Encoding: 8bit\r\n"+
+			"\r\n%s",
+		to,
+		s.from,
+		encodedSubject,
+		body,
+	))
+
+	return smtp.SendMail(s.smtpHost+":"+s.smtpPort, auth, s.from, []string{to}, msg)
+}
+
+// SendVerificationEmail sends a link confirming the user's email address.
+func (s *EmailService) SendVerificationEmail(user *model.User, token string) error {
+	link := fmt.Sprintf("%s/verify-email?token=%s", s.frontendURL, url.QueryEscape(token))
+	body := fmt.Sprintf(
+		"Cześć %s,\n\nDziękujemy za rejestrację w Band Manager.\n"+
+			"Aby potwierdzić swój adres email, otwórz link:\n%s\n\n"+
+			"Link wygaśnie po 24 godzinach.",
+		user.FirstName,
+		link,
+	)
+
+	if err := s.sendMail(user.Email, "Potwierdź adres email", body); err != nil {
+		fmt.Printf("Błąd wysyłania maila do %s: %v\n", user.Email, err)
+		return err
 	}
+	return nil
+}
 
-	auth := smtp.PlainAuth("", s.from, s.password, s.smtpHost)
-	fmt.Printf("Sending to: %v\n", recipients[0].Email)
+// SendPasswordResetEmail sends a link for setting a new password.
+func (s *EmailService) SendPasswordResetEmail(user *model.User, token string) error {
+	link := fmt.Sprintf("%s/reset-password?token=%s", s.frontendURL, url.QueryEscape(token))
+	body := fmt.Sprintf(
+		"Cześć %s,\n\nOtrzymaliśmy prośbę o zresetowanie hasła do Twojego konta.\n"+
+			"Aby ustawić nowe hasło, otwórz link:\n%s\n\n"+
+			"Link jest jednorazowy i wygaśnie po godzinie. Jeśli to nie Ty, zignoruj tę wiadomość.",
+		user.FirstName,
+		link,
+	)
 
-	subject := fmt.Sprintf("=?UTF-8?B?%s?=", base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("Nowe wydarzenie: %s", event.Title))))
+	if err := s.sendMail(user.Email, "Reset hasła", body); err != nil {
+		fmt.Printf("Błąd wysyłania maila do %s: %v\n", user.Email, err)
+		return err
+	}
+	return nil
+}
 
+// SendInvitationEmail sends a link for joining a group.
+func (s *EmailService) SendInvitationEmail(invitation *model.GroupInvitation, groupName string, token string) error {
+	link := fmt.Sprintf("%s/invitation?token=%s", s.frontendURL, url.QueryEscape(token))
+	body := fmt.Sprintf(
+		"Cześć,\n\nZostałeś zaproszony do zespołu %s w roli: %s.\n"+
+			"Aby dołączyć, otwórz link i zaloguj się lub załóż konto na ten adres e-mail:\n%s\n\n"+
+			"Link jest jednorazowy i wygaśnie %s.",
+		groupName,
+		invitation.Role,
+		link,
+		invitation.ExpiresAt.Format("02.01.2006 15:04"),
+	)
+
+	if err := s.sendMail(invitation.Email, "Zaproszenie do zespołu "+groupName, body); err != nil {
+		fmt.Printf("Błąd wysyłania maila do %s: %v\n", invitation.Email, err)
+		return err
+	}
+	return nil
+}
+
+// SendJoinRequestDecisionEmail tells a user whether their request to join a group was approved.
+func (s *EmailService) SendJoinRequestDecisionEmail(user *model.User, groupName string, approved bool) error {
+	decision := "odrzucona"
+	if approved {
+		decision = "zaakceptowana"
+	}
+	body := fmt.Sprintf(
+		"Cześć %s,\n\nTwoja prośba o dołączenie do zespołu %s została %s.",
+		user.FirstName,
+		groupName,
+		decision,
+	)
+
+	if err := s.sendMail(user.Email, "Prośba o dołączenie do zespołu "+groupName, body); err != nil {
+		fmt.Printf("Błąd wysyłania maila do %s: %v\n", user.Email, err)
+		return err
+	}
+	return nil
+}
+
+// SendEventEmail notifies a recipient about an event. A non-empty response
+// token adds links for answering whether the recipient will attend.
+func (s *EmailService) SendEventEmail(event *model.Event, recipient *model.User, responseToken string) error {
+	subject := fmt.Sprintf("Nowe wydarzenie: %s", event.Title)
 	body := fmt.Sprintf(
 		"Nazwa: %s\nOpis: %s\nMiejsce: %s\nData: %s",
 		event.Title,
 		event.Description,
 		event.Location,
-		event.Date.Format("02.01.2006 15:04"),
+		formatEventTime(event),
 	)
+	if responseToken != "" {
+		body += "\n\n" + s.eventResponseLinks(responseToken)
+	}
 
-	for _, recipient := range recipients {
-		msg := []byte(fmt.Sprintf(
-			"To: %s\r\n"+
-				"From: %s\r\n"+
-				"Subject: %s\r\n"+
-				"MIME-Version: 1.0\r\n"+
-				"Content-Type: text/plain; charset=UTF-8\r\n"+
-				"Content-Transfer-Encoding: 8bit\r\n"+
-				"\r\n%s",
-			recipient.Email,
-			s.from,
-			subject,
-			body,
-		))
-
-		if err := smtp.SendMail(
-			s.smtpHost+":"+s.smtpPort,
-			auth,
-			s.from,
-			[]string{recipient.Email},
-			msg,
-		); err != nil {
-			fmt.Printf("Błąd wysyłania maila do %s: %v\n", recipient.Email, err)
+	if err := s.sendMail(recipient.Email, subject, body); err != nil {
+		fmt.Printf("Błąd wysyłania maila do %s: %v\n", recipient.Email, err)
+		return err
+	}
+	return nil
+}
+
+// formatEventTime renders the start and end of an event in its time zone.
+func formatEventTime(event *model.Event) string {
+	location, err := time.LoadLocation(event.TimeZone)
+	if err != nil || event.TimeZone == "" {
+		location = time.UTC
+	}
+	start, end := event.Date.In(location), event.EndDate.In(location)
+
+	if event.AllDay {
+		if event.EndDate.IsZero() || end.Format("2006-01-02") == start.Format("2006-01-02") {
+			return start.Format("02.01.2006") + " (cały dzień)"
 		}
+		return fmt.Sprintf("%s - %s (cały dzień)", start.Format("02.01.2006"), end.Format("02.01.2006"))
+	}
+
+	if event.EndDate.IsZero() {
+		return fmt.Sprintf("%s (%s)", start.Format("02.01.2006 15:04"), location)
+	}
+	if end.Format("2006-01-02") == start.Format("2006-01-02") {
+		return fmt.Sprintf("%s - %s (%s)", start.Format("02.01.2006 15:04"), end.Format("15:04"), location)
+	}
+	return fmt.Sprintf("%s - %s (%s)", start.Format("02.01.2006 15:04"), end.Format("02.01.2006 15:04"), location)
+}
+
+// SendEventReminderEmail reminds a recipient of an upcoming event.
+func (s *EmailService) SendEventReminderEmail(event *model.Event, recipient *model.User) error {
+	subject := fmt.Sprintf("Przypomnienie: %s", event.Title)
+	body := fmt.Sprintf(
+		"Cześć %s,\n\nprzypominamy o wydarzeniu zespołu %s.\n\nNazwa: %s\nMiejsce: %s\nData: %s",
+		recipient.FirstName,
+		event.Group.Name,
+		event.Title,
+		event.Location,
+		formatEventTime(event),
+	)
+
+	if err := s.sendMail(recipient.Email, subject, body); err != nil {
+		fmt.Printf("Błąd wysyłania maila do %s: %v\n", recipient.Email, err)
+		return err
+	}
+	return nil
+}
+
+// SendEventChangeEmail tells a recipient that the time or place of an event
+// changed from those of previous.
+func (s *EmailService) SendEventChangeEmail(event, previous *model.Event, recipient *model.User) error {
+	subject := fmt.Sprintf("Zmiana wydarzenia: %s", event.Title)
+	body := fmt.Sprintf(
+		"Cześć %s,\n\nzmieniono termin lub miejsce wydarzenia zespołu %s.\n\nNazwa: %s\nMiejsce: %s\nData: %s",
+		recipient.FirstName,
+		event.Group.Name,
+		event.Title,
+		event.Location,
+		formatEventTime(event),
+	)
+	if previous.Location != event.Location {
+		body += fmt.Sprintf("\nPoprzednie miejsce: %s", previous.Location)
+	}
+	if previousTime := formatEventTime(previous); previousTime != formatEventTime(event) {
+		body += fmt.Sprintf("\nPoprzednia data: %s", previousTime)
+	}
+
+	if err := s.sendMail(recipient.Email, subject, body); err != nil {
+		fmt.Printf("Błąd wysyłania maila do %s: %v\n", recipient.Email, err)
+		return err
 	}
 	return nil
 }
 
-func (s *EmailService) SendAnnouncementEmail(announcement *model.Announcement, recipients []*model.User) error {
-	if len(recipients) == 0 {
-		return nil
+// SendEventCancelEmail tells a recipient that an event is cancelled. With
+// series, all occurrences of a recurring event from its date on are cancelled.
+func (s *EmailService) SendEventCancelEmail(event *model.Event, series bool, recipient *model.User) error {
+	subject := fmt.Sprintf("Odwołane wydarzenie: %s", event.Title)
+	cancelled := fmt.Sprintf("wydarzenie zespołu %s zostało odwołane.", event.Group.Name)
+	if series {
+		cancelled = fmt.Sprintf("wydarzenie cykliczne zespołu %s zostało odwołane od podanego terminu.", event.Group.Name)
 	}
+	body := fmt.Sprintf(
+		"Cześć %s,\n\n%s\n\nNazwa: %s\nMiejsce: %s\nData: %s",
+		recipient.FirstName,
+		cancelled,
+		event.Title,
+		event.Location,
+		formatEventTime(event),
+	)
 
-	auth := smtp.PlainAuth("", s.from, s.password, s.smtpHost)
-	subject := fmt.Sprintf("=?UTF-8?B?%s?=", base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("Nowe ogłoszenie: %s", announcement.Title))))
+	if err := s.sendMail(recipient.Email, subject, body); err != nil {
+		fmt.Printf("Błąd wysyłania maila do %s: %v\n", recipient.Email, err)
+		return err
+	}
+	return nil
+}
 
+// eventResponseLinks renders links for answering an event invitation.
+func (s *EmailService) eventResponseLinks(token string) string {
+	link := func(status string) string {
+		return fmt.Sprintf("%s/event-response?token=%s&status=%s", s.frontendURL, url.QueryEscape(token), status)
+	}
+	return fmt.Sprintf(
+		"Czy będziesz obecny?\nTak: %s\nMoże: %s\nNie: %s",
+		link("yes"),
+		link("maybe"),
+		link("no"),
+	)
+}
+
+// SendAnnouncementEmail sends an announcement to a recipient.
+func (s *EmailService) SendAnnouncementEmail(announcement *model.Announcement, recipient *model.User) error {
 	priorityText := "Normalne"
 	if announcement.Priority > 1 {
 		priorityText = "Ważne"
@@ -97,30 +284,9 @@ func (s *EmailService) SendAnnouncementEmail(announcement *model.Announcement, r
 		announcement.Sender.LastName,
 	)
 
-	for _, recipient := range recipients {
-		msg := []byte(fmt.Sprintf(
-			"To: %s\r\n"+
-				"From: %s\r\n"+
-				"Subject: %s\r\n"+
-				"MIME-Version: 1.0\r\n"+
-				"Content-Type: text/plain; charset=UTF-8\r\n"+
-				"Content-Transfer-Encoding: 8bit\r\n"+
-				"\r\n%s",
-			recipient.Email,
-			s.from,
-			subject,
-			body,
-		))
-
-		if err := smtp.SendMail(
-			s.smtpHost+":"+s.smtpPort,
-			auth,
-			s.from,
-			[]string{recipient.Email},
-			msg,
-		); err != nil {
-			fmt.Printf("Błąd wysyłania maila do %s: %v\n", recipient.Email, err)
-		}
+	if err := s.sendMail(recipient.Email, fmt.Sprintf("Nowe ogłoszenie: %s", announcement.Title), body); err != nil {
+		fmt.Printf("Błąd wysyłania maila do %s: %v\n", recipient.Email, err)
+		return err
 	}
 	return nil
 }
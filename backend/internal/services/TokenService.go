@@ -0,0 +1,128 @@
+package services
+
+import (
+	"band-manager-backend/internal/config"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
+// TokenClaims holds the payload of a signed token.
+type TokenClaims struct {
+	UserID    uint   `json:"uid"`
+	SessionID uint   `json:"sid"`
+	Type      string `json:"typ"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+	// TokenID tells refresh tokens of a session apart, so that only the
+	// latest one issued is accepted.
+	TokenID string `json:"jti,omitempty"`
+}
+
+// TokenService issues and verifies HS256-signed JSON Web Tokens.
+type TokenService struct {
+	secret          []byte
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
+}
+
+func NewTokenService(cfg *config.Config) *TokenService {
+	return &TokenService{
+		secret:          []byte(cfg.AuthConfig.JWTSecret),
+		accessTokenTTL:  cfg.AuthConfig.AccessTokenTTL,
+		refreshTokenTTL: cfg.AuthConfig.RefreshTokenTTL,
+	}
+}
+
+// AccessTokenTTL returns the lifetime of issued access tokens.
+func (s *TokenService) AccessTokenTTL() time.Duration {
+	return s.accessTokenTTL
+}
+
+// RefreshTokenTTL returns the lifetime of issued refresh tokens.
+func (s *TokenService) RefreshTokenTTL() time.Duration {
+	return s.refreshTokenTTL
+}
+
+// IssueAccessToken creates a short-lived token identifying the user and their session.
+func (s *TokenService) IssueAccessToken(userID, sessionID uint) (string, error) {
+	return s.sign(userID, sessionID, TokenTypeAccess, s.accessTokenTTL, "")
+}
+
+// IssueRefreshToken creates a long-lived token with the given ID used to
+// obtain new access tokens.
+func (s *TokenService) IssueRefreshToken(userID, sessionID uint, tokenID string) (string, error) {
+	return s.sign(userID, sessionID, TokenTypeRefresh, s.refreshTokenTTL, tokenID)
+}
+
+// Verify checks the signature, type and expiry of a token and returns its claims.
+func (s *TokenService) Verify(token string, tokenType string) (*TokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	expected := s.signature(parts[0] + "." + parts[1])
+	actual, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(expected, actual) {
+		return nil, errors.New("invalid token signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, errors.New("malformed token")
+	}
+
+	var claims TokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, errors.New("malformed token")
+	}
+
+	if claims.Type != tokenType {
+		return nil, errors.New("invalid token type")
+	}
+
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return nil, errors.New("token expired")
+	}
+
+	return &claims, nil
+}
+
+func (s *TokenService) sign(userID, sessionID uint, tokenType string, ttl time.Duration, tokenID string) (string, error) {
+	now := time.Now()
+	header, err := json.Marshal(map[string]string{"alg": "HS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(TokenClaims{
+		UserID:    userID,
+		SessionID: sessionID,
+		Type:      tokenType,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+		TokenID:   tokenID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(s.signature(unsigned)), nil
+}
+
+func (s *TokenService) signature(data string) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
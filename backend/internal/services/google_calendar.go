@@ -2,35 +2,36 @@ package services
 
 import (
 	"band-manager-backend/internal/config"
-	"band-manager-backend/internal/db"
 	"band-manager-backend/internal/model"
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"time"
 
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/option"
 )
 
-type GoogleCalendarService struct {
-	config *oauth2.Config
+// GoogleCalendarAPI is the part of the Google OAuth and Calendar APIs used to
+// sync events, so that the service can run against a local fake.
+type GoogleCalendarAPI interface {
+	AuthCodeURL(state string) string
+	Exchange(ctx context.Context, code string) (*oauth2.Token, error)
+	TokenSource(ctx context.Context, token *oauth2.Token) oauth2.TokenSource
+	InsertEvent(ctx context.Context, tokens oauth2.TokenSource, event *calendar.Event) (string, error)
+	UpdateEvent(ctx context.Context, tokens oauth2.TokenSource, calendarEventID string, event *calendar.Event) error
+	// DeleteEvent succeeds for events that no longer exist.
+	DeleteEvent(ctx context.Context, tokens oauth2.TokenSource, calendarEventID string) error
 }
 
-func (s *GoogleCalendarService) GetOrCreateToken() (*oauth2.Token, error) {
-	var gToken model.GoogleToken
-	result := db.GetDB().First(&gToken)
-	if result.Error != nil {
-		return nil, fmt.Errorf("no token found - authorization required")
-	}
-	return &oauth2.Token{
-		AccessToken:  gToken.AccessToken,
-		TokenType:    gToken.TokenType,
-		RefreshToken: gToken.RefreshToken,
-		Expiry:       gToken.Expiry,
-	}, nil
+// GoogleCalendarService pushes events to the primary Google calendars of users.
+type GoogleCalendarService struct {
+	api GoogleCalendarAPI
 }
 
 func NewGoogleCalendarService(cfg *config.Config) (*GoogleCalendarService, error) {
@@ -44,82 +45,160 @@ func NewGoogleCalendarService(cfg *config.Config) (*GoogleCalendarService, error
 		return nil, fmt.Errorf("unable to parse client secret file to config: %v", err)
 	}
 
+	return NewGoogleCalendarServiceWithAPI(&googleCalendarAPI{config: config}), nil
+}
+
+// NewGoogleCalendarServiceWithAPI creates a service using the given API implementation.
+func NewGoogleCalendarServiceWithAPI(api GoogleCalendarAPI) *GoogleCalendarService {
 	return &GoogleCalendarService{
-		config: config,
-	}, nil
+		api: api,
+	}
 }
 
-// Tu będą dodatkowe metody serwisu...
+// GetAuthURL returns the consent page URL carrying the given OAuth state.
+// Offline access is requested so that events can be synced later on.
+func (s *GoogleCalendarService) GetAuthURL(state string) string {
+	return s.api.AuthCodeURL(state)
+}
+
+// ExchangeCode exchanges an authorization code for a token.
+func (s *GoogleCalendarService) ExchangeCode(code string) (*oauth2.Token, error) {
+	return s.api.Exchange(context.Background(), code)
+}
 
-func (s *GoogleCalendarService) CreateCalendarEvent(event *model.Event) error {
+// TokenSource returns a token source refreshing the token when it expires.
+// The current token can be read back from it to persist refreshed tokens.
+func (s *GoogleCalendarService) TokenSource(token *oauth2.Token) oauth2.TokenSource {
+	return s.api.TokenSource(context.Background(), token)
+}
 
-	token, err := s.GetOrCreateToken()
+// CreateCalendarEvent adds the event to the calendar and returns the ID Google assigned to it.
+func (s *GoogleCalendarService) CreateCalendarEvent(tokens oauth2.TokenSource, event *model.Event) (string, error) {
+	id, err := s.api.InsertEvent(context.Background(), tokens, calendarEvent(event))
 	if err != nil {
-		return err
+		return "", fmt.Errorf("unable to create event in calendar: %v", err)
 	}
+	return id, nil
+}
 
-	ctx := context.Background()
-	srv, err := calendar.NewService(ctx, option.WithTokenSource(
-		s.config.TokenSource(ctx, token),
-	))
-	if err != nil {
-		return fmt.Errorf("unable to create calendar client: %v", err)
+// UpdateCalendarEvent replaces the details of a previously created calendar event.
+func (s *GoogleCalendarService) UpdateCalendarEvent(tokens oauth2.TokenSource, calendarEventID string, event *model.Event) error {
+	if err := s.api.UpdateEvent(context.Background(), tokens, calendarEventID, calendarEvent(event)); err != nil {
+		return fmt.Errorf("unable to update event in calendar: %v", err)
+	}
+	return nil
+}
+
+// DeleteCalendarEvent removes a previously created calendar event.
+func (s *GoogleCalendarService) DeleteCalendarEvent(tokens oauth2.TokenSource, calendarEventID string) error {
+	if err := s.api.DeleteEvent(context.Background(), tokens, calendarEventID); err != nil {
+		return fmt.Errorf("unable to delete event from calendar: %v", err)
 	}
+	return nil
+}
 
-	calendarEvent := &calendar.Event{
+// calendarEvent converts an event to a Google Calendar event. Recurring
+// events keep their rule; all their exceptions are excluded, as occurrences
+// edited separately are synced as events of their own.
+func calendarEvent(event *model.Event) *calendar.Event {
+	start, end := calendarEventTimes(event)
+	result := &calendar.Event{
 		Summary:     event.Title,
 		Location:    event.Location,
 		Description: event.Description,
-		Start: &calendar.EventDateTime{
-			DateTime: event.Date.Format(time.RFC3339),
-			TimeZone: "Europe/Warsaw",
-		},
-		End: &calendar.EventDateTime{
-			DateTime: event.Date.Add(2 * time.Hour).Format(time.RFC3339), // Domyślnie 2h
-			TimeZone: "Europe/Warsaw",
-		},
+		Start:       start,
+		End:         end,
 	}
 
-	createdEvent, err := srv.Events.Insert("primary", calendarEvent).Do()
-	if err != nil {
-		return fmt.Errorf("unable to create event in calendar: %v", err)
+	if event.RecurrenceRule != "" {
+		timeZone, location := calendarTimeZone(event)
+		result.Recurrence = []string{"RRULE:" + event.RecurrenceRule}
+		for _, exception := range event.Exceptions {
+			occurrence := exception.OccurrenceDate.In(location)
+			if event.AllDay {
+				result.Recurrence = append(result.Recurrence, "EXDATE;VALUE=DATE:"+occurrence.Format("20060102"))
+			} else {
+				result.Recurrence = append(result.Recurrence, "EXDATE;TZID="+timeZone+":"+occurrence.Format("20060102T150405"))
+			}
+		}
+	}
+	return result
+}
+
+// calendarEventTimes converts the timing of an event to Google Calendar start
+// and end times. All-day events use dates with an exclusive end date.
+func calendarEventTimes(event *model.Event) (*calendar.EventDateTime, *calendar.EventDateTime) {
+	timeZone, location := calendarTimeZone(event)
+	end := event.EndDate
+	if end.IsZero() {
+		end = event.Date
 	}
 
-	// Zapisz powiązanie w bazie
-	gcEvent := &model.GoogleCalendarEvent{
-		EventID:    event.ID,
-		CalendarID: createdEvent.Id,
-		LastSynced: time.Now(),
+	if event.AllDay {
+		return &calendar.EventDateTime{Date: event.Date.In(location).Format("2006-01-02")},
+			&calendar.EventDateTime{Date: end.In(location).AddDate(0, 0, 1).Format("2006-01-02")}
+	}
+	return &calendar.EventDateTime{DateTime: event.Date.In(location).Format(time.RFC3339), TimeZone: timeZone},
+		&calendar.EventDateTime{DateTime: end.In(location).Format(time.RFC3339), TimeZone: timeZone}
+}
+
+// calendarTimeZone returns the time zone of an event, falling back to UTC.
+func calendarTimeZone(event *model.Event) (string, *time.Location) {
+	location, err := time.LoadLocation(event.TimeZone)
+	if err != nil || event.TimeZone == "" {
+		return "UTC", time.UTC
 	}
+	return event.TimeZone, location
+}
 
-	return db.GetDB().Create(gcEvent).Error
+// googleCalendarAPI calls the Google APIs.
+type googleCalendarAPI struct {
+	config *oauth2.Config
 }
 
-func (s *GoogleCalendarService) GetAuthURL() string {
-	return s.config.AuthCodeURL("state")
+func (a *googleCalendarAPI) AuthCodeURL(state string) string {
+	return a.config.AuthCodeURL(state, oauth2.AccessTypeOffline, oauth2.ApprovalForce)
 }
 
-func (s *GoogleCalendarService) SaveToken(code string) error {
-	fmt.Println("Exchanging authorization code for token...") // DEBUG
+func (a *googleCalendarAPI) Exchange(ctx context.Context, code string) (*oauth2.Token, error) {
+	return a.config.Exchange(ctx, code)
+}
+
+func (a *googleCalendarAPI) TokenSource(ctx context.Context, token *oauth2.Token) oauth2.TokenSource {
+	return a.config.TokenSource(ctx, token)
+}
 
-	token, err := s.config.Exchange(context.Background(), code)
+func (a *googleCalendarAPI) InsertEvent(ctx context.Context, tokens oauth2.TokenSource, event *calendar.Event) (string, error) {
+	srv, err := calendar.NewService(ctx, option.WithTokenSource(tokens))
 	if err != nil {
-		fmt.Println("Error exchanging code for token:", err) // DEBUG
-		return err
+		return "", fmt.Errorf("unable to create calendar client: %v", err)
 	}
-	fmt.Printf("Token received: %+v\n", token) // DEBUG
+	created, err := srv.Events.Insert("primary", event).Context(ctx).Do()
+	if err != nil {
+		return "", err
+	}
+	return created.Id, nil
+}
 
-	gToken := &model.GoogleToken{
-		AccessToken:  token.AccessToken,
-		TokenType:    token.TokenType,
-		RefreshToken: token.RefreshToken,
-		Expiry:       token.Expiry,
+func (a *googleCalendarAPI) UpdateEvent(ctx context.Context, tokens oauth2.TokenSource, calendarEventID string, event *calendar.Event) error {
+	srv, err := calendar.NewService(ctx, option.WithTokenSource(tokens))
+	if err != nil {
+		return fmt.Errorf("unable to create calendar client: %v", err)
 	}
-	fmt.Println("Saving token to database...") // DEBUG
+	_, err = srv.Events.Update("primary", calendarEventID, event).Context(ctx).Do()
+	return err
+}
 
-	err = db.GetDB().Create(gToken).Error
+func (a *googleCalendarAPI) DeleteEvent(ctx context.Context, tokens oauth2.TokenSource, calendarEventID string) error {
+	srv, err := calendar.NewService(ctx, option.WithTokenSource(tokens))
 	if err != nil {
-		fmt.Println("Error saving token to database:", err) // DEBUG
+		return fmt.Errorf("unable to create calendar client: %v", err)
+	}
+	err = srv.Events.Delete("primary", calendarEventID).Context(ctx).Do()
+
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && (apiErr.Code == http.StatusNotFound || apiErr.Code == http.StatusGone) {
+		return nil
 	}
 	return err
 }
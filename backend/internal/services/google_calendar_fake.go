@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/api/calendar/v3"
+)
+
+// FakeGoogleCalendarAPI is an in-memory GoogleCalendarAPI for tests and local
+// development. Each access token has a calendar of its own, and authorization
+// codes are exchanged for tokens with the same value.
+type FakeGoogleCalendarAPI struct {
+	mu        sync.Mutex
+	calendars map[string]map[string]*calendar.Event
+	nextID    int
+}
+
+func NewFakeGoogleCalendarAPI() *FakeGoogleCalendarAPI {
+	return &FakeGoogleCalendarAPI{
+		calendars: make(map[string]map[string]*calendar.Event),
+	}
+}
+
+func (f *FakeGoogleCalendarAPI) AuthCodeURL(state string) string {
+	return "https://accounts.example.test/auth?state=" + url.QueryEscape(state)
+}
+
+func (f *FakeGoogleCalendarAPI) Exchange(ctx context.Context, code string) (*oauth2.Token, error) {
+	if code == "" {
+		return nil, errors.New("invalid authorization code")
+	}
+	return &oauth2.Token{AccessToken: code, TokenType: "Bearer", RefreshToken: "refresh-" + code}, nil
+}
+
+func (f *FakeGoogleCalendarAPI) TokenSource(ctx context.Context, token *oauth2.Token) oauth2.TokenSource {
+	return oauth2.StaticTokenSource(token)
+}
+
+func (f *FakeGoogleCalendarAPI) InsertEvent(ctx context.Context, tokens oauth2.TokenSource, event *calendar.Event) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	events, err := f.calendar(tokens)
+	if err != nil {
+		return "", err
+	}
+	f.nextID++
+	id := fmt.Sprintf("fake-%d", f.nextID)
+	stored := *event
+	stored.Id = id
+	events[id] = &stored
+	return id, nil
+}
+
+func (f *FakeGoogleCalendarAPI) UpdateEvent(ctx context.Context, tokens oauth2.TokenSource, calendarEventID string, event *calendar.Event) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	events, err := f.calendar(tokens)
+	if err != nil {
+		return err
+	}
+	if _, ok := events[calendarEventID]; !ok {
+		return errors.New("event not found")
+	}
+	stored := *event
+	stored.Id = calendarEventID
+	events[calendarEventID] = &stored
+	return nil
+}
+
+func (f *FakeGoogleCalendarAPI) DeleteEvent(ctx context.Context, tokens oauth2.TokenSource, calendarEventID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	events, err := f.calendar(tokens)
+	if err != nil {
+		return err
+	}
+	delete(events, calendarEventID)
+	return nil
+}
+
+// Events returns the events in the calendar of the access token.
+func (f *FakeGoogleCalendarAPI) Events(accessToken string) map[string]*calendar.Event {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	events := make(map[string]*calendar.Event, len(f.calendars[accessToken]))
+	for id, event := range f.calendars[accessToken] {
+		events[id] = event
+	}
+	return events
+}
+
+// calendar returns the calendar of the token, creating it on first use.
+func (f *FakeGoogleCalendarAPI) calendar(tokens oauth2.TokenSource) (map[string]*calendar.Event, error) {
+	token, err := tokens.Token()
+	if err != nil {
+		return nil, err
+	}
+	events, ok := f.calendars[token.AccessToken]
+	if !ok {
+		events = make(map[string]*calendar.Event)
+		f.calendars[token.AccessToken] = events
+	}
+	return events, nil
+}
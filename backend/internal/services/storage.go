@@ -0,0 +1,145 @@
+package services
+
+import (
+	"band-manager-backend/internal/config"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ErrObjectNotFound is returned for keys with no stored object.
+var ErrObjectNotFound = errors.New("file not found")
+
+// ObjectInfo describes a stored object.
+type ObjectInfo struct {
+	Key         string
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+}
+
+// Storage keeps uploaded files under slash-separated keys such as
+// "notesheets/1_part.pdf". Keys are relative to the storage root, so that
+// files can be moved between servers and backends without rewriting the
+// references kept in the database.
+type Storage interface {
+	// Put stores the object, replacing any object with the same key. Size
+	// may be -1 when unknown.
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// GetRange reads length bytes starting at offset, or the rest of the
+	// object when length is -1.
+	GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error)
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	// Delete succeeds for objects that do not exist.
+	Delete(ctx context.Context, key string) error
+	// List returns the objects whose keys start with the prefix.
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+}
+
+// NewStorage creates the storage backend selected in the configuration.
+func NewStorage(cfg *config.Config) (Storage, error) {
+	storageConfig := cfg.StorageConfig
+	switch storageConfig.Backend {
+	case config.StorageBackendLocal:
+		return NewLocalStorage(storageConfig.LocalDir)
+	case config.StorageBackendS3:
+		return NewS3Storage(S3Options{
+			Endpoint:  storageConfig.S3Endpoint,
+			Region:    storageConfig.S3Region,
+			Bucket:    storageConfig.S3Bucket,
+			AccessKey: storageConfig.S3AccessKey,
+			SecretKey: storageConfig.S3SecretKey,
+			PathStyle: storageConfig.S3PathStyle,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", storageConfig.Backend)
+	}
+}
+
+// validateStorageKey rejects keys that could point outside the storage root.
+func validateStorageKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("invalid storage key %q", key)
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return fmt.Errorf("invalid storage key %q", key)
+		}
+	}
+	return nil
+}
+
+// ObjectReader reads a stored object with seeking, fetching only the parts
+// that are read. It can be served with http.ServeContent, which answers
+// range requests by seeking.
+type ObjectReader struct {
+	Info    *ObjectInfo
+	ctx     context.Context
+	storage Storage
+	offset  int64
+	body    io.ReadCloser
+}
+
+// OpenObject opens a stored object for reading.
+func OpenObject(ctx context.Context, storage Storage, key string) (*ObjectReader, error) {
+	info, err := storage.Stat(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return &ObjectReader{Info: info, ctx: ctx, storage: storage}, nil
+}
+
+func (r *ObjectReader) Read(p []byte) (int, error) {
+	if r.offset >= r.Info.Size {
+		return 0, io.EOF
+	}
+	if r.body == nil {
+		body, err := r.storage.GetRange(r.ctx, r.Info.Key, r.offset, -1)
+		if err != nil {
+			return 0, err
+		}
+		r.body = body
+	}
+
+	n, err := r.body.Read(p)
+	r.offset += int64(n)
+	if err == io.EOF && r.offset < r.Info.Size {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+func (r *ObjectReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		offset += r.Info.Size
+	}
+	if offset < 0 {
+		return 0, errors.New("negative position")
+	}
+
+	if offset != r.offset {
+		r.closeBody()
+		r.offset = offset
+	}
+	return offset, nil
+}
+
+func (r *ObjectReader) Close() error {
+	return r.closeBody()
+}
+
+func (r *ObjectReader) closeBody() error {
+	if r.body == nil {
+		return nil
+	}
+	err := r.body.Close()
+	r.body = nil
+	return err
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FakeS3Server is an in-memory stand-in for an S3-compatible service such
+// as MinIO, for tests and local development. It serves a single bucket,
+// addressed in the path or the host name, checks request signatures and
+// answers range requests.
+type FakeS3Server struct {
+	mu        sync.Mutex
+	bucket    string
+	accessKey string
+	secretKey string
+	objects   map[string]*fakeS3Object
+}
+
+type fakeS3Object struct {
+	data        []byte
+	contentType string
+	modTime     time.Time
+}
+
+func NewFakeS3Server(bucket, accessKey, secretKey string) *FakeS3Server {
+	return &FakeS3Server{
+		bucket:    bucket,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		objects:   make(map[string]*fakeS3Object),
+	}
+}
+
+func (f *FakeS3Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !f.authorized(r) {
+		fakeS3Error(w, http.StatusForbidden, "SignatureDoesNotMatch")
+		return
+	}
+
+	key, ok := f.objectKey(r)
+	if !ok {
+		fakeS3Error(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if key == "" {
+		if r.Method != http.MethodGet || r.URL.Query().Get("list-type") != "2" {
+			fakeS3Error(w, http.StatusNotImplemented, "NotImplemented")
+			return
+		}
+		f.list(w, r.URL.Query().Get("prefix"))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			fakeS3Error(w, http.StatusBadRequest, "IncompleteBody")
+			return
+		}
+		f.objects[key] = &fakeS3Object{
+			data:        data,
+			contentType: r.Header.Get("Content-Type"),
+			modTime:     time.Now().UTC().Truncate(time.Second),
+		}
+	case http.MethodGet, http.MethodHead:
+		object := f.objects[key]
+		if object == nil {
+			fakeS3Error(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		if object.contentType != "" {
+			w.Header().Set("Content-Type", object.contentType)
+		}
+		http.ServeContent(w, r, "", object.modTime, bytes.NewReader(object.data))
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		fakeS3Error(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+	}
+}
+
+// list answers a ListObjectsV2 request, listing all objects in one page.
+func (f *FakeS3Server) list(w http.ResponseWriter, prefix string) {
+	type content struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		LastModified time.Time `xml:"LastModified"`
+	}
+	result := struct {
+		XMLName     xml.Name  `xml:"ListBucketResult"`
+		Name        string    `xml:"Name"`
+		Prefix      string    `xml:"Prefix"`
+		IsTruncated bool      `xml:"IsTruncated"`
+		Contents    []content `xml:"Contents"`
+	}{Name: f.bucket, Prefix: prefix}
+
+	for key, object := range f.objects {
+		if strings.HasPrefix(key, prefix) {
+			result.Contents = append(result.Contents, content{key, int64(len(object.data)), object.modTime})
+		}
+	}
+	sort.Slice(result.Contents, func(i, j int) bool {
+		return result.Contents[i].Key < result.Contents[j].Key
+	})
+
+	w.Header().Set("Content-Type", "application/xml")
+	xml.NewEncoder(w).Encode(result)
+}
+
+// objectKey returns the key of the object the request is for, empty for
+// requests for the bucket itself.
+func (f *FakeS3Server) objectKey(r *http.Request) (string, bool) {
+	p := strings.TrimPrefix(r.URL.Path, "/")
+	if strings.HasPrefix(r.Host, f.bucket+".") {
+		return p, true
+	}
+	bucket, key, _ := strings.Cut(p, "/")
+	return key, bucket == f.bucket
+}
+
+// authorized checks the Signature Version 4 of the request.
+func (f *FakeS3Server) authorized(r *http.Request) bool {
+	authorization, found := strings.CutPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ")
+	if !found {
+		return false
+	}
+
+	fields := make(map[string]string)
+	for _, field := range strings.Split(authorization, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(field), "=")
+		fields[name] = value
+	}
+	accessKey, scope, _ := strings.Cut(fields["Credential"], "/")
+	if accessKey != f.accessKey {
+		return false
+	}
+
+	headers := make(map[string]string)
+	for _, name := range strings.Split(fields["SignedHeaders"], ";") {
+		if name == "host" {
+			headers[name] = r.Host
+		} else {
+			headers[name] = r.Header.Get(name)
+		}
+	}
+	signedHeaders, signature := s3Signature(f.secretKey, scope, r.Header.Get("X-Amz-Date"), r.Method, r.URL.EscapedPath(), r.URL.RawQuery, headers)
+	return signedHeaders == fields["SignedHeaders"] && signature == fields["Signature"]
+}
+
+func fakeS3Error(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<Error><Code>%s</Code></Error>", code)
+}
@@ -2,25 +2,64 @@ package usecases
 
 import (
 	"band-manager-backend/internal/domain"
+	"band-manager-backend/internal/model"
 	"band-manager-backend/internal/repositories"
+	"band-manager-backend/internal/services"
+	"band-manager-backend/internal/usecases/helpers"
+	"errors"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	defaultUserPageSize = 20
+	maxUserPageSize     = 100
+	defaultJobPageSize  = 50
+	maxJobPageSize      = 200
 )
 
 // AdminUsecase implements administrative operations.
 type AdminUsecase struct {
-	userRepo  *repositories.UserRepository
-	groupRepo *repositories.GroupRepository
+	userRepo    *repositories.UserRepository
+	groupRepo   *repositories.GroupRepository
+	sessionRepo *repositories.SessionRepository
+	resetRepo   *repositories.PasswordResetRepository
+	jobRepo     *repositories.JobRepository
+	blobs       *BlobStore
 }
 
-func NewAdminUsecase() *AdminUsecase {
+func NewAdminUsecase(storage services.Storage) *AdminUsecase {
 	return &AdminUsecase{
-		userRepo:  repositories.NewUserRepository(),
-		groupRepo: repositories.NewGroupRepository(),
+		userRepo:    repositories.NewUserRepository(),
+		groupRepo:   repositories.NewGroupRepository(),
+		sessionRepo: repositories.NewSessionRepository(),
+		resetRepo:   repositories.NewPasswordResetRepository(),
+		jobRepo:     repositories.NewJobRepository(),
+		blobs:       NewBlobStore(storage),
 	}
 }
 
-// ResetUserPassword changes a user's password to the provided new password.
+// ResetUserPassword changes a user's password to the provided new password
+// and invalidates outstanding reset links and sessions of that user.
 func (u *AdminUsecase) ResetUserPassword(userID uint, newPassword string) error {
-	return u.userRepo.ResetPassword(userID, newPassword)
+	if err := u.userRepo.ResetPassword(userID, newPassword); err != nil {
+		return err
+	}
+
+	if err := u.resetRepo.InvalidateUserTokens(userID); err != nil {
+		return err
+	}
+
+	return u.sessionRepo.RevokeUserSessions(userID, 0)
+}
+
+// RevokeUserSessions logs a user out of every device.
+func (u *AdminUsecase) RevokeUserSessions(userID uint) error {
+	if _, err := u.userRepo.GetUserByID(userID); err != nil {
+		return err
+	}
+	return u.sessionRepo.RevokeUserSessions(userID, 0)
 }
 
 // GetSystemStats retrieves system-wide statistics.
@@ -40,3 +79,194 @@ func (u *AdminUsecase) GetSystemStats() (domain.SystemStats, error) {
 		TotalGroups: totalGroups,
 	}, nil
 }
+
+// IsAdmin reports whether the user is an enabled system administrator.
+func (u *AdminUsecase) IsAdmin(userID uint) bool {
+	user, err := u.userRepo.GetUserByID(userID)
+	if err != nil {
+		return false
+	}
+	return user.IsAdmin && !user.Disabled
+}
+
+// BootstrapAdmin makes sure at least one administrator exists. When none does,
+// the account with the given email is promoted, or created if it is missing.
+// An existing account is only promoted when its email is verified and the
+// password is its own, so that nobody can register the address to take over.
+func (u *AdminUsecase) BootstrapAdmin(email, password string) error {
+	count, err := u.userRepo.CountAdmins()
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	if email == "" || password == "" {
+		return errors.New("no administrator exists and ADMIN_EMAIL/ADMIN_PASSWORD are not set")
+	}
+
+	if user, err := u.userRepo.GetUserByEmail(email); err == nil {
+		if !user.EmailVerified {
+			return errors.New("refusing to promote the account of ADMIN_EMAIL: its email is not verified")
+		}
+		if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+			return errors.New("refusing to promote the account of ADMIN_EMAIL: ADMIN_PASSWORD is not its password")
+		}
+		return u.userRepo.SetUserAdmin(user.ID, true)
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	return u.userRepo.CreateUser(&model.User{
+		FirstName:     "Admin",
+		LastName:      "Admin",
+		Email:         email,
+		PasswordHash:  string(hashedPassword),
+		EmailVerified: true,
+		VerifiedAt:    &now,
+		IsAdmin:       true,
+	})
+}
+
+// ListUsers returns a page of users matching the search query.
+func (u *AdminUsecase) ListUsers(query string, page, pageSize int) (domain.UserList, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultUserPageSize
+	}
+	if pageSize > maxUserPageSize {
+		pageSize = maxUserPageSize
+	}
+
+	users, total, err := u.userRepo.SearchUsers(query, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return domain.UserList{}, err
+	}
+
+	summaries := make([]domain.UserSummary, 0, len(users))
+	for _, user := range users {
+		summaries = append(summaries, domain.UserSummary{
+			ID:            user.ID,
+			FirstName:     user.FirstName,
+			LastName:      user.LastName,
+			Email:         user.Email,
+			EmailVerified: user.EmailVerified,
+			IsAdmin:       user.IsAdmin,
+			Disabled:      user.Disabled,
+		})
+	}
+
+	return domain.UserList{
+		Users: summaries,
+		Total: total,
+	}, nil
+}
+
+// SetUserDisabled disables or re-enables an account. Disabling logs the user
+// out everywhere and hands the groups the user is the only manager of to
+// another member, preferably a moderator.
+func (u *AdminUsecase) SetUserDisabled(requesterID, userID uint, disabled bool) error {
+	if requesterID == userID {
+		return errors.New("cannot disable your own account")
+	}
+
+	if _, err := u.userRepo.GetUserByID(userID); err != nil {
+		return err
+	}
+
+	if err := u.userRepo.SetUserDisabled(userID, disabled, helpers.RoleManager, helpers.RoleModerator); err != nil {
+		return err
+	}
+
+	if disabled {
+		return u.sessionRepo.RevokeUserSessions(userID, 0)
+	}
+	return nil
+}
+
+// SetUserAdmin grants or removes administrator rights of another user.
+func (u *AdminUsecase) SetUserAdmin(requesterID, userID uint, isAdmin bool) error {
+	if requesterID == userID {
+		return errors.New("cannot change your own administrator rights")
+	}
+
+	if _, err := u.userRepo.GetUserByID(userID); err != nil {
+		return err
+	}
+
+	return u.userRepo.SetUserAdmin(userID, isAdmin)
+}
+
+// DeleteUser removes another user's account. The groups the user is the only
+// manager of are handed to another member, preferably a moderator.
+func (u *AdminUsecase) DeleteUser(requesterID, userID uint) error {
+	if requesterID == userID {
+		return errors.New("cannot delete your own account")
+	}
+
+	if _, err := u.userRepo.GetUserByID(userID); err != nil {
+		return err
+	}
+
+	return u.userRepo.DeleteUser(userID, helpers.RoleManager, helpers.RoleModerator)
+}
+
+// ListJobs returns a page of background jobs, newest first, optionally
+// filtered by status and type.
+func (u *AdminUsecase) ListJobs(status, jobType string, page, pageSize int) (domain.JobList, error) {
+	switch status {
+	case "", repositories.JobPending, repositories.JobRunning, repositories.JobSucceeded, repositories.JobDead:
+	default:
+		return domain.JobList{}, errors.New("invalid job status")
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultJobPageSize
+	}
+	if pageSize > maxJobPageSize {
+		pageSize = maxJobPageSize
+	}
+
+	jobs, total, err := u.jobRepo.ListJobs(status, jobType, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return domain.JobList{}, err
+	}
+
+	return domain.JobList{
+		Jobs:  jobs,
+		Total: total,
+	}, nil
+}
+
+// GetJob retrieves a background job with its last error.
+func (u *AdminUsecase) GetJob(jobID uint) (*model.Job, error) {
+	job, err := u.jobRepo.GetJobByID(jobID)
+	if err != nil {
+		return nil, errors.New("job not found")
+	}
+	return job, nil
+}
+
+// RetryJob puts a job that ran out of attempts back in the queue.
+func (u *AdminUsecase) RetryJob(jobID uint) error {
+	if err := u.jobRepo.RequeueJob(jobID); err != nil {
+		return errors.New("job not found or not dead")
+	}
+	return nil
+}
+
+// CleanupStorage deletes stored files no notesheet references, or only
+// reports them in a dry run.
+func (u *AdminUsecase) CleanupStorage(dryRun bool) (*domain.StorageCleanupReport, error) {
+	return u.blobs.CollectGarbage(dryRun)
+}
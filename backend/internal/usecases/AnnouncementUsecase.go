@@ -3,7 +3,6 @@ package usecases
 import (
 	"band-manager-backend/internal/model"
 	"band-manager-backend/internal/repositories"
-	"band-manager-backend/internal/services"
 	"band-manager-backend/internal/usecases/helpers"
 	"errors"
 )
@@ -12,31 +11,57 @@ import (
 type AnnouncementUsecase struct {
 	announcementRepo *repositories.AnnouncementRepository
 	groupRepo        *repositories.GroupRepository
-	emailService     *services.EmailService
+	jobs             *JobQueue
 	userRepo         *repositories.UserRepository
+	subgroupRepo     *repositories.SubgroupRepository
+	policy           *Policy
 }
 
 func NewAnnouncementUsecase() *AnnouncementUsecase {
 	return &AnnouncementUsecase{
 		announcementRepo: repositories.NewAnnouncementRepository(),
 		groupRepo:        repositories.NewGroupRepository(),
-		emailService:     services.NewEmailService(),
+		jobs:             NewJobQueue(),
 		userRepo:         repositories.NewUserRepository(),
+		subgroupRepo:     repositories.NewSubgroupRepository(),
+		policy:           NewPolicy(),
 	}
 }
 
 // CreateAnnouncement creates a new announcement and notifies recipients.
-func (u *AnnouncementUsecase) CreateAnnouncement(title, description string, priority, groupID, senderID uint, recipientIDs []uint) (*model.Announcement, error) {
-	role, err := u.groupRepo.GetUserRole(senderID, groupID)
-	if err != nil {
-		return nil, errors.New("Could not get user role")
+// When subgroups are given, the announcement is limited to their members,
+// which also lets subgroup leaders address their own subgroup.
+func (u *AnnouncementUsecase) CreateAnnouncement(title, description string, priority, groupID, senderID uint, recipientIDs, subgroupIDs []uint) (*model.Announcement, error) {
+	if err := u.policy.RequireInSubgroups(senderID, groupID, subgroupIDs, helpers.PermAnnouncementSend); err != nil {
+		return nil, err
 	}
-	if !helpers.IsManagerOrModeratorRole(role) {
-		return nil, errors.New("insufficient permissions")
+
+	subgroupMembers := map[uint]*model.User{}
+	var subgroupMemberIDs []uint
+	for _, subgroupID := range subgroupIDs {
+		subgroup, err := u.subgroupRepo.GetSubgroupByID(subgroupID)
+		if err != nil {
+			return nil, err
+		}
+		if subgroup.GroupID != groupID {
+			return nil, errors.New("subgroup does not belong to the group")
+		}
+		for _, user := range subgroup.Users {
+			if _, ok := subgroupMembers[user.ID]; !ok {
+				subgroupMembers[user.ID] = user
+				subgroupMemberIDs = append(subgroupMemberIDs, user.ID)
+			}
+		}
 	}
 
 	var recipients []*model.User
-	if len(recipientIDs) == 0 {
+	if len(recipientIDs) == 0 && len(subgroupIDs) > 0 {
+
+		recipientIDs = subgroupMemberIDs
+		for _, id := range subgroupMemberIDs {
+			recipients = append(recipients, subgroupMembers[id])
+		}
+	} else if len(recipientIDs) == 0 {
 
 		groupUsers, err := u.groupRepo.GetGroupMembers(groupID)
 		if err != nil {
@@ -55,6 +80,10 @@ func (u *AnnouncementUsecase) CreateAnnouncement(title, description string, prio
 				return nil, errors.New("one or more recipients do not belong to the group")
 			}
 
+			if _, ok := subgroupMembers[recipientID]; len(subgroupIDs) > 0 && !ok {
+				return nil, errors.New("one or more recipients do not belong to the subgroups")
+			}
+
 			user, err := u.userRepo.GetUserByID(recipientID)
 			if err != nil {
 				return nil, err
@@ -79,7 +108,13 @@ func (u *AnnouncementUsecase) CreateAnnouncement(title, description string, prio
 		return nil, err
 	}
 
-	go u.emailService.SendAnnouncementEmail(announcement, recipients)
+	if len(subgroupIDs) > 0 {
+		if err := u.announcementRepo.AddToSubgroups(announcement.ID, subgroupIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	u.jobs.AnnouncementEmails(announcement.ID, helpers.VerifiedRecipients(recipients))
 
 	return announcement, nil
 }
@@ -91,12 +126,11 @@ func (u *AnnouncementUsecase) DeleteAnnouncement(announcementID, userID uint) er
 		return err
 	}
 
-	role, err := u.groupRepo.GetUserRole(userID, announcement.GroupID)
-	if err != nil {
+	if err := u.policy.RequireMember(userID, announcement.GroupID); err != nil {
 		return err
 	}
-	if !helpers.IsManagerOrModeratorRole(role) && announcement.SenderID != userID {
-		return errors.New("insufficient permissions")
+	if announcement.SenderID != userID && !u.policy.Can(userID, announcement.GroupID, helpers.PermAnnouncementDelete) {
+		return ErrInsufficientPermissions
 	}
 
 	return u.announcementRepo.Delete(announcementID)
@@ -1,40 +1,212 @@
 package usecases
 
 import (
+	"band-manager-backend/internal/domain"
 	"band-manager-backend/internal/model"
 	"band-manager-backend/internal/repositories"
+	"band-manager-backend/internal/services"
+	"band-manager-backend/internal/usecases/helpers"
 	"errors"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
 // AuthUsecase implements authentication and user management logic.
 type AuthUsecase struct {
-	userRepo  *repositories.UserRepository
-	groupRepo *repositories.GroupRepository
+	userRepo     *repositories.UserRepository
+	groupRepo    *repositories.GroupRepository
+	sessionRepo  *repositories.SessionRepository
+	resetRepo    *repositories.PasswordResetRepository
+	verifyRepo   *repositories.EmailVerificationRepository
+	tokenService *services.TokenService
+	jobs         *JobQueue
 }
 
-func NewAuthUsecase() *AuthUsecase {
+func NewAuthUsecase(tokenService *services.TokenService) *AuthUsecase {
 	userRepo := repositories.NewUserRepository()
 	groupRepo := repositories.NewGroupRepository()
 	return &AuthUsecase{
-		userRepo:  userRepo,
-		groupRepo: groupRepo,
+		userRepo:     userRepo,
+		groupRepo:    groupRepo,
+		sessionRepo:  repositories.NewSessionRepository(),
+		resetRepo:    repositories.NewPasswordResetRepository(),
+		verifyRepo:   repositories.NewEmailVerificationRepository(),
+		tokenService: tokenService,
+		jobs:         NewJobQueue(),
 	}
 }
 
-// Login authenticates a user and returns their profile with group memberships.
-func (u *AuthUsecase) Login(email, password string) (*model.User, error) {
+const (
+	// sessionTouchInterval limits how often a session's last activity is written.
+	sessionTouchInterval = time.Minute
+	// passwordResetTokenTTL is how long an emailed password reset link stays valid.
+	passwordResetTokenTTL = time.Hour
+	// verificationTokenTTL is how long an emailed verification link stays valid.
+	verificationTokenTTL = 24 * time.Hour
+	// verificationResendInterval is the minimum delay between two verification emails.
+	verificationResendInterval = time.Minute
+	// verificationHourlyLimit caps the number of verification emails sent per hour.
+	verificationHourlyLimit = 5
+)
+
+// ErrTooManyRequests is returned when a user asks for verification emails too often.
+var ErrTooManyRequests = errors.New("too many verification emails requested, try again later")
+
+// Login authenticates a user, opens a session for the calling device
+// and returns the user's profile together with a fresh token pair.
+func (u *AuthUsecase) Login(email, password, userAgent, ipAddress string) (*model.User, *domain.TokenPair, error) {
 	user, err := u.userRepo.GetUserByEmail(email)
 	if err != nil {
-		return nil, errors.New("invalid credentials")
+		return nil, nil, errors.New("invalid credentials")
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
-		return nil, errors.New("invalid credentials")
+		return nil, nil, errors.New("invalid credentials")
+	}
+
+	if user.Disabled {
+		return nil, nil, errors.New("account disabled")
+	}
+
+	refreshTokenID, err := helpers.GenerateToken(16)
+	if err != nil {
+		return nil, nil, errors.New("failed to create session")
 	}
 
-	return user, nil
+	now := time.Now()
+	session := &model.Session{
+		UserID:         user.ID,
+		UserAgent:      userAgent,
+		IPAddress:      ipAddress,
+		LastSeenAt:     now,
+		ExpiresAt:      now.Add(u.tokenService.RefreshTokenTTL()),
+		RefreshTokenID: refreshTokenID,
+	}
+	if err := u.sessionRepo.CreateSession(session); err != nil {
+		return nil, nil, errors.New("failed to create session")
+	}
+
+	tokens, err := u.issueTokens(user.ID, session.ID, refreshTokenID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, tokens, nil
+}
+
+// Refresh exchanges a valid refresh token of an active session for a new
+// token pair. Every refresh token works once: presenting one that was
+// already exchanged means it was stolen, so the session is revoked.
+func (u *AuthUsecase) Refresh(refreshToken string) (*domain.TokenPair, error) {
+	claims, err := u.tokenService.Verify(refreshToken, services.TokenTypeRefresh)
+	if err != nil {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	session, err := u.sessionRepo.GetActiveSession(claims.SessionID)
+	if err != nil || session.UserID != claims.UserID {
+		return nil, errors.New("session expired or revoked")
+	}
+
+	refreshTokenID, err := helpers.GenerateToken(16)
+	if err != nil {
+		return nil, errors.New("failed to extend session")
+	}
+	rotated, err := u.sessionRepo.RotateRefreshToken(session.ID, claims.TokenID, refreshTokenID,
+		time.Now().Add(u.tokenService.RefreshTokenTTL()))
+	if err != nil {
+		return nil, errors.New("failed to extend session")
+	}
+	if !rotated {
+		if err := u.sessionRepo.RevokeSession(session.ID); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("session expired or revoked")
+	}
+
+	return u.issueTokens(claims.UserID, session.ID, refreshTokenID)
+}
+
+// Authenticate resolves an access token into the IDs of the user and session it was issued to.
+func (u *AuthUsecase) Authenticate(accessToken string) (uint, uint, error) {
+	claims, err := u.tokenService.Verify(accessToken, services.TokenTypeAccess)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	session, err := u.sessionRepo.GetActiveSession(claims.SessionID)
+	if err != nil || session.UserID != claims.UserID {
+		return 0, 0, errors.New("session expired or revoked")
+	}
+
+	if time.Since(session.LastSeenAt) > sessionTouchInterval {
+		u.sessionRepo.TouchSession(session.ID, time.Now())
+	}
+
+	return claims.UserID, session.ID, nil
+}
+
+// Logout revokes the given session of the user.
+func (u *AuthUsecase) Logout(userID, sessionID uint) error {
+	return u.RevokeSession(userID, sessionID)
+}
+
+// ListSessions returns the user's active sessions, flagging the one making the request.
+func (u *AuthUsecase) ListSessions(userID, currentSessionID uint) ([]domain.SessionInfo, error) {
+	sessions, err := u.sessionRepo.GetUserActiveSessions(userID)
+	if err != nil {
+		return nil, errors.New("failed to get sessions")
+	}
+
+	infos := make([]domain.SessionInfo, 0, len(sessions))
+	for _, session := range sessions {
+		infos = append(infos, domain.SessionInfo{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    session.ID == currentSessionID,
+		})
+	}
+
+	return infos, nil
+}
+
+// RevokeSession revokes one of the user's own sessions.
+func (u *AuthUsecase) RevokeSession(userID, sessionID uint) error {
+	session, err := u.sessionRepo.GetActiveSession(sessionID)
+	if err != nil || session.UserID != userID {
+		return errors.New("session not found")
+	}
+
+	return u.sessionRepo.RevokeSession(sessionID)
+}
+
+// RevokeOtherSessions revokes every session of the user except the current one.
+func (u *AuthUsecase) RevokeOtherSessions(userID, currentSessionID uint) error {
+	return u.sessionRepo.RevokeUserSessions(userID, currentSessionID)
+}
+
+// issueTokens creates a new access and refresh token bound to the session.
+func (u *AuthUsecase) issueTokens(userID, sessionID uint, refreshTokenID string) (*domain.TokenPair, error) {
+	accessToken, err := u.tokenService.IssueAccessToken(userID, sessionID)
+	if err != nil {
+		return nil, errors.New("failed to issue access token")
+	}
+
+	refreshToken, err := u.tokenService.IssueRefreshToken(userID, sessionID, refreshTokenID)
+	if err != nil {
+		return nil, errors.New("failed to issue refresh token")
+	}
+
+	return &domain.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(u.tokenService.AccessTokenTTL().Seconds()),
+	}, nil
 }
 
 // Register creates a new user account with the provided details.
@@ -60,5 +232,121 @@ func (u *AuthUsecase) Register(firstName, lastName, email, password string) erro
 		return errors.New("failed to create user")
 	}
 
+	return u.sendVerification(newUser)
+}
+
+// VerifyEmail confirms a user's email address using a token from a verification link.
+// The token is consumed before the address is marked verified.
+func (u *AuthUsecase) VerifyEmail(token string) error {
+	verificationToken, err := u.verifyRepo.ConsumeToken(helpers.HashToken(token))
+	if err != nil {
+		return errors.New("invalid or expired verification token")
+	}
+
+	if err := u.userRepo.MarkEmailVerified(verificationToken.UserID); err != nil {
+		return errors.New("failed to verify email")
+	}
+
+	return u.verifyRepo.InvalidateUserTokens(verificationToken.UserID)
+}
+
+// ResendVerification sends a new verification link to an unverified account.
+// Unknown and already verified addresses succeed silently.
+func (u *AuthUsecase) ResendVerification(email string) error {
+	user, err := u.userRepo.GetUserByEmail(email)
+	if err != nil || user.EmailVerified {
+		return nil
+	}
+
+	now := time.Now()
+	recent, err := u.verifyRepo.CountUserTokensSince(user.ID, now.Add(-verificationResendInterval))
+	if err != nil {
+		return errors.New("failed to check verification requests")
+	}
+	hourly, err := u.verifyRepo.CountUserTokensSince(user.ID, now.Add(-time.Hour))
+	if err != nil {
+		return errors.New("failed to check verification requests")
+	}
+	if recent > 0 || hourly >= verificationHourlyLimit {
+		return ErrTooManyRequests
+	}
+
+	if err := u.verifyRepo.InvalidateUserTokens(user.ID); err != nil {
+		return errors.New("failed to invalidate verification tokens")
+	}
+
+	return u.sendVerification(user)
+}
+
+// sendVerification creates a verification token for the user and emails the
+// link. The token itself is issued by the job sending the email.
+func (u *AuthUsecase) sendVerification(user *model.User) error {
+	tokenHash, err := helpers.UnsentTokenHash()
+	if err != nil {
+		return errors.New("failed to generate verification token")
+	}
+
+	verificationToken := &model.EmailVerificationToken{
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(verificationTokenTTL),
+	}
+	if err := u.verifyRepo.CreateToken(verificationToken); err != nil {
+		return errors.New("failed to create verification token")
+	}
+
+	u.jobs.VerificationEmail(verificationToken.ID)
+
+	return nil
+}
+
+// RequestPasswordReset emails a single-use password reset link to the account owner.
+// It succeeds silently for unknown addresses so that accounts cannot be enumerated.
+func (u *AuthUsecase) RequestPasswordReset(email string) error {
+	user, err := u.userRepo.GetUserByEmail(email)
+	if err != nil {
+		return nil
+	}
+
+	tokenHash, err := helpers.UnsentTokenHash()
+	if err != nil {
+		return errors.New("failed to generate reset token")
+	}
+
+	resetToken := &model.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(passwordResetTokenTTL),
+	}
+	if err := u.resetRepo.CreateToken(resetToken); err != nil {
+		return errors.New("failed to create reset token")
+	}
+
+	u.jobs.PasswordResetEmail(resetToken.ID)
+
 	return nil
 }
+
+// ResetPassword sets a new password using a token from a reset link.
+// The token is consumed before the password is changed, so that it works
+// once even for concurrent requests, and every session of the user is revoked.
+func (u *AuthUsecase) ResetPassword(token, newPassword string) error {
+	if newPassword == "" {
+		return errors.New("password cannot be empty")
+	}
+
+	resetToken, err := u.resetRepo.ConsumeToken(helpers.HashToken(token))
+	if err != nil {
+		return errors.New("invalid or expired reset token")
+	}
+
+	if err := u.userRepo.ResetPassword(resetToken.UserID, newPassword); err != nil {
+		return errors.New("failed to reset password")
+	}
+
+	if err := u.resetRepo.InvalidateUserTokens(resetToken.UserID); err != nil {
+		return errors.New("failed to invalidate reset tokens")
+	}
+
+	return u.sessionRepo.RevokeUserSessions(resetToken.UserID, 0)
+}
@@ -0,0 +1,147 @@
+package usecases
+
+import (
+	"band-manager-backend/internal/domain"
+	"band-manager-backend/internal/model"
+	"band-manager-backend/internal/repositories"
+	"band-manager-backend/internal/services"
+	"band-manager-backend/internal/usecases/helpers"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"time"
+)
+
+// blobGracePeriod is how long files stay after they were last used before
+// garbage collection deletes them unreferenced, so that files being
+// uploaded are not deleted before a notesheet references them.
+const blobGracePeriod = time.Hour
+
+// BlobStore keeps uploaded files as content-addressed blobs, so identical
+// uploads are stored once, and deletes files nothing references anymore.
+type BlobStore struct {
+	blobRepo  *repositories.BlobRepository
+	trackRepo *repositories.TrackRepository
+	storage   services.Storage
+}
+
+func NewBlobStore(storage services.Storage) *BlobStore {
+	return &BlobStore{
+		blobRepo:  repositories.NewBlobRepository(),
+		trackRepo: repositories.NewTrackRepository(),
+		storage:   storage,
+	}
+}
+
+// Store stores the content as a blob unless a blob with the same content
+// exists, and returns the blob. The blob is not referenced yet; it is kept
+// for the grace period for a notesheet to reference its key.
+func (s *BlobStore) Store(content io.ReadSeeker, contentType string) (*model.Blob, error) {
+	hash, size, err := helpers.HashContent(content)
+	if err != nil {
+		return nil, err
+	}
+	return s.StoreHashed(content, hash, size, contentType)
+}
+
+// StoreHashed stores content already hashed with helpers.HashContent, as
+// Store does.
+func (s *BlobStore) StoreHashed(content io.ReadSeeker, hash string, size int64, contentType string) (*model.Blob, error) {
+	blob := &model.Blob{
+		Hash:        hash,
+		Key:         helpers.BlobKey(hash),
+		Size:        size,
+		ContentType: contentType,
+	}
+	if err := s.blobRepo.ReserveBlob(blob); err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	info, err := s.storage.Stat(ctx, blob.Key)
+	if err == nil && info.Size == size {
+		return blob, nil
+	}
+	if err != nil && !errors.Is(err, services.ErrObjectNotFound) {
+		return nil, err
+	}
+
+	if err := s.storage.Put(ctx, blob.Key, content, size, blob.ContentType); err != nil {
+		return nil, err
+	}
+	return blob, nil
+}
+
+// CollectGarbage deletes blobs no notesheet references and other stored
+// files no notesheet refers to, such as files kept from before blobs were
+// introduced, once they have been unused for the grace period. A dry run
+// only reports the files that would be deleted.
+func (s *BlobStore) CollectGarbage(dryRun bool) (*domain.StorageCleanupReport, error) {
+	report := &domain.StorageCleanupReport{DryRun: dryRun, Files: []domain.StoredFile{}}
+	cutoff := time.Now().Add(-blobGracePeriod)
+	ctx := context.Background()
+
+	// Blobs are collected by their reference counts, so counts that drifted
+	// from the references are corrected first. A dry run reports the counts
+	// as they are.
+	if !dryRun {
+		corrected, err := s.blobRepo.RecountReferences()
+		if err != nil {
+			return nil, err
+		}
+		report.CorrectedRefCounts = corrected
+	}
+
+	blobs, err := s.blobRepo.GetUnreferencedBlobs(cutoff)
+	if err != nil {
+		return nil, err
+	}
+	for _, blob := range blobs {
+		if !dryRun {
+			deleted, err := s.blobRepo.DeleteUnreferencedBlob(blob.Hash, cutoff, func(key string) error {
+				return s.storage.Delete(ctx, key)
+			})
+			if err != nil {
+				log.Printf("Failed to delete blob %s: %v", blob.Key, err)
+			}
+			if !deleted {
+				continue
+			}
+		}
+		report.Files = append(report.Files, domain.StoredFile{Key: blob.Key, Size: blob.Size})
+		report.TotalBytes += blob.Size
+	}
+
+	// Files are listed before references are read, so that files stored
+	// meanwhile are not taken for unreferenced ones.
+	objects, err := s.storage.List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	filepaths, err := s.trackRepo.GetNotesheetFilepaths()
+	if err != nil {
+		return nil, err
+	}
+	referenced := make(map[string]bool, len(filepaths))
+	for _, filepath := range filepaths {
+		referenced[notesheetFileKey(filepath)] = true
+	}
+
+	for _, object := range objects {
+		// Blob files are deleted along with their records above.
+		if helpers.IsBlobKey(object.Key) || referenced[object.Key] || !object.ModTime.Before(cutoff) {
+			continue
+		}
+		if !dryRun {
+			if err := s.storage.Delete(ctx, object.Key); err != nil {
+				log.Printf("Failed to delete file %s: %v", object.Key, err)
+				continue
+			}
+		}
+		report.Files = append(report.Files, domain.StoredFile{Key: object.Key, Size: object.Size})
+		report.TotalBytes += object.Size
+	}
+
+	return report, nil
+}
@@ -0,0 +1,98 @@
+package usecases
+
+import (
+	"band-manager-backend/internal/model"
+	"band-manager-backend/internal/repositories"
+	"band-manager-backend/internal/usecases/helpers"
+	"errors"
+	"time"
+)
+
+// Calendar feeds publish past events for this long, besides all upcoming ones.
+const calendarFeedHistory = 90 * 24 * time.Hour
+
+// CalendarFeedUsecase manages token-protected iCalendar feeds.
+type CalendarFeedUsecase struct {
+	feedRepo  *repositories.CalendarFeedRepository
+	eventRepo *repositories.EventRepository
+	policy    *Policy
+}
+
+func NewCalendarFeedUsecase() *CalendarFeedUsecase {
+	return &CalendarFeedUsecase{
+		feedRepo:  repositories.NewCalendarFeedRepository(),
+		eventRepo: repositories.NewEventRepository(),
+		policy:    NewPolicy(),
+	}
+}
+
+// CreateFeed creates a feed of all the user's events, or of the events of a
+// group the user belongs to, and returns it with its token. The token is
+// only stored hashed, so it cannot be shown again later.
+func (u *CalendarFeedUsecase) CreateFeed(groupID *uint, userID uint) (*model.CalendarFeed, string, error) {
+	if groupID != nil {
+		if err := u.policy.RequireMember(userID, *groupID); err != nil {
+			return nil, "", errors.New("user not in group")
+		}
+	}
+
+	token, err := helpers.GenerateToken(32)
+	if err != nil {
+		return nil, "", errors.New("failed to generate feed token")
+	}
+
+	feed := &model.CalendarFeed{
+		UserID:    userID,
+		GroupID:   groupID,
+		TokenHash: helpers.HashToken(token),
+	}
+	if err := u.feedRepo.CreateFeed(feed); err != nil {
+		return nil, "", errors.New("failed to create calendar feed")
+	}
+	return feed, token, nil
+}
+
+// GetUserFeeds lists the feeds of a user that have not been revoked.
+func (u *CalendarFeedUsecase) GetUserFeeds(userID uint) ([]*model.CalendarFeed, error) {
+	return u.feedRepo.GetUserActiveFeeds(userID)
+}
+
+// RevokeFeed stops a feed of the user from being served.
+func (u *CalendarFeedUsecase) RevokeFeed(feedID, userID uint) error {
+	feed, err := u.feedRepo.GetFeedByID(feedID)
+	if err != nil || feed.UserID != userID {
+		return errors.New("calendar feed not found")
+	}
+	if feed.RevokedAt != nil {
+		return errors.New("calendar feed already revoked")
+	}
+	return u.feedRepo.RevokeFeed(feedID)
+}
+
+// RenderFeed renders the iCalendar document of the feed with the token.
+// Group feeds stop working once their owner leaves the group.
+func (u *CalendarFeedUsecase) RenderFeed(token string) (string, error) {
+	feed, err := u.feedRepo.GetActiveFeed(helpers.HashToken(token))
+	if err != nil {
+		return "", errors.New("calendar feed not found")
+	}
+
+	from := time.Now().Add(-calendarFeedHistory)
+	if feed.GroupID == nil {
+		events, err := u.eventRepo.GetUserCalendarEvents(feed.UserID, from)
+		if err != nil {
+			return "", err
+		}
+		name := feed.User.FirstName + " " + feed.User.LastName
+		return helpers.ICalendar(name, events, time.Now()), nil
+	}
+
+	if err := u.policy.RequireMember(feed.UserID, *feed.GroupID); err != nil || feed.Group == nil {
+		return "", errors.New("calendar feed not found")
+	}
+	events, err := u.eventRepo.GetGroupCalendarEvents(*feed.GroupID, from)
+	if err != nil {
+		return "", err
+	}
+	return helpers.ICalendar(feed.Group.Name, events, time.Now()), nil
+}
@@ -0,0 +1,328 @@
+package usecases
+
+import (
+	"band-manager-backend/internal/model"
+	"band-manager-backend/internal/repositories"
+	"band-manager-backend/internal/services"
+	"band-manager-backend/internal/usecases/helpers"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const googleOAuthStateTTL = 10 * time.Minute
+
+// CalendarSyncUsecase links users' Google calendars and keeps copies of the
+// events they attend in them.
+type CalendarSyncUsecase struct {
+	gcService    *services.GoogleCalendarService
+	calendarRepo *repositories.GoogleCalendarRepository
+	eventRepo    *repositories.EventRepository
+	jobs         *JobQueue
+}
+
+// NewCalendarSyncUsecase creates the usecase; without a Google Calendar
+// service linking fails and syncing does nothing.
+func NewCalendarSyncUsecase(gcService *services.GoogleCalendarService) *CalendarSyncUsecase {
+	return &CalendarSyncUsecase{
+		gcService:    gcService,
+		calendarRepo: repositories.NewGoogleCalendarRepository(),
+		eventRepo:    repositories.NewEventRepository(),
+		jobs:         NewJobQueue(),
+	}
+}
+
+// GetAuthURL returns the Google consent page URL for linking the user's
+// calendar. Its state is a single-use nonce identifying the user.
+func (u *CalendarSyncUsecase) GetAuthURL(userID uint) (string, error) {
+	if u.gcService == nil {
+		return "", errors.New("Google Calendar integration is not configured")
+	}
+
+	if err := u.calendarRepo.DeleteExpiredStates(); err != nil {
+		log.Printf("Failed to delete expired OAuth states: %v", err)
+	}
+
+	state, err := helpers.GenerateToken(32)
+	if err != nil {
+		return "", errors.New("failed to generate state")
+	}
+
+	err = u.calendarRepo.CreateState(&model.GoogleOAuthState{
+		UserID:    userID,
+		StateHash: helpers.HashToken(state),
+		ExpiresAt: time.Now().Add(googleOAuthStateTTL),
+	})
+	if err != nil {
+		return "", errors.New("failed to store state")
+	}
+
+	return u.gcService.GetAuthURL(state), nil
+}
+
+// LinkCalendar completes linking the calendar of the user who requested the
+// state and copies the user's upcoming events to it.
+func (u *CalendarSyncUsecase) LinkCalendar(state, code string) error {
+	if u.gcService == nil {
+		return errors.New("Google Calendar integration is not configured")
+	}
+
+	oauthState, err := u.calendarRepo.ConsumeState(helpers.HashToken(state))
+	if err != nil {
+		return errors.New("invalid or expired state")
+	}
+
+	token, err := u.gcService.ExchangeCode(code)
+	if err != nil {
+		return errors.New("failed to exchange authorization code")
+	}
+
+	stored := &model.GoogleToken{
+		UserID:       oauthState.UserID,
+		AccessToken:  token.AccessToken,
+		TokenType:    token.TokenType,
+		RefreshToken: token.RefreshToken,
+		Expiry:       token.Expiry,
+	}
+	if err := u.calendarRepo.SaveToken(stored); err != nil {
+		return errors.New("failed to save token")
+	}
+
+	u.jobs.CalendarSyncUpcoming(stored.UserID)
+	return nil
+}
+
+// UnlinkCalendar removes the copies of events from the user's calendar and
+// forgets the user's token.
+func (u *CalendarSyncUsecase) UnlinkCalendar(userID uint) error {
+	stored, err := u.calendarRepo.GetToken(userID)
+	if err != nil {
+		return errors.New("Google Calendar not linked")
+	}
+
+	if u.gcService != nil {
+		copies, err := u.calendarRepo.GetUserSyncedEvents(userID)
+		if err != nil {
+			return err
+		}
+		tokens := u.tokenSource(stored)
+		for _, synced := range copies {
+			if err := u.gcService.DeleteCalendarEvent(tokens, synced.CalendarID); err != nil {
+				log.Printf("Failed to remove event %d from calendar of user %d: %v", synced.EventID, userID, err)
+			}
+		}
+		u.saveRefreshedToken(stored, tokens)
+	}
+
+	return u.calendarRepo.DeleteToken(userID)
+}
+
+// IsLinked reports whether the user linked a Google calendar.
+func (u *CalendarSyncUsecase) IsLinked(userID uint) bool {
+	_, err := u.calendarRepo.GetToken(userID)
+	return err == nil
+}
+
+// ScheduleSync enqueues syncing the calendar copies of an event after it changed.
+func (u *CalendarSyncUsecase) ScheduleSync(eventID uint) {
+	if u.gcService != nil {
+		u.jobs.CalendarSync(eventID)
+	}
+}
+
+// ScheduleRemoval enqueues removing calendar copies of deleted events.
+func (u *CalendarSyncUsecase) ScheduleRemoval(copies []*model.GoogleCalendarEvent) {
+	if u.gcService != nil {
+		u.jobs.CalendarRemoval(copies)
+	}
+}
+
+// SyncEvent brings the copies of an event in line with its current state:
+// linked attendees get the event created or updated in their calendars and
+// users no longer attending it have it removed. It fails if any calendar
+// could not be updated, so that the sync is retried.
+func (u *CalendarSyncUsecase) SyncEvent(eventID uint) error {
+	if u.gcService == nil {
+		return nil
+	}
+
+	event, err := u.eventRepo.GetEventByID(eventID)
+	if err != nil {
+		return fmt.Errorf("loading event %d: %w", eventID, err)
+	}
+
+	copies, err := u.calendarRepo.GetSyncedEvents([]uint{eventID})
+	if err != nil {
+		return err
+	}
+	copiesByUser := make(map[uint]*model.GoogleCalendarEvent, len(copies))
+	for _, synced := range copies {
+		copiesByUser[synced.UserID] = synced
+	}
+
+	attendeeIDs := make([]uint, 0, len(event.Users))
+	for _, user := range event.Users {
+		attendeeIDs = append(attendeeIDs, user.ID)
+	}
+	tokens, err := u.calendarRepo.GetTokens(attendeeIDs)
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	for _, stored := range tokens {
+		if err := u.syncEventForUser(event, stored, copiesByUser[stored.UserID]); err != nil {
+			log.Printf("Failed to sync event %d to calendar of user %d: %v", eventID, stored.UserID, err)
+			failed++
+		}
+		delete(copiesByUser, stored.UserID)
+	}
+
+	for _, synced := range copiesByUser {
+		if err := u.RemoveCopy(synced); err != nil {
+			log.Printf("Failed to remove event %d from calendar of user %d: %v", eventID, synced.UserID, err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d calendars of event %d could not be synced", failed, eventID)
+	}
+	return nil
+}
+
+// SyncedCopies returns the calendar copies of the events. Copies of events
+// about to be deleted must be collected beforehand, as their records are
+// deleted along with the events.
+func (u *CalendarSyncUsecase) SyncedCopies(eventIDs []uint) []*model.GoogleCalendarEvent {
+	if u.gcService == nil || len(eventIDs) == 0 {
+		return nil
+	}
+	copies, err := u.calendarRepo.GetSyncedEvents(eventIDs)
+	if err != nil {
+		log.Printf("Failed to load calendar copies of events %v: %v", eventIDs, err)
+		return nil
+	}
+	return copies
+}
+
+// RemoveCopy deletes a calendar copy from the calendar of its user. Copies of
+// users who unlinked their calendar in the meantime are already gone.
+func (u *CalendarSyncUsecase) RemoveCopy(synced *model.GoogleCalendarEvent) error {
+	if u.gcService == nil {
+		return nil
+	}
+
+	stored, err := u.calendarRepo.GetToken(synced.UserID)
+	if err != nil {
+		return fmt.Errorf("loading token of user %d: %w", synced.UserID, err)
+	}
+
+	tokens := u.tokenSource(stored)
+	defer u.saveRefreshedToken(stored, tokens)
+
+	if err := u.gcService.DeleteCalendarEvent(tokens, synced.CalendarID); err != nil {
+		return err
+	}
+	return u.calendarRepo.DeleteSyncedEvent(synced.ID)
+}
+
+// SyncUpcomingEvents copies the upcoming events of a user who just linked a calendar.
+func (u *CalendarSyncUsecase) SyncUpcomingEvents(userID uint) error {
+	if u.gcService == nil {
+		return nil
+	}
+
+	stored, err := u.calendarRepo.GetToken(userID)
+	if err != nil {
+		return fmt.Errorf("loading token of user %d: %w", userID, err)
+	}
+
+	events, err := u.eventRepo.GetUserCalendarEvents(userID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	eventIDs := make([]uint, 0, len(events))
+	for _, event := range events {
+		eventIDs = append(eventIDs, event.ID)
+	}
+	copies, err := u.calendarRepo.GetSyncedEvents(eventIDs)
+	if err != nil {
+		return err
+	}
+	copiesByEvent := make(map[uint]*model.GoogleCalendarEvent)
+	for _, synced := range copies {
+		if synced.UserID == userID {
+			copiesByEvent[synced.EventID] = synced
+		}
+	}
+
+	var failed int
+	for _, event := range events {
+		if err := u.syncEventForUser(event, stored, copiesByEvent[event.ID]); err != nil {
+			log.Printf("Failed to sync event %d to calendar of user %d: %v", event.ID, userID, err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d events could not be synced to calendar of user %d", failed, userID)
+	}
+	return nil
+}
+
+// syncEventForUser creates or updates the copy of an event in the calendar of one user.
+func (u *CalendarSyncUsecase) syncEventForUser(event *model.Event, stored *model.GoogleToken, synced *model.GoogleCalendarEvent) error {
+	tokens := u.tokenSource(stored)
+	defer u.saveRefreshedToken(stored, tokens)
+
+	if synced != nil {
+		if err := u.gcService.UpdateCalendarEvent(tokens, synced.CalendarID, event); err != nil {
+			return err
+		}
+		synced.LastSynced = time.Now()
+		return u.calendarRepo.SaveSyncedEvent(synced)
+	}
+
+	calendarID, err := u.gcService.CreateCalendarEvent(tokens, event)
+	if err != nil {
+		return err
+	}
+	return u.calendarRepo.SaveSyncedEvent(&model.GoogleCalendarEvent{
+		EventID:    event.ID,
+		UserID:     stored.UserID,
+		CalendarID: calendarID,
+		LastSynced: time.Now(),
+	})
+}
+
+// tokenSource returns a token source for a stored token.
+func (u *CalendarSyncUsecase) tokenSource(stored *model.GoogleToken) oauth2.TokenSource {
+	return u.gcService.TokenSource(&oauth2.Token{
+		AccessToken:  stored.AccessToken,
+		TokenType:    stored.TokenType,
+		RefreshToken: stored.RefreshToken,
+		Expiry:       stored.Expiry,
+	})
+}
+
+// saveRefreshedToken stores the token of the token source if it was refreshed.
+func (u *CalendarSyncUsecase) saveRefreshedToken(stored *model.GoogleToken, tokens oauth2.TokenSource) {
+	current, err := tokens.Token()
+	if err != nil || current.AccessToken == stored.AccessToken {
+		return
+	}
+
+	stored.AccessToken = current.AccessToken
+	stored.Expiry = current.Expiry
+	if current.RefreshToken != "" {
+		stored.RefreshToken = current.RefreshToken
+	}
+	if err := u.calendarRepo.SaveToken(stored); err != nil {
+		log.Printf("Failed to save refreshed token of user %d: %v", stored.UserID, err)
+	}
+}
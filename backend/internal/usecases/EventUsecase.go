@@ -1,51 +1,103 @@
 package usecases
 
 import (
+	"band-manager-backend/internal/domain"
 	"band-manager-backend/internal/model"
 	"band-manager-backend/internal/repositories"
 	"band-manager-backend/internal/services"
 	"band-manager-backend/internal/usecases/helpers"
 	"errors"
+	"fmt"
 	"log"
+	"sort"
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
+// Recurring events without an explicit date range are expanded within this window around now.
+const (
+	defaultOccurrencesBefore = 90 * 24 * time.Hour
+	defaultOccurrencesAfter  = 365 * 24 * time.Hour
+)
+
+const maxSubgroupNoteLength = 2000
+
+// ErrSeriesScopeRequired is returned when a change to a recurring event does
+// not say which of its occurrences it applies to.
+var ErrSeriesScopeRequired = errors.New("scope is required for recurring events - must be 'this', 'following' or 'all'")
+
+// EventConflictError reports members who are already booked in other events
+// at the time of an event being scheduled.
+type EventConflictError struct {
+	Conflicts []domain.EventConflict
+}
+
+func (e *EventConflictError) Error() string {
+	return fmt.Sprintf("%d scheduling conflicts with other events", len(e.Conflicts))
+}
+
 // EventUsecase implements event management logic.
 type EventUsecase struct {
 	eventRepo    *repositories.EventRepository
 	groupRepo    *repositories.GroupRepository
 	trackRepo    *repositories.TrackRepository
 	userRepo     *repositories.UserRepository
-	gcService    *services.GoogleCalendarService
-	emailService *services.EmailService
+	subgroupRepo *repositories.SubgroupRepository
+	setlistRepo  *repositories.SetlistRepository
+	calendarSync *CalendarSyncUsecase
+	reminders    *ReminderScheduler
+	jobs         *JobQueue
+	policy       *Policy
 }
 
-func NewEventUsecase(gcService *services.GoogleCalendarService, emailService *services.EmailService) *EventUsecase {
+func NewEventUsecase(gcService *services.GoogleCalendarService) *EventUsecase {
 	return &EventUsecase{
 		eventRepo:    repositories.NewEventRepository(),
 		groupRepo:    repositories.NewGroupRepository(),
 		trackRepo:    repositories.NewTrackRepository(),
 		userRepo:     repositories.NewUserRepository(),
-		gcService:    gcService,
-		emailService: emailService,
+		subgroupRepo: repositories.NewSubgroupRepository(),
+		setlistRepo:  repositories.NewSetlistRepository(),
+		calendarSync: NewCalendarSyncUsecase(gcService),
+		reminders:    NewReminderScheduler(),
+		jobs:         NewJobQueue(),
+		policy:       NewPolicy(),
 	}
 }
 
 // CreateEvent creates a new event with optional Google Calendar integration.
-func (u *EventUsecase) CreateEvent(title, description, location string, date time.Time,
-	groupID uint, trackIDs []uint, userIDs []uint, userID uint) (*model.Event, error) {
+// A non-empty recurrence rule makes the event the first occurrence of a series.
+// Assigning members who are booked in other events at the same time fails
+// with an EventConflictError unless force is set by a member allowed to.
+func (u *EventUsecase) CreateEvent(details domain.EventDetails, recurrenceRule string,
+	groupID uint, trackIDs []uint, userIDs []uint, force bool, userID uint) (*model.Event, error) {
 
-	if err := u.validateUserPermissions(userID, groupID); err != nil {
+	if err := u.policy.Require(userID, groupID, helpers.PermEventCreate); err != nil {
+		return nil, err
+	}
+
+	if details.TimeZone == "" {
+		details.TimeZone = u.groupTimeZone(groupID)
+	}
+	if err := prepareEventDetails(&details); err != nil {
+		return nil, err
+	}
+
+	recurrenceRule, err := normalizeRecurrenceRule(recurrenceRule)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.checkConflicts(groupID, details, recurrenceRule, userIDs, 0, force, userID); err != nil {
 		return nil, err
 	}
 
 	event := &model.Event{
-		Title:       title,
-		Description: description,
-		Location:    location,
-		Date:        date,
-		GroupID:     groupID,
+		RecurrenceRule: recurrenceRule,
+		GroupID:        groupID,
 	}
+	applyEventDetails(event, details)
 
 	if err := u.eventRepo.CreateEvent(event); err != nil {
 		return nil, err
@@ -60,33 +112,104 @@ func (u *EventUsecase) CreateEvent(title, description, location string, date tim
 	}
 
 	u.handleExternalIntegrations(event, userIDs)
+	u.reminders.ScheduleEvent(event.ID)
 
 	return event, nil
 }
 
-// DeleteEvent removes an event and its Google Calendar entry.
-func (u *EventUsecase) DeleteEvent(id uint, userID uint) error {
+// DeleteEvent removes an event and its Google Calendar copies and tells the
+// assigned members it is cancelled. For recurring events the scope selects
+// whether only the given occurrence, the occurrence and all following ones,
+// or the whole series is cancelled; it is required, as for UpdateEvent.
+func (u *EventUsecase) DeleteEvent(id uint, scope string, occurrenceDate *time.Time, userID uint) error {
 	event, err := u.eventRepo.GetEventByID(id)
 	if err != nil {
 		return errors.New("Could not find event")
 	}
-	if err := u.validateUserPermissions(userID, event.GroupID); err != nil {
+	if err := u.policy.Require(userID, event.GroupID, helpers.PermEventDelete); err != nil {
 		return err
 	}
-	return u.eventRepo.DeleteEvent(id)
+
+	rule, scope, err := u.resolveSeriesScope(event, scope, occurrenceDate)
+	if err != nil {
+		return err
+	}
+
+	switch scope {
+	case helpers.SeriesScopeThis:
+		if err := u.eventRepo.AddException(event.ID, *occurrenceDate); err != nil {
+			return err
+		}
+		u.calendarSync.ScheduleSync(event.ID)
+		if occurrenceDate.After(time.Now()) {
+			u.notifyCancellation(event, occurrenceOf(event, *occurrenceDate), false)
+		}
+		return nil
+	case helpers.SeriesScopeFollowing:
+		cancelled := nextOccurrence(event, *occurrenceDate)
+		if err := u.endSeriesBefore(event, rule, *occurrenceDate); err != nil {
+			return err
+		}
+		if err := u.deleteSeriesOverrides(event.ID, *occurrenceDate); err != nil {
+			return err
+		}
+		u.calendarSync.ScheduleSync(event.ID)
+		if cancelled != nil {
+			u.notifyCancellation(event, cancelled, true)
+		}
+		return nil
+	}
+
+	// Separately edited occurrences are deleted along with their series.
+	overrideIDs, err := u.eventRepo.GetSeriesOverrideIDs(event.ID, nil)
+	if err != nil {
+		return err
+	}
+	copies := u.calendarSync.SyncedCopies(append(overrideIDs, event.ID))
+	cancelled := nextOccurrence(event, time.Now())
+
+	if err := u.eventRepo.DeleteEvent(id); err != nil {
+		return err
+	}
+	u.calendarSync.ScheduleRemoval(copies)
+	if cancelled != nil {
+		u.notifyCancellation(event, cancelled, event.RecurrenceRule != "")
+	}
+	return nil
 }
 
-// GetGroupEvents retrieves all events for a specific group.
-func (u *EventUsecase) GetGroupEvents(groupID uint, userID uint) ([]*model.Event, error) {
+// GetGroupEvents retrieves the events of a group within the optional date
+// range, with recurring events expanded into their occurrences. A non-empty
+// list of event types limits the result to events of those types.
+func (u *EventUsecase) GetGroupEvents(groupID uint, userID uint, from, to *time.Time, eventTypes []string) ([]*model.Event, error) {
 	if !u.isUserInGroup(userID, groupID) {
 		return nil, errors.New("User not in group")
 	}
-	return u.eventRepo.GetGroupEvents(groupID)
+
+	if err := validateEventTypes(eventTypes); err != nil {
+		return nil, err
+	}
+
+	events, err := u.eventRepo.GetGroupEvents(groupID, from, to, eventTypes)
+	if err != nil {
+		return nil, err
+	}
+	return expandOccurrences(events, from, to), nil
 }
 
-// GetUserEvents retrieves all events a user is participating in.
-func (u *EventUsecase) GetUserEvents(userID uint) ([]*model.Event, error) {
-	return u.eventRepo.GetUserEvents(userID)
+// GetUserEvents retrieves the events a user is participating in within the
+// optional date range, with recurring events expanded into their occurrences.
+// A non-empty list of event types limits the result to events of those types.
+func (u *EventUsecase) GetUserEvents(userID uint, from, to *time.Time, eventTypes []string) ([]*model.Event, error) {
+	if err := validateEventTypes(eventTypes); err != nil {
+		return nil, err
+	}
+
+	events, err := u.eventRepo.GetUserEvents(userID, from, to, eventTypes)
+	if err != nil {
+		return nil, err
+	}
+	return expandOccurrences(events, from, to), nil
 }
 
 // GetEventTracks retrieves tracks associated with an event.
@@ -117,20 +240,221 @@ func (u *EventUsecase) GetEvent(eventID uint, userID uint) (*model.Event, error)
 	return event, nil
 }
 
-// UpdateEvent modifies event details and updates Google Calendar.
-func (u *EventUsecase) UpdateEvent(id uint, title, description, location string,
-	date time.Time, trackIDs []uint, userIDs []uint, userID uint) error {
+// GetEventInfo retrieves event details together with the setlist if user has access.
+func (u *EventUsecase) GetEventInfo(eventID uint, userID uint) (*domain.EventInfo, error) {
+	event, err := u.GetEvent(eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := u.setlistRepo.GetSetlist(event.ID)
+	if err != nil {
+		return nil, err
+	}
+	setlist := helpers.BuildSetlist(event, entries)
+
+	notes, err := u.getSubgroupNotes(event.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.EventInfo{
+		Event:         event,
+		Setlist:       &setlist,
+		SubgroupNotes: notes,
+	}, nil
+}
+
+// ExportEventPDF renders an event in the given printable layout and returns
+// it with a file name.
+func (u *EventUsecase) ExportEventPDF(eventID uint, layout string, userID uint) ([]byte, string, error) {
+	info, err := u.GetEventInfo(eventID, userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch layout {
+	case helpers.ExportLayoutStage:
+		return helpers.StageSetlistPDF(info.Event, *info.Setlist), fmt.Sprintf("setlist-%d.pdf", eventID), nil
+	case helpers.ExportLayoutProgramme:
+		return helpers.EventProgrammePDF(info.Event, *info.Setlist, info.SubgroupNotes), fmt.Sprintf("program-%d.pdf", eventID), nil
+	default:
+		return nil, "", errors.New("unsupported export layout")
+	}
+}
+
+// SetSubgroupNote sets the instructions for a subgroup of the event's group
+// for the event. Empty notes remove them.
+func (u *EventUsecase) SetSubgroupNote(eventID, subgroupID uint, notes string, userID uint) error {
+	event, err := u.eventRepo.GetEventByID(eventID)
+	if err != nil {
+		return errors.New("event not found")
+	}
+
+	if err := u.policy.Require(userID, event.GroupID, helpers.PermEventUpdate); err != nil {
+		return err
+	}
+
+	subgroup, err := u.subgroupRepo.GetSubgroupByID(subgroupID)
+	if err != nil || subgroup.GroupID != event.GroupID {
+		return errors.New("subgroup not found in this group")
+	}
+
+	notes = strings.TrimSpace(notes)
+	if notes == "" {
+		return u.eventRepo.DeleteSubgroupNote(event.ID, subgroup.ID)
+	}
+	if utf8.RuneCountInString(notes) > maxSubgroupNoteLength {
+		return fmt.Errorf("notes must be at most %d characters", maxSubgroupNoteLength)
+	}
+	return u.eventRepo.SetSubgroupNote(event.ID, subgroup.ID, notes)
+}
+
+// SetEventReminders sets how many minutes before an event its members are
+// reminded of it. Nil makes the event use the reminders of its group; an
+// empty list turns reminders off.
+func (u *EventUsecase) SetEventReminders(eventID uint, minutes []int, userID uint) error {
+	event, err := u.eventRepo.GetEventByID(eventID)
+	if err != nil {
+		return errors.New("event not found")
+	}
+
+	if err := u.policy.Require(userID, event.GroupID, helpers.PermEventUpdate); err != nil {
+		return err
+	}
+
+	normalized, err := helpers.NormalizeReminderMinutes(minutes)
+	if err != nil {
+		return err
+	}
+	if err := u.eventRepo.UpdateReminderMinutes(event.ID, normalized); err != nil {
+		return err
+	}
+
+	u.reminders.ScheduleEvent(event.ID)
+	return nil
+}
+
+// getSubgroupNotes loads the subgroup notes of an event.
+func (u *EventUsecase) getSubgroupNotes(eventID uint) ([]domain.SubgroupNote, error) {
+	stored, err := u.eventRepo.GetSubgroupNotes(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	notes := make([]domain.SubgroupNote, 0, len(stored))
+	for _, note := range stored {
+		notes = append(notes, domain.SubgroupNote{
+			SubgroupID:   note.SubgroupID,
+			SubgroupName: note.Subgroup.Name,
+			Notes:        note.Notes,
+		})
+	}
+	return notes, nil
+}
+
+// CheckSlot lists the conflicts scheduling an event with the given details
+// would cause for the given members, or for all group members if none are
+// given. The event being edited, if any, is not reported as a conflict.
+func (u *EventUsecase) CheckSlot(groupID uint, details domain.EventDetails, recurrenceRule string,
+	userIDs []uint, excludeEventID uint, userID uint) ([]domain.EventConflict, error) {
+
+	if err := u.policy.Require(userID, groupID, helpers.PermEventCreate); err != nil {
+		return nil, err
+	}
+
+	if details.TimeZone == "" {
+		details.TimeZone = u.groupTimeZone(groupID)
+	}
+	if err := prepareEventDetails(&details); err != nil {
+		return nil, err
+	}
+
+	recurrenceRule, err := normalizeRecurrenceRule(recurrenceRule)
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts, err := u.findConflicts(groupID, details, recurrenceRule, userIDs, excludeEventID)
+	if err != nil {
+		return nil, err
+	}
+	if conflicts == nil {
+		conflicts = []domain.EventConflict{}
+	}
+	return conflicts, nil
+}
+
+// UpdateEvent modifies event details and updates Google Calendar. For
+// recurring events the scope selects whether only the given occurrence, the
+// occurrence and all following ones, or the whole series is changed; it is
+// required, so that a whole series is never changed by a request meant for
+// one occurrence. A nil recurrence rule keeps the current rule; an empty
+// type or time zone keeps the current one. Conflicts with other bookings of
+// the assigned members are handled as in CreateEvent.
+func (u *EventUsecase) UpdateEvent(id uint, details domain.EventDetails,
+	recurrenceRule *string, scope string, occurrenceDate *time.Time,
+	trackIDs []uint, userIDs []uint, force bool, userID uint) error {
 
 	event, err := u.eventRepo.GetEventByID(id)
 	if err != nil {
 		return err
 	}
 
-	if err := u.validateUserPermissions(userID, event.GroupID); err != nil {
+	if err := u.policy.Require(userID, event.GroupID, helpers.PermEventUpdate); err != nil {
 		return err
 	}
 
-	if err := u.updateEventBasicInfo(event, title, description, location, date); err != nil {
+	rule, scope, err := u.resolveSeriesScope(event, scope, occurrenceDate)
+	if err != nil {
+		return err
+	}
+
+	// Editing the whole series from one of its occurrences shifts the series by the same amount.
+	if scope == helpers.SeriesScopeAll && event.RecurrenceRule != "" && occurrenceDate != nil {
+		shift := event.Date.Sub(*occurrenceDate)
+		details.Date = details.Date.Add(shift)
+		if !details.EndDate.IsZero() {
+			details.EndDate = details.EndDate.Add(shift)
+		}
+	}
+
+	if details.Type == "" {
+		details.Type = event.Type
+	}
+	if details.TimeZone == "" {
+		details.TimeZone = event.TimeZone
+	}
+	if err := prepareEventDetails(&details); err != nil {
+		return err
+	}
+
+	newRule, err := updatedRecurrenceRule(event, rule, scope, occurrenceDate, recurrenceRule)
+	if err != nil {
+		return err
+	}
+
+	attendeeIDs := userIDs
+	if attendeeIDs == nil {
+		for _, user := range event.Users {
+			attendeeIDs = append(attendeeIDs, user.ID)
+		}
+	}
+	if err := u.checkConflicts(event.GroupID, details, newRule, attendeeIDs, event.ID, force, userID); err != nil {
+		return err
+	}
+
+	switch scope {
+	case helpers.SeriesScopeThis:
+		return u.updateOccurrence(event, *occurrenceDate, details, trackIDs, userIDs)
+	case helpers.SeriesScopeFollowing:
+		return u.updateFollowing(event, rule, *occurrenceDate, details, newRule, trackIDs, userIDs)
+	}
+
+	previous := *event
+	event.RecurrenceRule = newRule
+
+	if err := u.updateEventBasicInfo(event, details); err != nil {
 		return err
 	}
 
@@ -146,21 +470,413 @@ func (u *EventUsecase) UpdateEvent(id uint, title, description, location string,
 		}
 	}
 
+	u.calendarSync.ScheduleSync(event.ID)
+	u.notifyChange(&previous, event)
+	u.reminders.ScheduleEvent(event.ID)
 	return nil
 }
 
-// Validates if the user has sufficient permissions (manager or moderator) in the group.
-func (u *EventUsecase) validateUserPermissions(userID, groupID uint) error {
-	role, err := u.groupRepo.GetUserRole(userID, groupID)
+// resolveSeriesScope validates the scope of a change to an event. Changes to
+// single events always apply to the whole event; changes to recurring events
+// must give a scope, and an occurrence unless they apply to the whole series.
+// A change to the first occurrence and all following ones is a change to the
+// whole series.
+func (u *EventUsecase) resolveSeriesScope(event *model.Event, scope string, occurrenceDate *time.Time) (*helpers.RecurrenceRule, string, error) {
+	if event.RecurrenceRule == "" {
+		return nil, helpers.SeriesScopeAll, nil
+	}
+
+	if scope == "" {
+		return nil, "", ErrSeriesScopeRequired
+	}
+	if !helpers.IsValidSeriesScope(scope) {
+		return nil, "", errors.New("invalid scope - must be 'this', 'following' or 'all'")
+	}
+
+	if occurrenceDate == nil {
+		if scope == helpers.SeriesScopeAll {
+			return nil, helpers.SeriesScopeAll, nil
+		}
+		return nil, "", errors.New("occurrence date is required")
+	}
+
+	rule, err := helpers.ParseRecurrenceRule(event.RecurrenceRule)
 	if err != nil {
-		return errors.New("User not in group")
+		return nil, "", err
+	}
+
+	if !rule.IsOccurrence(seriesStart(event), *occurrenceDate) || isException(event, *occurrenceDate) {
+		return nil, "", errors.New("no such occurrence")
+	}
+
+	if scope == helpers.SeriesScopeAll {
+		return nil, helpers.SeriesScopeAll, nil
+	}
+	if scope == helpers.SeriesScopeFollowing && occurrenceDate.Equal(event.Date) {
+		return rule, helpers.SeriesScopeAll, nil
+	}
+
+	return rule, scope, nil
+}
+
+// updateOccurrence detaches a single occurrence from its series as a separate event.
+func (u *EventUsecase) updateOccurrence(series *model.Event, occurrenceDate time.Time, details domain.EventDetails,
+	trackIDs []uint, userIDs []uint) error {
+
+	if err := u.eventRepo.AddException(series.ID, occurrenceDate); err != nil {
+		return err
+	}
+
+	override := &model.Event{
+		GroupID:         series.GroupID,
+		SeriesID:        &series.ID,
+		OriginalDate:    &occurrenceDate,
+		ReminderMinutes: series.ReminderMinutes,
+	}
+	applyEventDetails(override, details)
+	if err := u.eventRepo.CreateEvent(override); err != nil {
+		return err
+	}
+
+	if err := u.copySeriesAssociations(series, override, trackIDs, userIDs); err != nil {
+		return err
+	}
+
+	u.calendarSync.ScheduleSync(series.ID)
+	u.calendarSync.ScheduleSync(override.ID)
+	u.notifyChange(occurrenceOf(series, occurrenceDate), override)
+	u.reminders.ScheduleEvent(override.ID)
+	return nil
+}
+
+// updateFollowing ends a series before the occurrence and starts a new series
+// from it with the changed details.
+func (u *EventUsecase) updateFollowing(series *model.Event, rule *helpers.RecurrenceRule, occurrenceDate time.Time,
+	details domain.EventDetails, newRuleValue string, trackIDs []uint, userIDs []uint) error {
+
+	if err := u.endSeriesBefore(series, rule, occurrenceDate); err != nil {
+		return err
+	}
+
+	following := &model.Event{
+		RecurrenceRule:  newRuleValue,
+		GroupID:         series.GroupID,
+		ReminderMinutes: series.ReminderMinutes,
+	}
+	applyEventDetails(following, details)
+	if err := u.eventRepo.CreateEvent(following); err != nil {
+		return err
+	}
+
+	if err := u.copySeriesAssociations(series, following, trackIDs, userIDs); err != nil {
+		return err
+	}
+
+	var err error
+
+	// Separately edited occurrences only still line up with the new series if it starts at the same time.
+	if details.Date.Equal(occurrenceDate) && newRuleValue == remainingRule(series, rule, occurrenceDate) {
+		err = u.eventRepo.MoveSeriesOverrides(series.ID, following.ID, occurrenceDate)
+	} else {
+		err = u.deleteSeriesOverrides(series.ID, occurrenceDate)
 	}
-	if !helpers.IsManagerOrModeratorRole(role) {
-		return errors.New("insufficient permissions")
+	if err != nil {
+		return err
 	}
+
+	u.calendarSync.ScheduleSync(series.ID)
+	u.calendarSync.ScheduleSync(following.ID)
+	u.notifyChange(occurrenceOf(series, occurrenceDate), following)
+	u.reminders.ScheduleEvent(following.ID)
 	return nil
 }
 
+// deleteSeriesOverrides deletes the separately edited occurrences of a series
+// from the given occurrence date on, along with their calendar copies.
+func (u *EventUsecase) deleteSeriesOverrides(seriesID uint, from time.Time) error {
+	overrideIDs, err := u.eventRepo.GetSeriesOverrideIDs(seriesID, &from)
+	if err != nil {
+		return err
+	}
+	copies := u.calendarSync.SyncedCopies(overrideIDs)
+
+	if err := u.eventRepo.DeleteSeriesOverrides(seriesID, from); err != nil {
+		return err
+	}
+	u.calendarSync.ScheduleRemoval(copies)
+	return nil
+}
+
+// updatedRecurrenceRule returns the recurrence rule of the event resulting
+// from an update with the given scope. A nil recurrence rule keeps the rule
+// of the series.
+func updatedRecurrenceRule(event *model.Event, rule *helpers.RecurrenceRule, scope string,
+	occurrenceDate *time.Time, recurrenceRule *string) (string, error) {
+
+	switch {
+	case scope == helpers.SeriesScopeThis:
+		return "", nil
+	case recurrenceRule != nil:
+		return normalizeRecurrenceRule(*recurrenceRule)
+	case scope == helpers.SeriesScopeFollowing:
+		return remainingRule(event, rule, *occurrenceDate), nil
+	}
+	return event.RecurrenceRule, nil
+}
+
+// remainingRule returns the rule of the part of a series starting at the occurrence.
+func remainingRule(series *model.Event, rule *helpers.RecurrenceRule, occurrenceDate time.Time) string {
+	remaining := *rule
+	if remaining.Count > 0 {
+		remaining.Count -= rule.CountBefore(seriesStart(series), occurrenceDate)
+	}
+	return remaining.String()
+}
+
+// endSeriesBefore limits a series to the occurrences before the given one.
+func (u *EventUsecase) endSeriesBefore(series *model.Event, rule *helpers.RecurrenceRule, occurrenceDate time.Time) error {
+	truncated := *rule
+	until := occurrenceDate.Add(-time.Second)
+	truncated.Count = 0
+	truncated.Until = &until
+
+	series.RecurrenceRule = truncated.String()
+	return u.eventRepo.UpdateEvent(series)
+}
+
+// copySeriesAssociations assigns the given tracks and users to an event split
+// off a series, defaulting to the ones of the series.
+func (u *EventUsecase) copySeriesAssociations(series, event *model.Event, trackIDs []uint, userIDs []uint) error {
+	if trackIDs == nil {
+		for _, track := range series.Tracks {
+			trackIDs = append(trackIDs, track.ID)
+		}
+	}
+	if err := u.addTracksToEvent(event, trackIDs); err != nil {
+		return err
+	}
+
+	if err := u.setlistRepo.CopySetlist(series.ID, event.ID, event.Date.Sub(series.Date)); err != nil {
+		return err
+	}
+	if err := u.eventRepo.CopySubgroupNotes(series.ID, event.ID); err != nil {
+		return err
+	}
+
+	if userIDs == nil {
+		for _, user := range series.Users {
+			userIDs = append(userIDs, user.ID)
+		}
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+	return u.addUsersToEvent(event, userIDs)
+}
+
+// seriesStart returns the start of a series in its time zone, so that
+// occurrences keep their local time across daylight saving time changes.
+func seriesStart(event *model.Event) time.Time {
+	return event.Date.In(helpers.EventLocation(event))
+}
+
+// normalizeRecurrenceRule validates a recurrence rule and returns it in canonical form.
+func normalizeRecurrenceRule(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	rule, err := helpers.ParseRecurrenceRule(value)
+	if err != nil {
+		return "", err
+	}
+	return rule.String(), nil
+}
+
+// isException reports whether the occurrence was cancelled or edited separately.
+func isException(event *model.Event, occurrenceDate time.Time) bool {
+	for _, exception := range event.Exceptions {
+		if exception.OccurrenceDate.Equal(occurrenceDate) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandOccurrences replaces every recurring event by its occurrences within
+// the date range and returns all events ordered by date.
+func expandOccurrences(events []*model.Event, from, to *time.Time) []*model.Event {
+	now := time.Now()
+	rangeFrom, rangeTo := now.Add(-defaultOccurrencesBefore), now.Add(defaultOccurrencesAfter)
+	if from != nil {
+		rangeFrom = *from
+	}
+	if to != nil {
+		rangeTo = *to
+	}
+
+	expanded := make([]*model.Event, 0, len(events))
+	for _, event := range events {
+		if event.RecurrenceRule == "" {
+			expanded = append(expanded, event)
+			continue
+		}
+
+		rule, err := helpers.ParseRecurrenceRule(event.RecurrenceRule)
+		if err != nil {
+			log.Printf("Skipping event %d with invalid recurrence rule: %v", event.ID, err)
+			continue
+		}
+
+		excluded := make([]time.Time, 0, len(event.Exceptions))
+		for _, exception := range event.Exceptions {
+			excluded = append(excluded, exception.OccurrenceDate)
+		}
+
+		for _, occurrenceDate := range rule.Occurrences(seriesStart(event), rangeFrom, rangeTo, excluded) {
+			expanded = append(expanded, occurrenceOf(event, occurrenceDate))
+		}
+	}
+
+	sort.SliceStable(expanded, func(i, j int) bool {
+		return expanded[i].Date.Before(expanded[j].Date)
+	})
+	return expanded
+}
+
+// occurrenceOf returns the occurrence of a recurring event starting at occurrenceDate.
+func occurrenceOf(event *model.Event, occurrenceDate time.Time) *model.Event {
+	occurrence := *event
+	occurrence.Date = occurrenceDate
+	occurrence.EndDate = helpers.OccurrenceEnd(event, occurrenceDate)
+	occurrence.OccurrenceDate = &occurrenceDate
+	return &occurrence
+}
+
+// nextOccurrence returns the first occurrence of an event starting at from
+// or later and not in the past, or nil if there is none.
+func nextOccurrence(event *model.Event, from time.Time) *model.Event {
+	if now := time.Now(); from.Before(now) {
+		from = now
+	}
+	to := from.Add(defaultOccurrencesAfter)
+	for _, occurrence := range expandOccurrences([]*model.Event{event}, &from, &to) {
+		if !occurrence.Date.Before(from) {
+			return occurrence
+		}
+	}
+	return nil
+}
+
+// checkConflicts refuses to schedule members who are already booked at the
+// time of an event, unless a member allowed to do so forces it.
+func (u *EventUsecase) checkConflicts(groupID uint, details domain.EventDetails, recurrenceRule string,
+	userIDs []uint, excludeEventID uint, force bool, userID uint) error {
+
+	if force {
+		return u.policy.Require(userID, groupID, helpers.PermEventConflictsForce)
+	}
+
+	conflicts, err := u.findConflicts(groupID, details, recurrenceRule, userIDs, excludeEventID)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		return &EventConflictError{Conflicts: conflicts}
+	}
+	return nil
+}
+
+// findConflicts lists the events in any group that the members are assigned
+// to and that overlap the event, or one of its occurrences within the
+// default expansion window for recurring events. Without members all group
+// members are checked, as they are all assigned to such an event.
+func (u *EventUsecase) findConflicts(groupID uint, details domain.EventDetails, recurrenceRule string,
+	userIDs []uint, excludeEventID uint) ([]domain.EventConflict, error) {
+
+	if len(userIDs) == 0 {
+		members, err := u.groupRepo.GetGroupMembers(groupID)
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range members {
+			userIDs = append(userIDs, member.ID)
+		}
+	}
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	candidate := &model.Event{RecurrenceRule: recurrenceRule, GroupID: groupID}
+	applyEventDetails(candidate, details)
+	horizon := details.Date.Add(defaultOccurrencesAfter)
+	slots := expandOccurrences([]*model.Event{candidate}, &details.Date, &horizon)
+	if len(slots) == 0 {
+		return nil, nil
+	}
+
+	from, to := slots[0].Date, helpers.EventEnd(slots[len(slots)-1])
+	events, err := u.eventRepo.GetUsersEvents(userIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// Occurrences starting before the first slot may still be running when it starts.
+	var longest time.Duration
+	for _, event := range events {
+		if length := helpers.EventEnd(event).Sub(event.Date); length > longest {
+			longest = length
+		}
+	}
+	lookback := from.Add(-longest)
+
+	checked := make(map[uint]bool, len(userIDs))
+	for _, id := range userIDs {
+		checked[id] = true
+	}
+
+	var conflicts []domain.EventConflict
+	for _, occurrence := range expandOccurrences(events, &lookback, &to) {
+		if excludeEventID != 0 && (occurrence.ID == excludeEventID ||
+			(occurrence.SeriesID != nil && *occurrence.SeriesID == excludeEventID)) {
+			continue
+		}
+
+		end := helpers.EventEnd(occurrence)
+		if !overlapsAny(occurrence.Date, end, slots) {
+			continue
+		}
+
+		for _, user := range occurrence.Users {
+			if !checked[user.ID] {
+				continue
+			}
+			conflicts = append(conflicts, domain.EventConflict{
+				UserID:     user.ID,
+				FirstName:  user.FirstName,
+				LastName:   user.LastName,
+				EventID:    occurrence.ID,
+				EventTitle: occurrence.Title,
+				EventType:  occurrence.Type,
+				Date:       occurrence.Date,
+				EndDate:    end,
+				GroupID:    occurrence.GroupID,
+				GroupName:  occurrence.Group.Name,
+			})
+		}
+	}
+	return conflicts, nil
+}
+
+// overlapsAny reports whether the time range overlaps any of the events.
+func overlapsAny(start, end time.Time, events []*model.Event) bool {
+	for _, event := range events {
+		if start.Before(helpers.EventEnd(event)) && end.After(event.Date) {
+			return true
+		}
+	}
+	return false
+}
+
 // Checks if the user is a member of the group.
 func (u *EventUsecase) isUserInGroup(userID, groupID uint) bool {
 	_, err := u.groupRepo.GetUserRole(userID, groupID)
@@ -213,11 +929,7 @@ func (u *EventUsecase) addUsersToEvent(event *model.Event, userIDs []uint) error
 
 // Handles external integrations like Google Calendar and email notifications.
 func (u *EventUsecase) handleExternalIntegrations(event *model.Event, userIDs []uint) {
-	if u.gcService != nil {
-		if err := u.gcService.CreateCalendarEvent(event); err != nil {
-			log.Printf("Failed to sync with Google Calendar: %v", err)
-		}
-	}
+	u.calendarSync.ScheduleSync(event.ID)
 
 	recipients, err := u.getEventRecipients(event.GroupID, userIDs)
 	if err != nil {
@@ -225,9 +937,165 @@ func (u *EventUsecase) handleExternalIntegrations(event *model.Event, userIDs []
 		return
 	}
 
+	recipients = helpers.VerifiedRecipients(recipients)
+	if len(recipients) > 0 {
+		u.jobs.EventEmails(event.ID, recipients)
+	}
+}
+
+// notifyChange tells the members assigned to an event that its time or place
+// changed, unless the event was and still is over.
+func (u *EventUsecase) notifyChange(previous, updated *model.Event) {
+	if previous.Date.Equal(updated.Date) && helpers.EventEnd(previous).Equal(helpers.EventEnd(updated)) &&
+		previous.AllDay == updated.AllDay && previous.Location == updated.Location {
+		return
+	}
+	now := time.Now()
+	if updated.RecurrenceRule == "" && helpers.EventEnd(previous).Before(now) && helpers.EventEnd(updated).Before(now) {
+		return
+	}
+
+	recipients, err := u.eventRepo.GetEventUsers(updated.ID)
+	if err != nil {
+		log.Printf("Failed to get recipients: %v", err)
+		return
+	}
+
+	recipients = helpers.VerifiedRecipients(recipients)
+	if len(recipients) > 0 {
+		u.jobs.EventChangeEmails(updated.ID, recipients, previous)
+	}
+}
+
+// notifyCancellation tells the members assigned to an event that the given
+// occurrence of it, or with series every occurrence from then on, is cancelled.
+func (u *EventUsecase) notifyCancellation(event, cancelled *model.Event, series bool) {
+	recipients := helpers.VerifiedRecipients(event.Users)
 	if len(recipients) > 0 {
-		go u.emailService.SendEventEmail(event, recipients)
+		u.jobs.EventCancelEmails(cancelled, series, recipients)
+	}
+}
+
+// RespondToEvent records whether an assigned member will attend an event.
+func (u *EventUsecase) RespondToEvent(eventID, userID uint, status, comment string) (*model.EventUser, error) {
+	if !helpers.IsValidRSVPResponse(status) {
+		return nil, errors.New("invalid response - must be 'yes', 'no' or 'maybe'")
+	}
+
+	event, err := u.eventRepo.GetEventByID(eventID)
+	if err != nil {
+		return nil, errors.New("Could not find event")
+	}
+
+	if !u.isUserInGroup(userID, event.GroupID) {
+		return nil, errors.New("User not in group")
+	}
+
+	if _, err := u.eventRepo.GetAttendee(eventID, userID); err != nil {
+		return nil, errors.New("user is not assigned to this event")
+	}
+
+	if err := u.eventRepo.UpdateRSVP(eventID, userID, status, comment); err != nil {
+		return nil, err
+	}
+
+	return u.eventRepo.GetAttendee(eventID, userID)
+}
+
+// RespondWithToken records a response submitted through a respond-link from an event email.
+func (u *EventUsecase) RespondWithToken(token, status string) (*model.EventUser, error) {
+	attendee, err := u.eventRepo.GetAttendeeByResponseToken(helpers.HashToken(token))
+	if err != nil || token == "" {
+		return nil, errors.New("invalid response link")
+	}
+
+	return u.RespondToEvent(attendee.EventID, attendee.UserID, status, attendee.RSVPComment)
+}
+
+// GetEventAttendance returns the responses of all assigned members, rolled up per subgroup.
+func (u *EventUsecase) GetEventAttendance(eventID, userID uint) (*domain.EventAttendance, error) {
+	event, err := u.eventRepo.GetEventByID(eventID)
+	if err != nil {
+		return nil, errors.New("Could not find event")
+	}
+
+	if err := u.policy.Require(userID, event.GroupID, helpers.PermEventAttendanceView); err != nil {
+		return nil, err
+	}
+
+	attendees, err := u.eventRepo.GetEventAttendees(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make(map[uint]*model.User, len(event.Users))
+	for _, user := range event.Users {
+		users[user.ID] = user
+	}
+
+	infos := make(map[uint]domain.AttendeeInfo, len(attendees))
+	attendance := &domain.EventAttendance{
+		EventID:   eventID,
+		Totals:    newRSVPTotals(),
+		Subgroups: []domain.SubgroupAttendance{},
+		Ungrouped: []domain.AttendeeInfo{},
+	}
+	for _, attendee := range attendees {
+		info := domain.AttendeeInfo{
+			UserID:      attendee.UserID,
+			Status:      attendee.RSVPStatus,
+			Comment:     attendee.RSVPComment,
+			RespondedAt: attendee.RespondedAt,
+		}
+		if user, ok := users[attendee.UserID]; ok {
+			info.FirstName = user.FirstName
+			info.LastName = user.LastName
+		}
+		infos[attendee.UserID] = info
+		attendance.Totals[info.Status]++
+	}
+
+	subgroups, err := u.subgroupRepo.GetGroupSubgroups(event.GroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	grouped := make(map[uint]bool)
+	for _, subgroup := range subgroups {
+		rollup := domain.SubgroupAttendance{
+			SubgroupID: subgroup.ID,
+			Name:       subgroup.Name,
+			Totals:     newRSVPTotals(),
+			Attendees:  []domain.AttendeeInfo{},
+		}
+		for _, member := range subgroup.Users {
+			info, ok := infos[member.ID]
+			if !ok {
+				continue
+			}
+			rollup.Attendees = append(rollup.Attendees, info)
+			rollup.Totals[info.Status]++
+			grouped[member.ID] = true
+		}
+		attendance.Subgroups = append(attendance.Subgroups, rollup)
 	}
+
+	for _, attendee := range attendees {
+		if !grouped[attendee.UserID] {
+			attendance.Ungrouped = append(attendance.Ungrouped, infos[attendee.UserID])
+		}
+	}
+
+	return attendance, nil
+}
+
+// newRSVPTotals returns a zeroed counter for every attendance status.
+func newRSVPTotals() map[string]int {
+	totals := make(map[string]int, len(helpers.RSVPStatuses))
+	for _, status := range helpers.RSVPStatuses {
+		totals[status] = 0
+	}
+	return totals
 }
 
 // Returns a list of users to receive event notifications (either specific users or all group members).
@@ -247,12 +1115,76 @@ func (u *EventUsecase) getEventRecipients(groupID uint, userIDs []uint) ([]*mode
 	return u.groupRepo.GetGroupMembers(groupID)
 }
 
-// Updates basic event details (title, description, location, date).
-func (u *EventUsecase) updateEventBasicInfo(event *model.Event, title, description, location string, date time.Time) error {
-	event.Title = title
-	event.Description = description
-	event.Location = location
-	event.Date = date
-
+// Updates basic event details (title, description, location, type and timing).
+func (u *EventUsecase) updateEventBasicInfo(event *model.Event, details domain.EventDetails) error {
+	applyEventDetails(event, details)
 	return u.eventRepo.UpdateEvent(event)
 }
+
+// applyEventDetails copies validated event details onto an event.
+func applyEventDetails(event *model.Event, details domain.EventDetails) {
+	event.Title = details.Title
+	event.Description = details.Description
+	event.Location = details.Location
+	event.Type = details.Type
+	event.Date = details.Date
+	event.EndDate = details.EndDate
+	event.AllDay = details.AllDay
+	event.TimeZone = details.TimeZone
+}
+
+// prepareEventDetails validates event details and fills in defaults. All-day
+// events are aligned to midnight of their days in the event's time zone and
+// timed events without an end last the default duration.
+func prepareEventDetails(details *domain.EventDetails) error {
+	if details.Type == "" {
+		details.Type = helpers.EventTypeRehearsal
+	}
+	if !helpers.IsValidEventType(details.Type) {
+		return errors.New("invalid event type")
+	}
+
+	location, err := helpers.LoadTimeZone(details.TimeZone)
+	if err != nil {
+		return err
+	}
+
+	if details.Date.IsZero() {
+		return errors.New("start date is required")
+	}
+
+	if details.AllDay {
+		details.Date = helpers.StartOfDay(details.Date, location)
+		if details.EndDate.IsZero() {
+			details.EndDate = details.Date
+		} else {
+			details.EndDate = helpers.StartOfDay(details.EndDate, location)
+		}
+	} else if details.EndDate.IsZero() {
+		details.EndDate = details.Date.Add(helpers.DefaultEventDuration)
+	}
+
+	if details.EndDate.Before(details.Date) {
+		return errors.New("end date cannot be before start date")
+	}
+	return nil
+}
+
+// validateEventTypes checks an event type filter.
+func validateEventTypes(eventTypes []string) error {
+	for _, eventType := range eventTypes {
+		if !helpers.IsValidEventType(eventType) {
+			return errors.New("invalid event type")
+		}
+	}
+	return nil
+}
+
+// groupTimeZone returns the time zone new events of a group are created in.
+func (u *EventUsecase) groupTimeZone(groupID uint) string {
+	group, err := u.groupRepo.GetGroupByID(groupID)
+	if err != nil || group.TimeZone == "" {
+		return helpers.DefaultTimeZone
+	}
+	return group.TimeZone
+}
@@ -1,6 +1,8 @@
 package usecases
 
 import (
+	"band-manager-backend/internal/config"
+	"band-manager-backend/internal/domain"
 	"band-manager-backend/internal/model"
 	"band-manager-backend/internal/repositories"
 	"band-manager-backend/internal/usecases/helpers"
@@ -12,31 +14,34 @@ import (
 
 // GroupUsecase implements group management logic.
 type GroupUsecase struct {
-	groupRepo *repositories.GroupRepository
-	userRepo  *repositories.UserRepository
+	groupRepo       *repositories.GroupRepository
+	userRepo        *repositories.UserRepository
+	joinRequestRepo *repositories.JoinRequestRepository
+	trackRepo       *repositories.TrackRepository
+	uploads         *config.UploadConfig
+	policy          *Policy
 }
 
-func NewGroupUsecase() *GroupUsecase {
+func NewGroupUsecase(uploads *config.UploadConfig) *GroupUsecase {
 	return &GroupUsecase{
-		groupRepo: repositories.NewGroupRepository(),
-		userRepo:  repositories.NewUserRepository(),
+		groupRepo:       repositories.NewGroupRepository(),
+		userRepo:        repositories.NewUserRepository(),
+		joinRequestRepo: repositories.NewJoinRequestRepository(),
+		trackRepo:       repositories.NewTrackRepository(),
+		uploads:         uploads,
+		policy:          NewPolicy(),
 	}
 }
 
 // RefreshAccessToken generates and updates a new access token for a group.
 func (u *GroupUsecase) RefreshAccessToken(groupID uint, requestingUserID uint) (string, error) {
-	role, err := u.groupRepo.GetUserRole(requestingUserID, groupID)
-	if err != nil {
-		return "", errors.New("user not in group")
-	}
-
-	if role != helpers.RoleManager {
-		return "", errors.New("insufficient permissions - only managers can refresh access token")
+	if err := u.policy.Require(requestingUserID, groupID, helpers.PermTokenRefresh); err != nil {
+		return "", err
 	}
 
 	newToken := generateAccessToken()
 
-	err = u.groupRepo.UpdateAccessToken(groupID, newToken)
+	err := u.groupRepo.UpdateAccessToken(groupID, newToken)
 	if err != nil {
 		return "", fmt.Errorf("failed to update access token: %v", err)
 	}
@@ -55,11 +60,22 @@ type MemberInfo struct {
 
 // GroupInfo holds the details of a group.
 type GroupInfo struct {
-	ID           uint   `json:"id"`
-	Name         string `json:"name"`
-	Description  string `json:"description"`
-	Role         string `json:"role"`
-	MembersCount int    `json:"members_count"`
+	ID           uint                 `json:"id"`
+	Name         string               `json:"name"`
+	Description  string               `json:"description"`
+	Role         string               `json:"role"`
+	Permissions  []helpers.Permission `json:"permissions"`
+	MembersCount int                  `json:"members_count"`
+}
+
+// GroupDetails holds the details of a group shown to its members.
+type GroupDetails struct {
+	Name            string              `json:"name"`
+	Description     string              `json:"description"`
+	AccessToken     string              `json:"access_token"`
+	RequireApproval bool                `json:"require_approval"`
+	TimeZone        string              `json:"time_zone"`
+	Storage         domain.StorageUsage `json:"storage"`
 }
 
 // generateAccessToken generates a random access token for group access.
@@ -97,49 +113,117 @@ func (u *GroupUsecase) CreateGroup(name, description string, userID uint) (strin
 }
 
 // JoinGroup processes a user's request to join a group via access token.
-func (u *GroupUsecase) JoinGroup(userID uint, accessToken string) (string, uint, string, error) {
+// For groups requiring approval a pending join request is created instead,
+// which is reported by the pending flag.
+func (u *GroupUsecase) JoinGroup(userID uint, accessToken string) (string, uint, string, bool, error) {
 	group, err := u.groupRepo.GetGroupByAccessToken(accessToken)
 	if err != nil {
-		return "", 0, "", errors.New("invalid access token")
+		return "", 0, "", false, errors.New("invalid access token")
 	}
 
 	user, err := u.userRepo.GetUserByID(userID)
 	if err != nil {
-		return "", 0, "", errors.New("user not found")
+		return "", 0, "", false, errors.New("user not found")
 	}
 
-	for _, g := range user.Groups {
-		if g.ID == group.ID {
-			return "", 0, "", errors.New("user already in group")
+	if !user.EmailVerified {
+		return "", 0, "", false, errors.New("email address not verified")
+	}
+
+	if _, err := u.groupRepo.GetUserRole(userID, group.ID); err == nil {
+		return "", 0, "", false, errors.New("user already in group")
+	}
+
+	if group.RequireApproval {
+		if _, err := u.joinRequestRepo.GetPendingRequest(group.ID, userID); err == nil {
+			return "", group.ID, group.Name, true, nil
+		}
+
+		err = u.joinRequestRepo.CreateRequest(&model.JoinRequest{
+			GroupID: group.ID,
+			UserID:  userID,
+		})
+		if err != nil {
+			return "", 0, "", false, errors.New("failed to create join request")
 		}
+		return "", group.ID, group.Name, true, nil
 	}
 
 	err = u.groupRepo.AddUserToGroup(userID, group.ID, helpers.RoleMember)
 	if err != nil {
-		return "", 0, "", errors.New("failed to join group")
+		return "", 0, "", false, errors.New("failed to join group")
 	}
 
-	return helpers.RoleMember, group.ID, group.Name, nil
+	return helpers.RoleMember, group.ID, group.Name, false, nil
 }
 
-// GetGroupInfo retrieves group details and access token for managers.
-func (u *GroupUsecase) GetGroupInfo(userID uint, groupID uint) (string, string, string, error) {
-	role, err := u.groupRepo.GetUserRole(userID, groupID)
-	if err != nil {
-		return "", "", "", errors.New("user not in group")
+// GetGroupInfo retrieves group details, its storage usage and the access
+// token for managers.
+func (u *GroupUsecase) GetGroupInfo(userID uint, groupID uint) (*GroupDetails, error) {
+	if err := u.policy.RequireMember(userID, groupID); err != nil {
+		return nil, errors.New("user not in group")
 	}
 
 	group, err := u.groupRepo.GetGroupByID(groupID)
 	if err != nil {
-		return "", "", "", errors.New("could not find group")
+		return nil, errors.New("could not find group")
 	}
 
-	accessToken := ""
-	if role == helpers.RoleManager {
-		accessToken = group.AccessToken
+	usage, err := u.trackRepo.GetGroupStorageUsage(groupID)
+	if err != nil {
+		return nil, err
 	}
 
-	return group.Name, group.Description, accessToken, nil
+	details := &GroupDetails{
+		Name:            group.Name,
+		Description:     group.Description,
+		RequireApproval: group.RequireApproval,
+		TimeZone:        group.TimeZone,
+		Storage: domain.StorageUsage{
+			UsedBytes:   usage,
+			QuotaBytes:  u.uploads.GroupStorageQuota,
+			MaxFileSize: u.uploads.MaxFileSize,
+		},
+	}
+	if u.policy.Can(userID, groupID, helpers.PermTokenView) {
+		details.AccessToken = group.AccessToken
+	}
+
+	return details, nil
+}
+
+// UpdateSettings changes whether joining by access token requires approval,
+// the time zone of the group and how many minutes before events members are
+// reminded of them. Nil settings are left unchanged.
+func (u *GroupUsecase) UpdateSettings(groupID uint, requireApproval *bool, timeZone *string, reminderMinutes []int, requestingUserID uint) error {
+	if err := u.policy.Require(requestingUserID, groupID, helpers.PermGroupSettingsUpdate); err != nil {
+		return err
+	}
+
+	normalizedReminders, err := helpers.NormalizeReminderMinutes(reminderMinutes)
+	if err != nil {
+		return err
+	}
+
+	if timeZone != nil {
+		if _, err := helpers.LoadTimeZone(*timeZone); err != nil {
+			return err
+		}
+		if err := u.groupRepo.UpdateTimeZone(groupID, *timeZone); err != nil {
+			return err
+		}
+	}
+
+	if normalizedReminders != nil {
+		if err := u.groupRepo.UpdateReminderMinutes(groupID, normalizedReminders); err != nil {
+			return err
+		}
+	}
+
+	if requireApproval != nil {
+		return u.groupRepo.UpdateRequireApproval(groupID, *requireApproval)
+	}
+	return nil
 }
 
 // GetGroupMembers retrieves all members of a group with their roles.
@@ -193,6 +277,7 @@ func (u *GroupUsecase) GetUserGroups(userID uint) ([]GroupInfo, error) {
 			Name:         group.Name,
 			Description:  group.Description,
 			Role:         role.Role,
+			Permissions:  u.policy.RolePermissions(group.ID, role.Role),
 			MembersCount: membersCount,
 		})
 	}
@@ -203,51 +288,118 @@ func (u *GroupUsecase) GetUserGroups(userID uint) ([]GroupInfo, error) {
 // RemoveMember removes a user from a group if requester has permissions.
 func (u *GroupUsecase) RemoveMember(groupID, userToRemoveID, requestingUserID uint) error {
 
-	requesterRole, err := u.groupRepo.GetUserRole(requestingUserID, groupID)
-	if err != nil {
-		return errors.New("requesting user not in group")
-	}
-
-	if !helpers.IsManagerOrModeratorRole(requesterRole) {
-		return errors.New("insufficient permissions")
+	if err := u.policy.Require(requestingUserID, groupID, helpers.PermMemberRemove); err != nil {
+		return err
 	}
 
 	if userToRemoveID == requestingUserID {
 		return errors.New("cannot remove yourself from group")
 	}
 
-	return u.groupRepo.RemoveUserFromGroup(userToRemoveID, groupID)
+	currentRole, err := u.groupRepo.GetUserRole(userToRemoveID, groupID)
+	if err != nil {
+		return errors.New("user not in group")
+	}
+
+	if err := u.checkManagerChange(groupID, requestingUserID, currentRole, ""); err != nil {
+		return err
+	}
+
+	return managerChangeError(u.groupRepo.RemoveUserFromGroup(userToRemoveID, groupID, helpers.RoleManager))
 }
 
 // UpdateMemberRole changes a user's role within a group.
 func (u *GroupUsecase) UpdateMemberRole(groupID uint, userToUpdateID uint, requestingUserID uint, newRole string) error {
-	requesterRole, err := u.groupRepo.GetUserRole(requestingUserID, groupID)
+	if err := u.policy.Require(requestingUserID, groupID, helpers.PermMemberRoleUpdate); err != nil {
+		return err
+	}
+
+	if userToUpdateID == requestingUserID {
+		return errors.New("cannot change your own role")
+	}
+
+	if !u.policy.RoleExists(groupID, newRole) {
+		return errors.New("invalid role - must be 'manager', 'moderator', 'member' or a custom role of the group")
+	}
+	if err := u.policy.RequireGrantable(requestingUserID, groupID, newRole); err != nil {
+		return err
+	}
+
+	currentRole, err := u.groupRepo.GetUserRole(userToUpdateID, groupID)
 	if err != nil {
-		return errors.New("requesting user not in group")
+		return errors.New("user not in group")
 	}
 
-	if requesterRole != helpers.RoleManager {
-		return errors.New("insufficient permissions - only managers can change roles")
+	if err := u.checkManagerChange(groupID, requestingUserID, currentRole, newRole); err != nil {
+		return err
 	}
 
-	if userToUpdateID == requestingUserID {
-		return errors.New("cannot change your own role")
+	return managerChangeError(u.groupRepo.UpdateUserRole(userToUpdateID, groupID, newRole, helpers.RoleManager))
+}
+
+// TransferOwnership makes another member a manager and demotes the requesting manager to moderator.
+func (u *GroupUsecase) TransferOwnership(groupID, newOwnerID, requestingUserID uint) error {
+	role, err := u.groupRepo.GetUserRole(requestingUserID, groupID)
+	if err != nil {
+		return ErrAccessDenied
+	}
+	if role != helpers.RoleManager {
+		return errors.New("only managers can transfer ownership")
+	}
+
+	if newOwnerID == requestingUserID {
+		return errors.New("cannot transfer ownership to yourself")
+	}
+
+	if _, err := u.groupRepo.GetUserRole(newOwnerID, groupID); err != nil {
+		return errors.New("user not in group")
+	}
+
+	// The roles may have changed since they were checked; the repository
+	// checks them again while holding both memberships.
+	err = u.groupRepo.TransferRole(groupID, requestingUserID, newOwnerID, helpers.RoleManager, helpers.RoleModerator)
+	if errors.Is(err, repositories.ErrRoleNotHeld) {
+		return errors.New("only managers can transfer ownership")
+	}
+	if errors.Is(err, repositories.ErrNotGroupMember) {
+		return errors.New("user not in group")
 	}
+	return err
+}
 
-	if !isValidRole(newRole) {
-		return errors.New("invalid role - must be 'manager', 'moderator', or 'member'")
+// LeaveGroup removes the requesting user from a group. The last manager
+// has to transfer ownership first.
+func (u *GroupUsecase) LeaveGroup(groupID, userID uint) error {
+	if _, err := u.groupRepo.GetUserRole(userID, groupID); err != nil {
+		return errors.New("user not in group")
 	}
 
-	return u.groupRepo.UpdateUserRole(userToUpdateID, groupID, newRole)
+	return managerChangeError(u.groupRepo.RemoveUserFromGroup(userID, groupID, helpers.RoleManager))
 }
 
-// isValidRole checks if the provided role is valid.
-func isValidRole(role string) bool {
-	validRoles := []string{helpers.RoleManager, helpers.RoleModerator, helpers.RoleMember}
-	for _, r := range validRoles {
-		if r == role {
-			return true
+// checkManagerChange verifies that a member's role may change from currentRole
+// to newRole (empty when the member is removed): only managers may act on
+// managers or appoint them. That the group keeps at least one manager is
+// checked by the repository, in the transaction changing the role.
+func (u *GroupUsecase) checkManagerChange(groupID, requestingUserID uint, currentRole, newRole string) error {
+	if currentRole == helpers.RoleManager || newRole == helpers.RoleManager {
+		requesterRole, err := u.groupRepo.GetUserRole(requestingUserID, groupID)
+		if err != nil {
+			return ErrAccessDenied
+		}
+		if requesterRole != helpers.RoleManager {
+			return errors.New("only managers can act on managers")
 		}
 	}
-	return false
+
+	return nil
+}
+
+// managerChangeError explains that the group must keep a manager when the
+// repository refused to take the manager role from its last holder.
+func managerChangeError(err error) error {
+	if errors.Is(err, repositories.ErrLastRoleHolder) {
+		return errors.New("group must keep at least one manager - transfer ownership first")
+	}
+	return err
 }
@@ -0,0 +1,147 @@
+package usecases
+
+import (
+	"band-manager-backend/internal/model"
+	"band-manager-backend/internal/repositories"
+	"band-manager-backend/internal/usecases/helpers"
+	"errors"
+)
+
+// InstrumentUsecase manages the instrument catalogues of groups and the
+// instruments members play.
+type InstrumentUsecase struct {
+	instrumentRepo *repositories.InstrumentRepository
+	policy         *Policy
+}
+
+func NewInstrumentUsecase() *InstrumentUsecase {
+	return &InstrumentUsecase{
+		instrumentRepo: repositories.NewInstrumentRepository(),
+		policy:         NewPolicy(),
+	}
+}
+
+// GetGroupInstruments lists the instrument catalogue of a group with the
+// members playing each instrument.
+func (u *InstrumentUsecase) GetGroupInstruments(groupID, userID uint) ([]*model.Instrument, error) {
+	if err := u.policy.RequireMember(userID, groupID); err != nil {
+		return nil, err
+	}
+
+	return u.instrumentRepo.GetGroupInstruments(groupID)
+}
+
+// CreateInstrument adds an instrument to the catalogue of a group.
+func (u *InstrumentUsecase) CreateInstrument(groupID uint, name, family, transposition, clef string, userID uint) (*model.Instrument, error) {
+	if err := u.policy.Require(userID, groupID, helpers.PermInstrumentsManage); err != nil {
+		return nil, err
+	}
+
+	instrument := &model.Instrument{
+		GroupID:       groupID,
+		Name:          name,
+		Family:        family,
+		Transposition: transposition,
+		Clef:          clef,
+	}
+	if err := u.validateInstrument(instrument); err != nil {
+		return nil, err
+	}
+
+	if err := u.instrumentRepo.CreateInstrument(instrument); err != nil {
+		return nil, err
+	}
+	instrument.PlayerIDs = []uint{}
+	return instrument, nil
+}
+
+// UpdateInstrument changes an instrument of a group's catalogue. Parts for
+// the instrument are renamed with it.
+func (u *InstrumentUsecase) UpdateInstrument(instrumentID uint, name, family, transposition, clef string, userID uint) (*model.Instrument, error) {
+	instrument, err := u.instrumentRepo.GetInstrumentByID(instrumentID)
+	if err != nil {
+		return nil, errors.New("instrument not found")
+	}
+
+	if err := u.policy.Require(userID, instrument.GroupID, helpers.PermInstrumentsManage); err != nil {
+		return nil, err
+	}
+
+	instrument.Name = name
+	instrument.Family = family
+	instrument.Transposition = transposition
+	instrument.Clef = clef
+	if err := u.validateInstrument(instrument); err != nil {
+		return nil, err
+	}
+
+	if err := u.instrumentRepo.UpdateInstrument(instrument); err != nil {
+		return nil, err
+	}
+	return instrument, nil
+}
+
+// DeleteInstrument removes an instrument from a group's catalogue. Parts
+// for it stay, named after it.
+func (u *InstrumentUsecase) DeleteInstrument(instrumentID uint, userID uint) error {
+	instrument, err := u.instrumentRepo.GetInstrumentByID(instrumentID)
+	if err != nil {
+		return errors.New("instrument not found")
+	}
+
+	if err := u.policy.Require(userID, instrument.GroupID, helpers.PermInstrumentsManage); err != nil {
+		return err
+	}
+
+	return u.instrumentRepo.DeleteInstrument(instrument.ID)
+}
+
+// GetUserInstruments lists the instruments of a group's catalogue the user plays.
+func (u *InstrumentUsecase) GetUserInstruments(groupID, userID uint) ([]*model.Instrument, error) {
+	if err := u.policy.RequireMember(userID, groupID); err != nil {
+		return nil, err
+	}
+
+	return u.instrumentRepo.GetUserInstruments(groupID, userID)
+}
+
+// SetUserInstruments declares which instruments of a group's catalogue the
+// user plays, replacing the instruments declared before.
+func (u *InstrumentUsecase) SetUserInstruments(groupID uint, instrumentIDs []uint, userID uint) ([]*model.Instrument, error) {
+	if err := u.policy.RequireMember(userID, groupID); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uint]bool, len(instrumentIDs))
+	unique := make([]uint, 0, len(instrumentIDs))
+	for _, instrumentID := range instrumentIDs {
+		if seen[instrumentID] {
+			continue
+		}
+		seen[instrumentID] = true
+
+		instrument, err := u.instrumentRepo.GetInstrumentByID(instrumentID)
+		if err != nil || instrument.GroupID != groupID {
+			return nil, errors.New("instrument does not belong to the group")
+		}
+		unique = append(unique, instrumentID)
+	}
+
+	if err := u.instrumentRepo.SetUserInstruments(groupID, userID, unique); err != nil {
+		return nil, err
+	}
+	return u.instrumentRepo.GetUserInstruments(groupID, userID)
+}
+
+// validateInstrument normalizes a catalogue instrument and checks that its
+// name is not taken by another instrument of the group.
+func (u *InstrumentUsecase) validateInstrument(instrument *model.Instrument) error {
+	if err := helpers.NormalizeInstrument(instrument); err != nil {
+		return err
+	}
+
+	if existing, err := u.instrumentRepo.GetGroupInstrumentByName(instrument.GroupID, instrument.Name); err == nil && existing.ID != instrument.ID {
+		return errors.New("instrument already exists")
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package usecases
+
+import (
+	"band-manager-backend/internal/model"
+	"band-manager-backend/internal/repositories"
+	"band-manager-backend/internal/usecases/helpers"
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const invitationTTL = 7 * 24 * time.Hour
+
+// InvitationUsecase handles inviting people to groups by email.
+type InvitationUsecase struct {
+	invitationRepo *repositories.InvitationRepository
+	groupRepo      *repositories.GroupRepository
+	subgroupRepo   *repositories.SubgroupRepository
+	userRepo       *repositories.UserRepository
+	jobs           *JobQueue
+	policy         *Policy
+}
+
+// InvitationInfo describes a pending invitation to whoever holds its link.
+type InvitationInfo struct {
+	GroupID       uint      `json:"group_id"`
+	GroupName     string    `json:"group_name"`
+	Email         string    `json:"email"`
+	Role          string    `json:"role"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	AccountExists bool      `json:"account_exists"`
+}
+
+func NewInvitationUsecase() *InvitationUsecase {
+	return &InvitationUsecase{
+		invitationRepo: repositories.NewInvitationRepository(),
+		groupRepo:      repositories.NewGroupRepository(),
+		subgroupRepo:   repositories.NewSubgroupRepository(),
+		userRepo:       repositories.NewUserRepository(),
+		jobs:           NewJobQueue(),
+		policy:         NewPolicy(),
+	}
+}
+
+// CreateInvitation invites an email address to the group with a pre-selected
+// role and subgroups. The inviter must hold every permission the role grants.
+func (u *InvitationUsecase) CreateInvitation(groupID uint, email, role string, subgroupIDs []uint, requestingUserID uint) (*model.GroupInvitation, error) {
+	if err := u.policy.Require(requestingUserID, groupID, helpers.PermMemberInvite); err != nil {
+		return nil, err
+	}
+
+	email = strings.ToLower(strings.TrimSpace(email))
+	if _, err := mail.ParseAddress(email); err != nil {
+		return nil, errors.New("invalid email address")
+	}
+
+	if role == "" {
+		role = helpers.RoleMember
+	}
+	if !u.policy.RoleExists(groupID, role) {
+		return nil, errors.New("invalid role")
+	}
+	if role == helpers.RoleManager {
+		requesterRole, err := u.groupRepo.GetUserRole(requestingUserID, groupID)
+		if err != nil || requesterRole != helpers.RoleManager {
+			return nil, errors.New("only managers can act on managers")
+		}
+	}
+	if err := u.policy.RequireGrantable(requestingUserID, groupID, role); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uint]bool, len(subgroupIDs))
+	unique := make([]uint, 0, len(subgroupIDs))
+	for _, subgroupID := range subgroupIDs {
+		if seen[subgroupID] {
+			continue
+		}
+		seen[subgroupID] = true
+
+		subgroup, err := u.subgroupRepo.GetSubgroupByID(subgroupID)
+		if err != nil {
+			return nil, err
+		}
+		if subgroup.GroupID != groupID {
+			return nil, errors.New("subgroup does not belong to the group")
+		}
+		unique = append(unique, subgroupID)
+	}
+	subgroupIDs = unique
+
+	if user, err := u.userRepo.GetUserByEmail(email); err == nil {
+		if _, err := u.groupRepo.GetUserRole(user.ID, groupID); err == nil {
+			return nil, errors.New("user already in group")
+		}
+	}
+
+	if _, err := u.groupRepo.GetGroupByID(groupID); err != nil {
+		return nil, err
+	}
+
+	// The token is issued by the job sending the invitation.
+	tokenHash, err := helpers.UnsentTokenHash()
+	if err != nil {
+		return nil, errors.New("failed to generate invitation token")
+	}
+
+	if err := u.invitationRepo.RevokePendingInvitations(groupID, email); err != nil {
+		return nil, err
+	}
+
+	ids := make(pq.Int64Array, 0, len(subgroupIDs))
+	for _, subgroupID := range subgroupIDs {
+		ids = append(ids, int64(subgroupID))
+	}
+
+	invitation := &model.GroupInvitation{
+		GroupID:     groupID,
+		Email:       email,
+		Role:        role,
+		SubgroupIDs: ids,
+		InvitedByID: requestingUserID,
+		TokenHash:   tokenHash,
+		ExpiresAt:   time.Now().Add(invitationTTL),
+	}
+	if err := u.invitationRepo.CreateInvitation(invitation); err != nil {
+		return nil, errors.New("failed to create invitation")
+	}
+
+	u.jobs.InvitationEmail(invitation.ID)
+
+	return invitation, nil
+}
+
+// GetPendingInvitations lists the invitations of a group that can still be accepted.
+func (u *InvitationUsecase) GetPendingInvitations(groupID, requestingUserID uint) ([]*model.GroupInvitation, error) {
+	if err := u.policy.Require(requestingUserID, groupID, helpers.PermMemberInvite); err != nil {
+		return nil, err
+	}
+	return u.invitationRepo.GetGroupPendingInvitations(groupID)
+}
+
+// RevokeInvitation invalidates a pending invitation.
+func (u *InvitationUsecase) RevokeInvitation(invitationID, requestingUserID uint) error {
+	invitation, err := u.invitationRepo.GetInvitationByID(invitationID)
+	if err != nil {
+		return errors.New("invitation not found")
+	}
+
+	if err := u.policy.Require(requestingUserID, invitation.GroupID, helpers.PermMemberInvite); err != nil {
+		return err
+	}
+
+	if invitation.AcceptedAt != nil {
+		return errors.New("invitation already accepted")
+	}
+
+	return u.invitationRepo.RevokeInvitation(invitation.ID)
+}
+
+// GetInvitation describes a pending invitation so the invitee can log in or register.
+func (u *InvitationUsecase) GetInvitation(token string) (*InvitationInfo, error) {
+	invitation, err := u.invitationRepo.GetPendingInvitation(helpers.HashToken(token))
+	if err != nil {
+		return nil, errors.New("invalid or expired invitation")
+	}
+
+	_, err = u.userRepo.GetUserByEmail(invitation.Email)
+
+	return &InvitationInfo{
+		GroupID:       invitation.GroupID,
+		GroupName:     invitation.Group.Name,
+		Email:         invitation.Email,
+		Role:          invitation.Role,
+		ExpiresAt:     invitation.ExpiresAt,
+		AccountExists: err == nil,
+	}, nil
+}
+
+// AcceptInvitation adds the user to the invited group. The invitation must be
+// addressed to the user's email; since the link was delivered there, accepting
+// it also confirms the address of a newly registered account.
+func (u *InvitationUsecase) AcceptInvitation(token string, userID uint) (*model.GroupInvitation, error) {
+	invitation, err := u.invitationRepo.GetPendingInvitation(helpers.HashToken(token))
+	if err != nil {
+		return nil, errors.New("invalid or expired invitation")
+	}
+
+	user, err := u.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if !strings.EqualFold(user.Email, invitation.Email) {
+		return nil, errors.New("invitation was sent to a different email address")
+	}
+
+	if _, err := u.groupRepo.GetUserRole(userID, invitation.GroupID); err == nil {
+		return nil, errors.New("user already in group")
+	}
+
+	// The inviter's permissions may have shrunk since the invitation was sent.
+	if err := u.policy.RequireGrantable(invitation.InvitedByID, invitation.GroupID, invitation.Role); err != nil {
+		return nil, errors.New("invitation is no longer valid")
+	}
+
+	if err := u.invitationRepo.AcceptInvitation(invitation, userID); err != nil {
+		return nil, errors.New("failed to accept invitation")
+	}
+
+	if !user.EmailVerified {
+		if err := u.userRepo.MarkEmailVerified(userID); err != nil {
+			return nil, errors.New("failed to verify email")
+		}
+	}
+
+	return invitation, nil
+}
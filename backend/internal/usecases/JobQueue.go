@@ -0,0 +1,175 @@
+package usecases
+
+import (
+	"band-manager-backend/internal/domain"
+	"band-manager-backend/internal/model"
+	"band-manager-backend/internal/repositories"
+	"band-manager-backend/internal/usecases/helpers"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+)
+
+// JobQueue enqueues background jobs run by the JobWorker. Failing to enqueue
+// a job is logged rather than failing the operation that caused it.
+type JobQueue struct {
+	jobRepo *repositories.JobRepository
+}
+
+func NewJobQueue() *JobQueue {
+	return &JobQueue{
+		jobRepo: repositories.NewJobRepository(),
+	}
+}
+
+// Enqueue adds a job with a JSON payload to the queue. A non-empty
+// idempotency key makes sure the job is enqueued at most once.
+func (q *JobQueue) Enqueue(jobType string, payload interface{}, idempotencyKey string) error {
+	return q.EnqueueAt(jobType, payload, idempotencyKey, time.Now())
+}
+
+// EnqueueAt adds a job that is not run before runAt.
+func (q *JobQueue) EnqueueAt(jobType string, payload interface{}, idempotencyKey string, runAt time.Time) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	job := &model.Job{
+		Type:        jobType,
+		Payload:     string(data),
+		MaxAttempts: helpers.DefaultJobMaxAttempts,
+		RunAt:       runAt,
+	}
+	if idempotencyKey != "" {
+		job.IdempotencyKey = &idempotencyKey
+	}
+	return q.jobRepo.CreateJob(job)
+}
+
+// enqueue adds a job and logs when that fails.
+func (q *JobQueue) enqueue(jobType string, payload interface{}, idempotencyKey string) {
+	if err := q.Enqueue(jobType, payload, idempotencyKey); err != nil {
+		log.Printf("Failed to enqueue %s job: %v", jobType, err)
+	}
+}
+
+// VerificationEmail enqueues sending the link of an email address verification token.
+func (q *JobQueue) VerificationEmail(tokenID uint) {
+	q.enqueue(helpers.JobTypeVerificationEmail, domain.TokenEmailJob{TokenID: tokenID},
+		fmt.Sprintf("verification-email:%d", tokenID))
+}
+
+// PasswordResetEmail enqueues sending the link of a password reset token.
+func (q *JobQueue) PasswordResetEmail(tokenID uint) {
+	q.enqueue(helpers.JobTypePasswordResetEmail, domain.TokenEmailJob{TokenID: tokenID},
+		fmt.Sprintf("password-reset-email:%d", tokenID))
+}
+
+// InvitationEmail enqueues sending a group invitation.
+func (q *JobQueue) InvitationEmail(invitationID uint) {
+	q.enqueue(helpers.JobTypeInvitationEmail, domain.InvitationEmailJob{InvitationID: invitationID},
+		fmt.Sprintf("invitation-email:%d", invitationID))
+}
+
+// JoinRequestEmail enqueues telling a user about the decision on their join request.
+func (q *JobQueue) JoinRequestEmail(requestID uint, approved bool) {
+	q.enqueue(helpers.JobTypeJoinRequestEmail, domain.JoinRequestEmailJob{RequestID: requestID, Approved: approved},
+		fmt.Sprintf("join-request-email:%d", requestID))
+}
+
+// EventEmails enqueues notifying every recipient about an event.
+func (q *JobQueue) EventEmails(eventID uint, recipients []*model.User) {
+	for _, recipient := range recipients {
+		payload := domain.EventEmailJob{EventID: eventID, UserID: recipient.ID}
+		q.enqueue(helpers.JobTypeEventEmail, payload, fmt.Sprintf("event-email:%d:%d", eventID, recipient.ID))
+	}
+}
+
+// EventReminder enqueues reminding the members assigned to an event of an
+// occurrence at the time given by the reminder. Each reminder is enqueued
+// once, however often it is scheduled.
+func (q *JobQueue) EventReminder(reminder domain.EventReminderJob) error {
+	runAt := reminder.OccurrenceDate.Add(-time.Duration(reminder.Minutes) * time.Minute)
+	return q.EnqueueAt(helpers.JobTypeEventReminder, reminder, eventReminderKey("event-reminder", reminder), runAt)
+}
+
+// EventReminderEmail enqueues sending a reminder to one member.
+func (q *JobQueue) EventReminderEmail(reminder domain.EventReminderJob, userID uint) error {
+	payload := domain.EventReminderEmailJob{EventReminderJob: reminder, UserID: userID}
+	return q.Enqueue(helpers.JobTypeEventReminderEmail, payload,
+		fmt.Sprintf("%s:%d", eventReminderKey("event-reminder-email", reminder), userID))
+}
+
+// eventReminderKey identifies a reminder of an occurrence.
+func eventReminderKey(prefix string, reminder domain.EventReminderJob) string {
+	return fmt.Sprintf("%s:%d:%d:%d", prefix, reminder.EventID, reminder.OccurrenceDate.Unix(), reminder.Minutes)
+}
+
+// EventChangeEmails enqueues telling every recipient that the time or place
+// of an event changed from those of previous.
+func (q *JobQueue) EventChangeEmails(eventID uint, recipients []*model.User, previous *model.Event) {
+	for _, recipient := range recipients {
+		payload := domain.EventChangeEmailJob{
+			EventID:          eventID,
+			UserID:           recipient.ID,
+			PreviousDate:     previous.Date,
+			PreviousEndDate:  previous.EndDate,
+			PreviousAllDay:   previous.AllDay,
+			PreviousLocation: previous.Location,
+		}
+		q.enqueue(helpers.JobTypeEventChangeEmail, payload, "")
+	}
+}
+
+// EventCancelEmails enqueues telling every recipient that an event, or with
+// series all occurrences of it from its date on, is cancelled.
+func (q *JobQueue) EventCancelEmails(event *model.Event, series bool, recipients []*model.User) {
+	for _, recipient := range recipients {
+		payload := domain.EventCancelEmailJob{
+			UserID:    recipient.ID,
+			GroupName: event.Group.Name,
+			Title:     event.Title,
+			Location:  event.Location,
+			Date:      event.Date,
+			EndDate:   event.EndDate,
+			AllDay:    event.AllDay,
+			TimeZone:  event.TimeZone,
+			Series:    series,
+		}
+		q.enqueue(helpers.JobTypeEventCancelEmail, payload, "")
+	}
+}
+
+// AnnouncementEmails enqueues sending an announcement to every recipient.
+func (q *JobQueue) AnnouncementEmails(announcementID uint, recipients []*model.User) {
+	for _, recipient := range recipients {
+		payload := domain.AnnouncementEmailJob{AnnouncementID: announcementID, UserID: recipient.ID}
+		q.enqueue(helpers.JobTypeAnnouncementEmail, payload, fmt.Sprintf("announcement-email:%d:%d", announcementID, recipient.ID))
+	}
+}
+
+// CalendarSync enqueues bringing the calendar copies of an event up to date.
+// Syncing is repeated after every change, so these jobs have no idempotency key.
+func (q *JobQueue) CalendarSync(eventID uint) {
+	q.enqueue(helpers.JobTypeCalendarSyncEvent, domain.CalendarSyncEventJob{EventID: eventID}, "")
+}
+
+// CalendarRemoval enqueues removing calendar copies of deleted events.
+func (q *JobQueue) CalendarRemoval(copies []*model.GoogleCalendarEvent) {
+	for _, synced := range copies {
+		payload := domain.CalendarRemoveCopyJob{
+			CopyID:     synced.ID,
+			EventID:    synced.EventID,
+			UserID:     synced.UserID,
+			CalendarID: synced.CalendarID,
+		}
+		q.enqueue(helpers.JobTypeCalendarRemoveCopy, payload, fmt.Sprintf("calendar-remove-copy:%d", synced.ID))
+	}
+}
+
+// CalendarSyncUpcoming enqueues copying the upcoming events of a user to a newly linked calendar.
+func (q *JobQueue) CalendarSyncUpcoming(userID uint) {
+	q.enqueue(helpers.JobTypeCalendarSyncUpcoming, domain.CalendarSyncUpcomingJob{UserID: userID}, "")
+}
@@ -0,0 +1,439 @@
+package usecases
+
+import (
+	"band-manager-backend/internal/domain"
+	"band-manager-backend/internal/model"
+	"band-manager-backend/internal/repositories"
+	"band-manager-backend/internal/services"
+	"band-manager-backend/internal/usecases/helpers"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// jobLockTimeout is how long a job may run before it is assumed that its
+	// worker stopped and the job is handed to another one.
+	jobLockTimeout = 15 * time.Minute
+	// jobMaintenanceInterval is how often stale and old jobs are cleaned up.
+	jobMaintenanceInterval = time.Minute
+	// succeededJobRetention is how long succeeded jobs are kept for inspection.
+	succeededJobRetention = 7 * 24 * time.Hour
+)
+
+// jobHandler runs a job with the given JSON payload.
+type jobHandler func(payload []byte) error
+
+// JobWorker runs queued background jobs with a pool of goroutines. Failed
+// jobs are retried with exponential backoff until they run out of attempts.
+// Jobs are claimed with row locks, so any number of instances can share the queue.
+type JobWorker struct {
+	jobRepo          *repositories.JobRepository
+	userRepo         *repositories.UserRepository
+	verifyRepo       *repositories.EmailVerificationRepository
+	resetRepo        *repositories.PasswordResetRepository
+	groupRepo        *repositories.GroupRepository
+	eventRepo        *repositories.EventRepository
+	invitationRepo   *repositories.InvitationRepository
+	joinRequestRepo  *repositories.JoinRequestRepository
+	announcementRepo *repositories.AnnouncementRepository
+	emailService     *services.EmailService
+	calendarSync     *CalendarSyncUsecase
+	jobs             *JobQueue
+	handlers         map[string]jobHandler
+	concurrency      int
+	pollInterval     time.Duration
+}
+
+func NewJobWorker(gcService *services.GoogleCalendarService, emailService *services.EmailService, concurrency int, pollInterval time.Duration) *JobWorker {
+	w := &JobWorker{
+		jobRepo:          repositories.NewJobRepository(),
+		userRepo:         repositories.NewUserRepository(),
+		verifyRepo:       repositories.NewEmailVerificationRepository(),
+		resetRepo:        repositories.NewPasswordResetRepository(),
+		groupRepo:        repositories.NewGroupRepository(),
+		eventRepo:        repositories.NewEventRepository(),
+		invitationRepo:   repositories.NewInvitationRepository(),
+		joinRequestRepo:  repositories.NewJoinRequestRepository(),
+		announcementRepo: repositories.NewAnnouncementRepository(),
+		emailService:     emailService,
+		calendarSync:     NewCalendarSyncUsecase(gcService),
+		jobs:             NewJobQueue(),
+		concurrency:      concurrency,
+		pollInterval:     pollInterval,
+	}
+
+	w.handlers = map[string]jobHandler{
+		helpers.JobTypeVerificationEmail:    decodeJob(w.sendVerificationEmail),
+		helpers.JobTypePasswordResetEmail:   decodeJob(w.sendPasswordResetEmail),
+		helpers.JobTypeInvitationEmail:      decodeJob(w.sendInvitationEmail),
+		helpers.JobTypeJoinRequestEmail:     decodeJob(w.sendJoinRequestEmail),
+		helpers.JobTypeEventEmail:           decodeJob(w.sendEventEmail),
+		helpers.JobTypeAnnouncementEmail:    decodeJob(w.sendAnnouncementEmail),
+		helpers.JobTypeEventReminderEmail:   decodeJob(w.sendEventReminderEmail),
+		helpers.JobTypeEventChangeEmail:     decodeJob(w.sendEventChangeEmail),
+		helpers.JobTypeEventCancelEmail:     decodeJob(w.sendEventCancelEmail),
+		helpers.JobTypeEventReminder:        decodeJob(w.remindOfEvent),
+		helpers.JobTypeCalendarSyncEvent:    decodeJob(w.syncCalendarEvent),
+		helpers.JobTypeCalendarRemoveCopy:   decodeJob(w.removeCalendarCopy),
+		helpers.JobTypeCalendarSyncUpcoming: decodeJob(w.syncUpcomingCalendarEvents),
+	}
+	return w
+}
+
+// Start runs the worker pool until the context is cancelled.
+func (w *JobWorker) Start(ctx context.Context) {
+	hostname, _ := os.Hostname()
+	for i := 0; i < w.concurrency; i++ {
+		go w.work(ctx, fmt.Sprintf("%s-%d/%d", hostname, os.Getpid(), i))
+	}
+	go w.maintain(ctx)
+}
+
+// work claims and runs jobs one at a time, waiting for new ones when the queue is empty.
+func (w *JobWorker) work(ctx context.Context, workerID string) {
+	for ctx.Err() == nil {
+		job, err := w.jobRepo.ClaimJob(workerID)
+		if err != nil {
+			log.Printf("Failed to claim job: %v", err)
+		}
+		if job == nil {
+			select {
+			case <-ctx.Done():
+			case <-time.After(w.pollInterval):
+			}
+			continue
+		}
+		w.run(job)
+	}
+}
+
+// run runs a claimed job and records its outcome.
+func (w *JobWorker) run(job *model.Job) {
+	handler, ok := w.handlers[job.Type]
+	if !ok {
+		w.record(w.jobRepo.KillJob(job.ID, job.LockedBy, "unknown job type"), job)
+		return
+	}
+
+	err := callJobHandler(handler, job)
+	switch {
+	case err == nil:
+		w.record(w.jobRepo.CompleteJob(job.ID, job.LockedBy), job)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		// The records the job was about are gone, so there is nothing left to do.
+		log.Printf("Skipping %s job %d: %v", job.Type, job.ID, err)
+		w.record(w.jobRepo.CompleteJob(job.ID, job.LockedBy), job)
+	case job.Attempts >= job.MaxAttempts:
+		log.Printf("%s job %d failed for the last time: %v", job.Type, job.ID, err)
+		w.record(w.jobRepo.KillJob(job.ID, job.LockedBy, err.Error()), job)
+	default:
+		log.Printf("%s job %d failed, retrying: %v", job.Type, job.ID, err)
+		w.record(w.jobRepo.RetryJobAt(job.ID, job.LockedBy, err.Error(), time.Now().Add(helpers.JobBackoff(job.Attempts))), job)
+	}
+}
+
+// record logs a failure to store the outcome of a job. The job is then
+// released once its lock times out. A job whose lock timed out while it ran
+// has already been released, so its outcome is dropped.
+func (w *JobWorker) record(err error, job *model.Job) {
+	if errors.Is(err, repositories.ErrJobLockLost) {
+		log.Printf("Lost the lock on %s job %d, dropping its outcome", job.Type, job.ID)
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to record outcome of job %d: %v", job.ID, err)
+	}
+}
+
+// maintain periodically releases jobs of stopped workers and removes old
+// succeeded jobs. It runs right away, so that jobs interrupted by a restart
+// are picked up again.
+func (w *JobWorker) maintain(ctx context.Context) {
+	ticker := time.NewTicker(jobMaintenanceInterval)
+	defer ticker.Stop()
+
+	for {
+		now := time.Now()
+		if err := w.jobRepo.ReleaseStaleJobs(now.Add(-jobLockTimeout)); err != nil {
+			log.Printf("Failed to release stale jobs: %v", err)
+		}
+		if err := w.jobRepo.DeleteSucceededJobs(now.Add(-succeededJobRetention)); err != nil {
+			log.Printf("Failed to delete succeeded jobs: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// callJobHandler runs a handler, turning a panic into an error.
+func callJobHandler(handler jobHandler, job *model.Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return handler([]byte(job.Payload))
+}
+
+// decodeJob adapts a handler of a typed payload to a jobHandler.
+func decodeJob[T any](handle func(T) error) jobHandler {
+	return func(payload []byte) error {
+		var p T
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return fmt.Errorf("invalid payload: %v", err)
+		}
+		return handle(p)
+	}
+}
+
+// sendVerificationEmail issues the token of a verification token record and
+// emails its link. Tokens that were used or expired meanwhile are not sent.
+func (w *JobWorker) sendVerificationEmail(p domain.TokenEmailJob) error {
+	verificationToken, err := w.verifyRepo.GetTokenByID(p.TokenID)
+	if err != nil {
+		return err
+	}
+	user, err := w.userRepo.GetUserByID(verificationToken.UserID)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerified {
+		return nil
+	}
+
+	token, err := helpers.GenerateToken(32)
+	if err != nil {
+		return err
+	}
+	if valid, err := w.verifyRepo.RenewTokenHash(verificationToken.ID, helpers.HashToken(token)); err != nil || !valid {
+		return err
+	}
+	return w.emailService.SendVerificationEmail(user, token)
+}
+
+// sendPasswordResetEmail issues the token of a password reset token record
+// and emails its link. Tokens that were used or expired meanwhile are not sent.
+func (w *JobWorker) sendPasswordResetEmail(p domain.TokenEmailJob) error {
+	resetToken, err := w.resetRepo.GetTokenByID(p.TokenID)
+	if err != nil {
+		return err
+	}
+	user, err := w.userRepo.GetUserByID(resetToken.UserID)
+	if err != nil {
+		return err
+	}
+
+	token, err := helpers.GenerateToken(32)
+	if err != nil {
+		return err
+	}
+	if valid, err := w.resetRepo.RenewTokenHash(resetToken.ID, helpers.HashToken(token)); err != nil || !valid {
+		return err
+	}
+	return w.emailService.SendPasswordResetEmail(user, token)
+}
+
+// sendInvitationEmail issues the token of an invitation and emails it.
+// Invitations that cannot be accepted anymore are not sent.
+func (w *JobWorker) sendInvitationEmail(p domain.InvitationEmailJob) error {
+	invitation, err := w.invitationRepo.GetInvitationByID(p.InvitationID)
+	if err != nil {
+		return err
+	}
+	if invitation.AcceptedAt != nil || invitation.RevokedAt != nil || invitation.ExpiresAt.Before(time.Now()) {
+		return nil
+	}
+
+	group, err := w.groupRepo.GetGroupByID(invitation.GroupID)
+	if err != nil {
+		return err
+	}
+
+	token, err := helpers.GenerateToken(32)
+	if err != nil {
+		return err
+	}
+	if valid, err := w.invitationRepo.RenewTokenHash(invitation.ID, helpers.HashToken(token)); err != nil || !valid {
+		return err
+	}
+	return w.emailService.SendInvitationEmail(invitation, group.Name, token)
+}
+
+func (w *JobWorker) sendJoinRequestEmail(p domain.JoinRequestEmailJob) error {
+	request, err := w.joinRequestRepo.GetRequestByID(p.RequestID)
+	if err != nil {
+		return err
+	}
+	return w.emailService.SendJoinRequestDecisionEmail(&request.User, request.Group.Name, p.Approved)
+}
+
+func (w *JobWorker) sendEventEmail(p domain.EventEmailJob) error {
+	event, err := w.eventRepo.GetEventByID(p.EventID)
+	if err != nil {
+		return err
+	}
+	user, err := w.userRepo.GetUserByID(p.UserID)
+	if err != nil {
+		return err
+	}
+	responseToken, err := w.issueResponseToken(event.ID, user.ID)
+	if err != nil {
+		return err
+	}
+	return w.emailService.SendEventEmail(event, user, responseToken)
+}
+
+// issueResponseToken creates the respond-link token of a user assigned to an
+// event. Users who are not assigned to it get no token.
+func (w *JobWorker) issueResponseToken(eventID, userID uint) (string, error) {
+	if _, err := w.eventRepo.GetAttendee(eventID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	token, err := helpers.GenerateToken(32)
+	if err != nil {
+		return "", err
+	}
+	if err := w.eventRepo.SetResponseToken(eventID, userID, helpers.HashToken(token)); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// remindOfEvent enqueues a reminder email for every member assigned to the
+// event who did not decline it.
+func (w *JobWorker) remindOfEvent(p domain.EventReminderJob) error {
+	event, err := w.eventRepo.GetEventByID(p.EventID)
+	if err != nil {
+		return err
+	}
+	if !reminderDue(event, p, time.Now()) {
+		return nil
+	}
+
+	attendees, err := w.eventRepo.GetEventAttendees(event.ID)
+	if err != nil {
+		return err
+	}
+	for _, attendee := range attendees {
+		if attendee.RSVPStatus == helpers.RSVPNo {
+			continue
+		}
+		if err := w.jobs.EventReminderEmail(p, attendee.UserID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *JobWorker) sendEventReminderEmail(p domain.EventReminderEmailJob) error {
+	event, err := w.eventRepo.GetEventByID(p.EventID)
+	if err != nil {
+		return err
+	}
+	if !reminderDue(event, p.EventReminderJob, time.Now()) {
+		return nil
+	}
+
+	user, err := w.userRepo.GetUserByID(p.UserID)
+	if err != nil {
+		return err
+	}
+	if !user.EventReminders || !user.EmailVerified || user.Disabled {
+		return nil
+	}
+
+	attendee, err := w.eventRepo.GetAttendee(event.ID, user.ID)
+	if err != nil {
+		return err
+	}
+	if attendee.RSVPStatus == helpers.RSVPNo {
+		return nil
+	}
+
+	occurrence := *event
+	if event.RecurrenceRule != "" {
+		occurrence.Date = p.OccurrenceDate
+		occurrence.EndDate = helpers.OccurrenceEnd(event, p.OccurrenceDate)
+	}
+	return w.emailService.SendEventReminderEmail(&occurrence, user)
+}
+
+func (w *JobWorker) sendEventChangeEmail(p domain.EventChangeEmailJob) error {
+	event, err := w.eventRepo.GetEventByID(p.EventID)
+	if err != nil {
+		return err
+	}
+	user, err := w.userRepo.GetUserByID(p.UserID)
+	if err != nil {
+		return err
+	}
+
+	previous := *event
+	previous.Date = p.PreviousDate
+	previous.EndDate = p.PreviousEndDate
+	previous.AllDay = p.PreviousAllDay
+	previous.Location = p.PreviousLocation
+	return w.emailService.SendEventChangeEmail(event, &previous, user)
+}
+
+func (w *JobWorker) sendEventCancelEmail(p domain.EventCancelEmailJob) error {
+	user, err := w.userRepo.GetUserByID(p.UserID)
+	if err != nil {
+		return err
+	}
+
+	event := &model.Event{
+		Title:    p.Title,
+		Location: p.Location,
+		Date:     p.Date,
+		EndDate:  p.EndDate,
+		AllDay:   p.AllDay,
+		TimeZone: p.TimeZone,
+		Group:    model.Group{Name: p.GroupName},
+	}
+	return w.emailService.SendEventCancelEmail(event, p.Series, user)
+}
+
+func (w *JobWorker) sendAnnouncementEmail(p domain.AnnouncementEmailJob) error {
+	announcement, err := w.announcementRepo.GetByID(p.AnnouncementID)
+	if err != nil {
+		return err
+	}
+	user, err := w.userRepo.GetUserByID(p.UserID)
+	if err != nil {
+		return err
+	}
+	return w.emailService.SendAnnouncementEmail(announcement, user)
+}
+
+func (w *JobWorker) syncCalendarEvent(p domain.CalendarSyncEventJob) error {
+	return w.calendarSync.SyncEvent(p.EventID)
+}
+
+func (w *JobWorker) removeCalendarCopy(p domain.CalendarRemoveCopyJob) error {
+	return w.calendarSync.RemoveCopy(&model.GoogleCalendarEvent{
+		ID:         p.CopyID,
+		EventID:    p.EventID,
+		UserID:     p.UserID,
+		CalendarID: p.CalendarID,
+	})
+}
+
+func (w *JobWorker) syncUpcomingCalendarEvents(p domain.CalendarSyncUpcomingJob) error {
+	return w.calendarSync.SyncUpcomingEvents(p.UserID)
+}
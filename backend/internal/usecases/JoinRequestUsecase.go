@@ -0,0 +1,149 @@
+package usecases
+
+import (
+	"band-manager-backend/internal/model"
+	"band-manager-backend/internal/repositories"
+	"band-manager-backend/internal/usecases/helpers"
+	"errors"
+	"time"
+)
+
+// JoinRequestUsecase handles reviewing requests to join groups that require approval.
+type JoinRequestUsecase struct {
+	joinRequestRepo *repositories.JoinRequestRepository
+	groupRepo       *repositories.GroupRepository
+	subgroupRepo    *repositories.SubgroupRepository
+	jobs            *JobQueue
+	policy          *Policy
+}
+
+// JoinRequestInfo holds the details of a pending join request.
+type JoinRequestInfo struct {
+	ID        uint      `json:"id"`
+	UserID    uint      `json:"user_id"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func NewJoinRequestUsecase() *JoinRequestUsecase {
+	return &JoinRequestUsecase{
+		joinRequestRepo: repositories.NewJoinRequestRepository(),
+		groupRepo:       repositories.NewGroupRepository(),
+		subgroupRepo:    repositories.NewSubgroupRepository(),
+		jobs:            NewJobQueue(),
+		policy:          NewPolicy(),
+	}
+}
+
+// GetPendingRequests lists the pending join requests of a group.
+func (u *JoinRequestUsecase) GetPendingRequests(groupID, requestingUserID uint) ([]JoinRequestInfo, error) {
+	if err := u.policy.Require(requestingUserID, groupID, helpers.PermJoinRequestsManage); err != nil {
+		return nil, err
+	}
+
+	requests, err := u.joinRequestRepo.GetGroupPendingRequests(groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]JoinRequestInfo, 0, len(requests))
+	for _, request := range requests {
+		infos = append(infos, JoinRequestInfo{
+			ID:        request.ID,
+			UserID:    request.UserID,
+			FirstName: request.User.FirstName,
+			LastName:  request.User.LastName,
+			Email:     request.User.Email,
+			CreatedAt: request.CreatedAt,
+		})
+	}
+	return infos, nil
+}
+
+// ApproveRequest adds the requester to the group with the chosen role and
+// subgroups. The approver must hold every permission the role grants.
+func (u *JoinRequestUsecase) ApproveRequest(requestID uint, role string, subgroupIDs []uint, requestingUserID uint) error {
+	request, err := u.getPendingRequest(requestID, requestingUserID)
+	if err != nil {
+		return err
+	}
+
+	if role == "" {
+		role = helpers.RoleMember
+	}
+	if !u.policy.RoleExists(request.GroupID, role) {
+		return errors.New("invalid role")
+	}
+	if role == helpers.RoleManager {
+		requesterRole, err := u.groupRepo.GetUserRole(requestingUserID, request.GroupID)
+		if err != nil || requesterRole != helpers.RoleManager {
+			return errors.New("only managers can act on managers")
+		}
+	}
+	if err := u.policy.RequireGrantable(requestingUserID, request.GroupID, role); err != nil {
+		return err
+	}
+
+	seen := make(map[uint]bool, len(subgroupIDs))
+	unique := make([]uint, 0, len(subgroupIDs))
+	for _, subgroupID := range subgroupIDs {
+		if seen[subgroupID] {
+			continue
+		}
+		seen[subgroupID] = true
+
+		subgroup, err := u.subgroupRepo.GetSubgroupByID(subgroupID)
+		if err != nil {
+			return err
+		}
+		if subgroup.GroupID != request.GroupID {
+			return errors.New("subgroup does not belong to the group")
+		}
+		unique = append(unique, subgroupID)
+	}
+	subgroupIDs = unique
+
+	if err := u.joinRequestRepo.ApproveRequest(request, role, subgroupIDs, requestingUserID); err != nil {
+		return errors.New("failed to approve join request")
+	}
+
+	u.jobs.JoinRequestEmail(request.ID, true)
+
+	return nil
+}
+
+// RejectRequest declines a pending join request.
+func (u *JoinRequestUsecase) RejectRequest(requestID uint, requestingUserID uint) error {
+	request, err := u.getPendingRequest(requestID, requestingUserID)
+	if err != nil {
+		return err
+	}
+
+	if err := u.joinRequestRepo.RejectRequest(request, requestingUserID); err != nil {
+		return errors.New("failed to reject join request")
+	}
+
+	u.jobs.JoinRequestEmail(request.ID, false)
+
+	return nil
+}
+
+// getPendingRequest loads a request the user may decide on.
+func (u *JoinRequestUsecase) getPendingRequest(requestID, requestingUserID uint) (*model.JoinRequest, error) {
+	request, err := u.joinRequestRepo.GetRequestByID(requestID)
+	if err != nil {
+		return nil, errors.New("join request not found")
+	}
+
+	if err := u.policy.Require(requestingUserID, request.GroupID, helpers.PermJoinRequestsManage); err != nil {
+		return nil, err
+	}
+
+	if request.Status != repositories.JoinRequestPending {
+		return nil, errors.New("join request already decided")
+	}
+
+	return request, nil
+}
@@ -0,0 +1,126 @@
+package usecases
+
+import (
+	"band-manager-backend/internal/repositories"
+	"band-manager-backend/internal/usecases/helpers"
+	"errors"
+)
+
+var (
+	// ErrAccessDenied is returned when the user is not a member of the group.
+	ErrAccessDenied = errors.New("access denied")
+	// ErrInsufficientPermissions is returned when the user's role lacks a permission.
+	ErrInsufficientPermissions = errors.New("insufficient permissions")
+	// ErrRoleNotGrantable is returned when a role grants permissions the user lacks.
+	ErrRoleNotGrantable = errors.New("cannot grant a role with permissions you do not have")
+)
+
+// Policy decides which actions group members may perform, based on built-in
+// roles and the custom roles defined by each group.
+type Policy struct {
+	groupRepo    *repositories.GroupRepository
+	roleRepo     *repositories.RoleRepository
+	subgroupRepo *repositories.SubgroupRepository
+}
+
+func NewPolicy() *Policy {
+	return &Policy{
+		groupRepo:    repositories.NewGroupRepository(),
+		roleRepo:     repositories.NewRoleRepository(),
+		subgroupRepo: repositories.NewSubgroupRepository(),
+	}
+}
+
+// RolePermissions returns the permissions granted by a role within a group.
+func (p *Policy) RolePermissions(groupID uint, role string) []helpers.Permission {
+	if helpers.IsBuiltInRole(role) {
+		return helpers.ResolveRolePermissions(role, nil)
+	}
+
+	customRole, err := p.roleRepo.GetGroupRoleByName(groupID, role)
+	if err != nil {
+		return nil
+	}
+	return helpers.ResolveRolePermissions(role, customRole)
+}
+
+// Can reports whether the user may perform the action in the group.
+func (p *Policy) Can(userID, groupID uint, permission helpers.Permission) bool {
+	return p.Require(userID, groupID, permission) == nil
+}
+
+// Require returns ErrAccessDenied if the user is not in the group and
+// ErrInsufficientPermissions if their role does not grant the permission.
+func (p *Policy) Require(userID, groupID uint, permission helpers.Permission) error {
+	role, err := p.groupRepo.GetUserRole(userID, groupID)
+	if err != nil {
+		return ErrAccessDenied
+	}
+
+	if !helpers.HasPermission(p.RolePermissions(groupID, role), permission) {
+		return ErrInsufficientPermissions
+	}
+
+	return nil
+}
+
+// RequireGrantable returns ErrRoleNotGrantable unless the user holds every
+// permission the role grants in the group, so that nobody gives a member,
+// or an account of their own, more rights than they have.
+func (p *Policy) RequireGrantable(userID, groupID uint, role string) error {
+	userRole, err := p.groupRepo.GetUserRole(userID, groupID)
+	if err != nil {
+		return ErrAccessDenied
+	}
+
+	held := p.RolePermissions(groupID, userRole)
+	if len(helpers.MissingPermissions(held, p.RolePermissions(groupID, role))) > 0 {
+		return ErrRoleNotGrantable
+	}
+	return nil
+}
+
+// RequireInSubgroups behaves like Require, but also lets subgroup leaders
+// perform leader actions when every listed subgroup is one they lead.
+// Callers must check that the subgroups belong to the group.
+func (p *Policy) RequireInSubgroups(userID, groupID uint, subgroupIDs []uint, permission helpers.Permission) error {
+	err := p.Require(userID, groupID, permission)
+	if err != ErrInsufficientPermissions {
+		return err
+	}
+
+	if len(subgroupIDs) == 0 || !helpers.HasPermission(helpers.SubgroupLeaderPermissions, permission) {
+		return err
+	}
+
+	for _, subgroupID := range subgroupIDs {
+		if !p.IsSubgroupLeader(userID, subgroupID) {
+			return ErrInsufficientPermissions
+		}
+	}
+
+	return nil
+}
+
+// IsSubgroupLeader reports whether the user leads the subgroup.
+func (p *Policy) IsSubgroupLeader(userID, subgroupID uint) bool {
+	role, err := p.subgroupRepo.GetMemberRole(subgroupID, userID)
+	return err == nil && role == helpers.SubgroupRoleLeader
+}
+
+// RequireMember returns ErrAccessDenied if the user is not in the group.
+func (p *Policy) RequireMember(userID, groupID uint) error {
+	if _, err := p.groupRepo.GetUserRole(userID, groupID); err != nil {
+		return ErrAccessDenied
+	}
+	return nil
+}
+
+// RoleExists reports whether the role is built in or defined by the group.
+func (p *Policy) RoleExists(groupID uint, role string) bool {
+	if helpers.IsBuiltInRole(role) {
+		return true
+	}
+	_, err := p.roleRepo.GetGroupRoleByName(groupID, role)
+	return err == nil
+}
@@ -0,0 +1,140 @@
+package usecases
+
+import (
+	"band-manager-backend/internal/domain"
+	"band-manager-backend/internal/model"
+	"band-manager-backend/internal/repositories"
+	"band-manager-backend/internal/usecases/helpers"
+	"context"
+	"log"
+	"time"
+)
+
+const (
+	// reminderScanInterval is how often reminders of upcoming events are scheduled.
+	reminderScanInterval = 10 * time.Minute
+	// reminderGracePeriod is how late a reminder is still sent, for example
+	// when no instance was running at its time.
+	reminderGracePeriod = time.Hour
+)
+
+// ReminderScheduler enqueues event reminders as jobs due at the time they are
+// to be sent. Reminders are identified by event, occurrence and offset, so
+// scheduling them repeatedly, from any number of instances, enqueues each
+// one once. Reminders of events that were moved or cancelled are skipped
+// when they run.
+type ReminderScheduler struct {
+	eventRepo *repositories.EventRepository
+	jobs      *JobQueue
+}
+
+func NewReminderScheduler() *ReminderScheduler {
+	return &ReminderScheduler{
+		eventRepo: repositories.NewEventRepository(),
+		jobs:      NewJobQueue(),
+	}
+}
+
+// Start periodically schedules reminders of upcoming events until the
+// context is cancelled. It runs right away, so that reminders missed while
+// no instance was running are sent.
+func (s *ReminderScheduler) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(reminderScanInterval)
+		defer ticker.Stop()
+
+		for {
+			if err := s.ScheduleUpcoming(time.Now()); err != nil {
+				log.Printf("Failed to schedule event reminders: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// ScheduleUpcoming schedules the reminders of every occurrence starting
+// within the longest reminder offset from now.
+func (s *ReminderScheduler) ScheduleUpcoming(now time.Time) error {
+	to := now.Add(helpers.MaxReminderMinutes * time.Minute)
+	events, err := s.eventRepo.GetEventsInRange(now, to)
+	if err != nil {
+		return err
+	}
+
+	for _, occurrence := range expandOccurrences(events, &now, &to) {
+		s.scheduleOccurrence(occurrence, now)
+	}
+	return nil
+}
+
+// ScheduleEvent schedules the reminders of an event that was created or
+// changed, without waiting for the next scan.
+func (s *ReminderScheduler) ScheduleEvent(eventID uint) {
+	event, err := s.eventRepo.GetEventByID(eventID)
+	if err != nil {
+		log.Printf("Failed to schedule reminders of event %d: %v", eventID, err)
+		return
+	}
+
+	now := time.Now()
+	to := now.Add(helpers.MaxReminderMinutes * time.Minute)
+	for _, occurrence := range expandOccurrences([]*model.Event{event}, &now, &to) {
+		s.scheduleOccurrence(occurrence, now)
+	}
+}
+
+// scheduleOccurrence enqueues the reminders of an occurrence that has not
+// started yet, unless their time passed more than the grace period ago.
+func (s *ReminderScheduler) scheduleOccurrence(occurrence *model.Event, now time.Time) {
+	if !occurrence.Date.After(now) {
+		return
+	}
+
+	for _, minutes := range helpers.EventReminderMinutes(occurrence) {
+		reminder := domain.EventReminderJob{
+			EventID:        occurrence.ID,
+			OccurrenceDate: occurrence.Date,
+			Minutes:        minutes,
+		}
+		remindAt := occurrence.Date.Add(-time.Duration(minutes) * time.Minute)
+		if remindAt.Before(now.Add(-reminderGracePeriod)) {
+			continue
+		}
+		if err := s.jobs.EventReminder(reminder); err != nil {
+			log.Printf("Failed to schedule reminder of event %d: %v", occurrence.ID, err)
+		}
+	}
+}
+
+// reminderDue reports whether a reminder still applies to the event: the
+// occurrence has not started and is still scheduled at the same time, and
+// the event is still to be reminded of at that offset.
+func reminderDue(event *model.Event, reminder domain.EventReminderJob, now time.Time) bool {
+	if !reminder.OccurrenceDate.After(now) {
+		return false
+	}
+
+	offsetKept := false
+	for _, minutes := range helpers.EventReminderMinutes(event) {
+		if minutes == reminder.Minutes {
+			offsetKept = true
+		}
+	}
+	if !offsetKept {
+		return false
+	}
+
+	if event.RecurrenceRule == "" {
+		return event.Date.Equal(reminder.OccurrenceDate)
+	}
+	rule, err := helpers.ParseRecurrenceRule(event.RecurrenceRule)
+	if err != nil {
+		return false
+	}
+	return rule.IsOccurrence(seriesStart(event), reminder.OccurrenceDate) && !isException(event, reminder.OccurrenceDate)
+}
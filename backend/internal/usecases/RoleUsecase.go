@@ -0,0 +1,167 @@
+package usecases
+
+import (
+	"band-manager-backend/internal/model"
+	"band-manager-backend/internal/repositories"
+	"band-manager-backend/internal/usecases/helpers"
+	"errors"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// RoleUsecase manages the built-in and custom roles of a group.
+type RoleUsecase struct {
+	roleRepo *repositories.RoleRepository
+	policy   *Policy
+}
+
+// RoleInfo describes a role and the permissions it grants.
+type RoleInfo struct {
+	ID          uint                 `json:"id,omitempty"`
+	Name        string               `json:"name"`
+	BuiltIn     bool                 `json:"built_in"`
+	Permissions []helpers.Permission `json:"permissions"`
+}
+
+func NewRoleUsecase() *RoleUsecase {
+	return &RoleUsecase{
+		roleRepo: repositories.NewRoleRepository(),
+		policy:   NewPolicy(),
+	}
+}
+
+// GetGroupRoles lists the built-in roles followed by the group's custom roles.
+func (u *RoleUsecase) GetGroupRoles(groupID, userID uint) ([]RoleInfo, error) {
+	if err := u.policy.RequireMember(userID, groupID); err != nil {
+		return nil, err
+	}
+
+	roles := []RoleInfo{}
+	for _, name := range []string{helpers.RoleManager, helpers.RoleModerator, helpers.RoleMember} {
+		roles = append(roles, RoleInfo{
+			Name:        name,
+			BuiltIn:     true,
+			Permissions: helpers.BuiltInRolePermissions[name],
+		})
+	}
+
+	customRoles, err := u.roleRepo.GetGroupRoles(groupID)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range customRoles {
+		roles = append(roles, RoleInfo{
+			ID:          role.ID,
+			Name:        role.Name,
+			Permissions: u.policy.RolePermissions(groupID, role.Name),
+		})
+	}
+
+	return roles, nil
+}
+
+// CreateRole defines a new custom role in the group.
+func (u *RoleUsecase) CreateRole(groupID uint, name string, permissions []helpers.Permission, userID uint) (*model.CustomRole, error) {
+	if err := u.policy.Require(userID, groupID, helpers.PermRolesManage); err != nil {
+		return nil, err
+	}
+
+	name, err := u.validateRole(groupID, name, permissions, 0, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &model.CustomRole{
+		GroupID:     groupID,
+		Name:        name,
+		Permissions: toStringArray(permissions),
+	}
+	if err := u.roleRepo.CreateRole(role); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// UpdateRole renames a custom role or changes its permissions.
+func (u *RoleUsecase) UpdateRole(roleID uint, name string, permissions []helpers.Permission, userID uint) (*model.CustomRole, error) {
+	role, err := u.roleRepo.GetRoleByID(roleID)
+	if err != nil {
+		return nil, errors.New("role not found")
+	}
+
+	if err := u.policy.Require(userID, role.GroupID, helpers.PermRolesManage); err != nil {
+		return nil, err
+	}
+
+	name, err = u.validateRole(role.GroupID, name, permissions, role.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	oldName := role.Name
+	role.Name = name
+	role.Permissions = toStringArray(permissions)
+	if err := u.roleRepo.UpdateRole(role, oldName); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// DeleteRole removes a custom role that is no longer held by any member.
+func (u *RoleUsecase) DeleteRole(roleID uint, userID uint) error {
+	role, err := u.roleRepo.GetRoleByID(roleID)
+	if err != nil {
+		return errors.New("role not found")
+	}
+
+	if err := u.policy.Require(userID, role.GroupID, helpers.PermRolesManage); err != nil {
+		return err
+	}
+
+	count, err := u.roleRepo.CountRoleMembers(role.GroupID, role.Name)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("role is still assigned to members")
+	}
+
+	return u.roleRepo.DeleteRole(role.ID)
+}
+
+// validateRole checks the name and permissions of a custom role and returns
+// the trimmed name. Members can only grant permissions they hold themselves,
+// so that nobody gives themselves more rights through a role.
+func (u *RoleUsecase) validateRole(groupID uint, name string, permissions []helpers.Permission, roleID, userID uint) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errors.New("role name is required")
+	}
+	if helpers.IsBuiltInRole(name) {
+		return "", errors.New("role name is reserved")
+	}
+
+	if existing, err := u.roleRepo.GetGroupRoleByName(groupID, name); err == nil && existing.ID != roleID {
+		return "", errors.New("role already exists")
+	}
+
+	for _, permission := range permissions {
+		if !helpers.IsValidPermission(permission) {
+			return "", errors.New("unknown permission: " + string(permission))
+		}
+		if !u.policy.Can(userID, groupID, permission) {
+			return "", errors.New("cannot grant a permission you do not have: " + string(permission))
+		}
+	}
+
+	return name, nil
+}
+
+func toStringArray(permissions []helpers.Permission) pq.StringArray {
+	values := make(pq.StringArray, 0, len(permissions))
+	for _, permission := range permissions {
+		values = append(values, string(permission))
+	}
+	return values
+}
@@ -0,0 +1,272 @@
+package usecases
+
+import (
+	"band-manager-backend/internal/domain"
+	"band-manager-backend/internal/model"
+	"band-manager-backend/internal/repositories"
+	"band-manager-backend/internal/usecases/helpers"
+	"errors"
+	"fmt"
+)
+
+const (
+	maxSetlistEntryDuration = 24 * 60 * 60
+	maxSetlistTempo         = 400
+)
+
+// SetlistUsecase handles the running order of events.
+type SetlistUsecase struct {
+	setlistRepo *repositories.SetlistRepository
+	eventRepo   *repositories.EventRepository
+	trackRepo   *repositories.TrackRepository
+	policy      *Policy
+}
+
+func NewSetlistUsecase() *SetlistUsecase {
+	return &SetlistUsecase{
+		setlistRepo: repositories.NewSetlistRepository(),
+		eventRepo:   repositories.NewEventRepository(),
+		trackRepo:   repositories.NewTrackRepository(),
+		policy:      NewPolicy(),
+	}
+}
+
+// GetSetlist returns the setlist of an event with planned start times.
+func (u *SetlistUsecase) GetSetlist(eventID, userID uint) (*domain.Setlist, error) {
+	event, err := u.eventRepo.GetEventByID(eventID)
+	if err != nil {
+		return nil, errors.New("event not found")
+	}
+
+	if err := u.policy.RequireMember(userID, event.GroupID); err != nil {
+		return nil, err
+	}
+
+	return u.buildSetlist(event)
+}
+
+// AddEntry inserts a track or a break at the given position of the setlist,
+// or appends it when position is 0. Tracks added to the setlist are also
+// assigned to the event.
+func (u *SetlistUsecase) AddEntry(eventID uint, details domain.SetlistEntryDetails, position int, userID uint) (*model.Performance, error) {
+	event, err := u.eventRepo.GetEventByID(eventID)
+	if err != nil {
+		return nil, errors.New("event not found")
+	}
+
+	if err := u.policy.Require(userID, event.GroupID, helpers.PermEventUpdate); err != nil {
+		return nil, err
+	}
+
+	if err := u.validateEntryDetails(event, details); err != nil {
+		return nil, err
+	}
+
+	entry := &model.Performance{
+		EventID:  event.ID,
+		Position: position,
+	}
+	applyEntryDetails(entry, details)
+	if err := u.setlistRepo.CreateEntry(entry); err != nil {
+		return nil, errors.New("failed to add setlist entry")
+	}
+
+	if entry.TrackID != nil {
+		if err := u.eventRepo.AddTracksToEvent(event.ID, []uint{*entry.TrackID}); err != nil {
+			return nil, err
+		}
+	}
+
+	return entry, nil
+}
+
+// UpdateEntry changes the details of a setlist entry.
+func (u *SetlistUsecase) UpdateEntry(entryID uint, details domain.SetlistEntryDetails, userID uint) (*model.Performance, error) {
+	entry, event, err := u.getEditableEntry(entryID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.validateEntryDetails(event, details); err != nil {
+		return nil, err
+	}
+
+	applyEntryDetails(entry, details)
+	if err := u.setlistRepo.UpdateEntry(entry); err != nil {
+		return nil, errors.New("failed to update setlist entry")
+	}
+
+	if entry.TrackID != nil {
+		if err := u.eventRepo.AddTracksToEvent(event.ID, []uint{*entry.TrackID}); err != nil {
+			return nil, err
+		}
+	}
+
+	return entry, nil
+}
+
+// DeleteEntry removes an entry from the setlist.
+func (u *SetlistUsecase) DeleteEntry(entryID, userID uint) error {
+	entry, _, err := u.getEditableEntry(entryID, userID)
+	if err != nil {
+		return err
+	}
+
+	return u.setlistRepo.DeleteEntry(entry)
+}
+
+// MoveEntry moves an entry to a new position in the setlist.
+func (u *SetlistUsecase) MoveEntry(entryID uint, position int, userID uint) (*domain.Setlist, error) {
+	entry, event, err := u.getEditableEntry(entryID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := u.setlistRepo.GetSetlist(event.ID)
+	if err != nil {
+		return nil, err
+	}
+	if position < 1 || position > len(entries) {
+		return nil, fmt.Errorf("position must be between 1 and %d", len(entries))
+	}
+
+	if position != entry.Position {
+		if err := u.setlistRepo.MoveEntry(entry, position); err != nil {
+			return nil, errors.New("failed to move setlist entry")
+		}
+	}
+
+	return u.buildSetlist(event)
+}
+
+// ReorderSetlist puts the entries of the setlist in the given order. Every
+// entry of the setlist must be listed exactly once.
+func (u *SetlistUsecase) ReorderSetlist(eventID uint, entryIDs []uint, userID uint) (*domain.Setlist, error) {
+	event, err := u.eventRepo.GetEventByID(eventID)
+	if err != nil {
+		return nil, errors.New("event not found")
+	}
+
+	if err := u.policy.Require(userID, event.GroupID, helpers.PermEventUpdate); err != nil {
+		return nil, err
+	}
+
+	entries, err := u.setlistRepo.GetSetlist(event.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := make(map[uint]bool, len(entries))
+	for _, entry := range entries {
+		remaining[entry.ID] = true
+	}
+	for _, entryID := range entryIDs {
+		if !remaining[entryID] {
+			return nil, errors.New("entry IDs must list every setlist entry exactly once")
+		}
+		delete(remaining, entryID)
+	}
+	if len(remaining) > 0 {
+		return nil, errors.New("entry IDs must list every setlist entry exactly once")
+	}
+
+	if err := u.setlistRepo.SetOrder(event.ID, entryIDs); err != nil {
+		return nil, errors.New("failed to reorder setlist")
+	}
+
+	return u.buildSetlist(event)
+}
+
+// ExportSetlist renders the setlist of an event in the given format and
+// returns it with its content type and a file name.
+func (u *SetlistUsecase) ExportSetlist(eventID uint, format string, userID uint) ([]byte, string, string, error) {
+	event, err := u.eventRepo.GetEventByID(eventID)
+	if err != nil {
+		return nil, "", "", errors.New("event not found")
+	}
+
+	if err := u.policy.RequireMember(userID, event.GroupID); err != nil {
+		return nil, "", "", err
+	}
+
+	setlist, err := u.buildSetlist(event)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	filename := fmt.Sprintf("setlist-%d.%s", event.ID, format)
+	switch format {
+	case helpers.SetlistFormatText:
+		return []byte(helpers.SetlistText(event, *setlist)), "text/plain; charset=utf-8", filename, nil
+	case helpers.SetlistFormatCSV:
+		data, err := helpers.SetlistCSV(event, *setlist)
+		if err != nil {
+			return nil, "", "", err
+		}
+		return data, "text/csv; charset=utf-8", filename, nil
+	default:
+		return nil, "", "", errors.New("unsupported export format")
+	}
+}
+
+// buildSetlist loads the setlist of an event and plans it.
+func (u *SetlistUsecase) buildSetlist(event *model.Event) (*domain.Setlist, error) {
+	entries, err := u.setlistRepo.GetSetlist(event.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	setlist := helpers.BuildSetlist(event, entries)
+	return &setlist, nil
+}
+
+// getEditableEntry loads a setlist entry with its event if the user may edit the event.
+func (u *SetlistUsecase) getEditableEntry(entryID, userID uint) (*model.Performance, *model.Event, error) {
+	entry, err := u.setlistRepo.GetEntryByID(entryID)
+	if err != nil {
+		return nil, nil, errors.New("setlist entry not found")
+	}
+
+	event, err := u.eventRepo.GetEventByID(entry.EventID)
+	if err != nil {
+		return nil, nil, errors.New("event not found")
+	}
+
+	if err := u.policy.Require(userID, event.GroupID, helpers.PermEventUpdate); err != nil {
+		return nil, nil, err
+	}
+
+	return entry, event, nil
+}
+
+// validateEntryDetails checks the details of a setlist entry of the event.
+func (u *SetlistUsecase) validateEntryDetails(event *model.Event, details domain.SetlistEntryDetails) error {
+	if details.TrackID != nil {
+		track, err := u.trackRepo.GetTrackByID(*details.TrackID)
+		if err != nil {
+			return errors.New("track not found")
+		}
+		if track.GroupID != event.GroupID {
+			return errors.New("track does not belong to this group")
+		}
+	}
+
+	if details.DurationSeconds < 0 || details.DurationSeconds > maxSetlistEntryDuration {
+		return errors.New("duration must be between 0 and 24 hours")
+	}
+	if details.Tempo < 0 || details.Tempo > maxSetlistTempo {
+		return fmt.Errorf("tempo must be between 0 and %d BPM", maxSetlistTempo)
+	}
+	return nil
+}
+
+// applyEntryDetails copies the editable details onto a setlist entry.
+func applyEntryDetails(entry *model.Performance, details domain.SetlistEntryDetails) {
+	entry.TrackID = details.TrackID
+	entry.Title = details.Title
+	entry.StartTime = details.StartTime
+	entry.DurationSeconds = details.DurationSeconds
+	entry.Key = details.Key
+	entry.Tempo = details.Tempo
+	entry.Notes = details.Notes
+}
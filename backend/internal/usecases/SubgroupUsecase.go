@@ -11,24 +11,21 @@ import (
 type SubgroupUsecase struct {
 	subgroupRepo *repositories.SubgroupRepository
 	groupRepo    *repositories.GroupRepository
+	policy       *Policy
 }
 
 func NewSubgroupUsecase() *SubgroupUsecase {
 	return &SubgroupUsecase{
 		subgroupRepo: repositories.NewSubgroupRepository(),
 		groupRepo:    repositories.NewGroupRepository(),
+		policy:       NewPolicy(),
 	}
 }
 
 // CreateSubgroup creates a new subgroup within a band group.
 func (u *SubgroupUsecase) CreateSubgroup(name, description string, groupID uint, userID uint) (*model.Subgroup, error) {
-	role, err := u.groupRepo.GetUserRole(userID, groupID)
-	if err != nil {
-		return nil, errors.New("user not in group")
-	}
-
-	if !helpers.IsManagerOrModeratorRole(role) {
-		return nil, errors.New("insufficient permissions")
+	if err := u.policy.Require(userID, groupID, helpers.PermSubgroupCreate); err != nil {
+		return nil, err
 	}
 
 	subgroup := &model.Subgroup{
@@ -56,6 +53,10 @@ func (u *SubgroupUsecase) GetSubgroup(id uint, userID uint) (*model.Subgroup, er
 		return nil, errors.New("access denied")
 	}
 
+	if subgroup.LeaderIDs, err = u.subgroupRepo.GetLeaderIDs(subgroup.ID, helpers.SubgroupRoleLeader); err != nil {
+		return nil, err
+	}
+
 	return subgroup, nil
 }
 
@@ -66,13 +67,8 @@ func (u *SubgroupUsecase) UpdateSubgroup(id uint, name, description string, user
 		return err
 	}
 
-	role, err := u.groupRepo.GetUserRole(userID, subgroup.GroupID)
-	if err != nil {
-		return errors.New("access denied")
-	}
-
-	if !helpers.IsManagerOrModeratorRole(role) {
-		return errors.New("insufficient permissions")
+	if err := u.policy.Require(userID, subgroup.GroupID, helpers.PermSubgroupUpdate); err != nil {
+		return err
 	}
 
 	subgroup.Name = name
@@ -88,13 +84,8 @@ func (u *SubgroupUsecase) DeleteSubgroup(id uint, userID uint) error {
 		return err
 	}
 
-	role, err := u.groupRepo.GetUserRole(userID, subgroup.GroupID)
-	if err != nil {
-		return errors.New("access denied")
-	}
-
-	if !helpers.IsManagerOrModeratorRole(role) {
-		return errors.New("insufficient permissions")
+	if err := u.policy.Require(userID, subgroup.GroupID, helpers.PermSubgroupDelete); err != nil {
+		return err
 	}
 
 	return u.subgroupRepo.DeleteSubgroup(id)
@@ -107,13 +98,14 @@ func (u *SubgroupUsecase) AddMembers(id uint, userIDs []uint, requestingUserID u
 		return err
 	}
 
-	role, err := u.groupRepo.GetUserRole(requestingUserID, subgroup.GroupID)
-	if err != nil {
-		return errors.New("access denied")
+	if err := u.policy.RequireInSubgroups(requestingUserID, subgroup.GroupID, []uint{id}, helpers.PermSubgroupMembersAdd); err != nil {
+		return err
 	}
 
-	if !helpers.IsManagerOrModeratorRole(role) {
-		return errors.New("insufficient permissions")
+	for _, userID := range userIDs {
+		if _, err := u.groupRepo.GetUserRole(userID, subgroup.GroupID); err != nil {
+			return errors.New("one or more users do not belong to the group")
+		}
 	}
 
 	return u.subgroupRepo.AddMembers(id, userIDs)
@@ -126,16 +118,33 @@ func (u *SubgroupUsecase) RemoveMember(subgroupID uint, userID uint, requestingU
 		return err
 	}
 
-	role, err := u.groupRepo.GetUserRole(requestingUserID, subgroup.GroupID)
+	if err := u.policy.RequireInSubgroups(requestingUserID, subgroup.GroupID, []uint{subgroupID}, helpers.PermSubgroupMembersRemove); err != nil {
+		return err
+	}
+
+	return u.subgroupRepo.RemoveMember(subgroupID, userID)
+}
+
+// UpdateMemberRole makes a subgroup member a leader or a regular member.
+func (u *SubgroupUsecase) UpdateMemberRole(subgroupID, userID uint, role string, requestingUserID uint) error {
+	subgroup, err := u.subgroupRepo.GetSubgroupByID(subgroupID)
 	if err != nil {
-		return errors.New("access denied")
+		return err
 	}
 
-	if role != helpers.RoleManager {
-		return errors.New("insufficient permissions")
+	if err := u.policy.Require(requestingUserID, subgroup.GroupID, helpers.PermSubgroupLeadersAssign); err != nil {
+		return err
 	}
 
-	return u.subgroupRepo.RemoveMember(subgroupID, userID)
+	if !helpers.IsValidSubgroupRole(role) {
+		return errors.New("invalid role")
+	}
+
+	if _, err := u.subgroupRepo.GetMemberRole(subgroupID, userID); err != nil {
+		return errors.New("user is not a member of the subgroup")
+	}
+
+	return u.subgroupRepo.UpdateMemberRole(subgroupID, userID, role)
 }
 
 // GetGroupSubgroups retrieves all subgroups in a specific group.
@@ -145,5 +154,16 @@ func (u *SubgroupUsecase) GetGroupSubgroups(groupID uint, userID uint) ([]*model
 		return nil, errors.New("access denied")
 	}
 
-	return u.subgroupRepo.GetGroupSubgroups(groupID)
+	subgroups, err := u.subgroupRepo.GetGroupSubgroups(groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, subgroup := range subgroups {
+		if subgroup.LeaderIDs, err = u.subgroupRepo.GetLeaderIDs(subgroup.ID, helpers.SubgroupRoleLeader); err != nil {
+			return nil, err
+		}
+	}
+
+	return subgroups, nil
 }
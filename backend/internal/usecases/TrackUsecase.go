@@ -1,35 +1,68 @@
 package usecases
 
 import (
+	"band-manager-backend/internal/config"
+	"band-manager-backend/internal/domain"
 	"band-manager-backend/internal/model"
 	"band-manager-backend/internal/repositories"
+	"band-manager-backend/internal/services"
 	"band-manager-backend/internal/usecases/helpers"
+	"context"
 	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
 )
 
+// maxChangeNoteLength limits the notes describing notesheet versions.
+const maxChangeNoteLength = 1000
+
+// legacyUploadDir is where notesheet files were kept before they were
+// referenced by storage keys. Paths under it are read as keys relative to it.
+const legacyUploadDir = "/app/uploads/"
+
+// Errors of uploads exceeding the limits on notesheet files.
+var (
+	ErrFileTooLarge         = errors.New("file is too large")
+	ErrStorageQuotaExceeded = errors.New("group storage quota exceeded")
+)
+
+// UploadedFile is the content of an uploaded file, such as a multipart.File.
+type UploadedFile interface {
+	io.ReadSeeker
+	io.ReaderAt
+}
+
 // TrackUsecase implements music track management logic.
 type TrackUsecase struct {
-	trackRepo    *repositories.TrackRepository
-	groupRepo    *repositories.GroupRepository
-	subgroupRepo *repositories.SubgroupRepository
+	trackRepo      *repositories.TrackRepository
+	groupRepo      *repositories.GroupRepository
+	subgroupRepo   *repositories.SubgroupRepository
+	instrumentRepo *repositories.InstrumentRepository
+	storage        services.Storage
+	blobs          *BlobStore
+	uploads        *config.UploadConfig
+	policy         *Policy
 }
 
-func NewTrackUsecase() *TrackUsecase {
+func NewTrackUsecase(storage services.Storage, uploads *config.UploadConfig) *TrackUsecase {
 	return &TrackUsecase{
-		trackRepo:    repositories.NewTrackRepository(),
-		groupRepo:    repositories.NewGroupRepository(),
-		subgroupRepo: repositories.NewSubgroupRepository(),
+		trackRepo:      repositories.NewTrackRepository(),
+		groupRepo:      repositories.NewGroupRepository(),
+		subgroupRepo:   repositories.NewSubgroupRepository(),
+		instrumentRepo: repositories.NewInstrumentRepository(),
+		storage:        storage,
+		blobs:          NewBlobStore(storage),
+		uploads:        uploads,
+		policy:         NewPolicy(),
 	}
 }
 
 // CreateTrack creates a new track in a specified group.
 func (u *TrackUsecase) CreateTrack(title, description string, groupID uint, userID uint) (*model.Track, error) {
-	role, err := u.groupRepo.GetUserRole(userID, groupID)
-	if err != nil {
-		return nil, errors.New("user not in group")
-	}
-	if !helpers.IsManagerOrModeratorRole(role) {
-		return nil, errors.New("insufficient permissions")
+	if err := u.policy.Require(userID, groupID, helpers.PermTrackCreate); err != nil {
+		return nil, err
 	}
 
 	track := &model.Track{
@@ -67,13 +100,8 @@ func (u *TrackUsecase) UpdateTrack(id uint, title string, description string, us
 		return err
 	}
 
-	role, err := u.groupRepo.GetUserRole(userID, track.GroupID)
-	if err != nil {
-		return errors.New("access denied")
-	}
-
-	if !helpers.IsManagerOrModeratorRole(role) {
-		return errors.New("insufficient permissions")
+	if err := u.policy.Require(userID, track.GroupID, helpers.PermTrackUpdate); err != nil {
+		return err
 	}
 
 	track.Name = title
@@ -89,13 +117,8 @@ func (u *TrackUsecase) DeleteTrack(id uint, userID uint) error {
 		return err
 	}
 
-	role, err := u.groupRepo.GetUserRole(userID, track.GroupID)
-	if err != nil {
-		return errors.New("access denied")
-	}
-
-	if !helpers.IsManagerOrModeratorRole(role) {
-		return errors.New("insufficient permissions")
+	if err := u.policy.Require(userID, track.GroupID, helpers.PermTrackDelete); err != nil {
+		return err
 	}
 
 	return u.trackRepo.DeleteTrack(id)
@@ -112,45 +135,107 @@ func (u *TrackUsecase) GetGroupTracks(groupID uint, userID uint) ([]*model.Track
 	return u.trackRepo.GetGroupTracks(groupID)
 }
 
-// AddNotesheet adds a new notesheet to a track for specific subgroups.
-func (u *TrackUsecase) AddNotesheet(trackID uint, instrument string, filepath string, subgroupIDs []uint, userID uint) (*model.Notesheet, error) {
-	track, err := u.trackRepo.GetTrackByID(trackID)
+// AddNotesheet adds a new notesheet without a file to a track for specific
+// subgroups; files are added by uploading them. The notesheet is a part for
+// an instrument of the group's catalogue when instrumentID is given, and for
+// the named instrument otherwise.
+func (u *TrackUsecase) AddNotesheet(trackID uint, instrumentID *uint, instrument string, subgroupIDs []uint, userID uint) (*model.Notesheet, error) {
+	track, err := u.requireNotesheetTargets(trackID, subgroupIDs, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	role, err := u.groupRepo.GetUserRole(userID, track.GroupID)
+	instrumentID, instrument, err = u.resolveInstrument(track.GroupID, instrumentID, instrument)
 	if err != nil {
-		return nil, errors.New("user not in group")
+		return nil, err
 	}
-	if !helpers.IsManagerOrModeratorRole(role) {
-		return nil, errors.New("insufficient permissions")
+
+	notesheet := &model.Notesheet{
+		TrackId:      trackID,
+		Instrument:   instrument,
+		InstrumentID: instrumentID,
 	}
 
-	for _, subgroupID := range subgroupIDs {
-		subgroup, err := u.subgroupRepo.GetSubgroupByID(subgroupID)
-		if err != nil {
-			return nil, err
-		}
-		if subgroup.GroupID != track.GroupID {
-			return nil, errors.New("subgroup does not belong to track's group")
-		}
+	if err := u.trackRepo.AddNotesheetToTrack(notesheet, subgroupIDs, nil); err != nil {
+		return nil, err
+	}
+
+	return notesheet, nil
+}
+
+// CreateNotesheetWithFile stores an uploaded file and adds a notesheet
+// referencing it to a track for specific subgroups, the file being its
+// first version. The notesheet's instrument is given as in AddNotesheet.
+func (u *TrackUsecase) CreateNotesheetWithFile(trackID uint, instrumentID *uint, instrument string, subgroupIDs []uint, userID uint,
+	filename string, file UploadedFile, changeNote string) (*model.Notesheet, error) {
+	changeNote, err := normalizeChangeNote(changeNote)
+	if err != nil {
+		return nil, err
+	}
+
+	track, err := u.requireNotesheetTargets(trackID, subgroupIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	instrumentID, instrument, err = u.resolveInstrument(track.GroupID, instrumentID, instrument)
+	if err != nil {
+		return nil, err
+	}
+
+	blob, format, err := u.storeUpload(track.GroupID, file)
+	if err != nil {
+		return nil, err
 	}
 
 	notesheet := &model.Notesheet{
-		TrackId:    trackID,
-		Instrument: instrument,
-		Filepath:   filepath,
+		TrackId:      trackID,
+		Instrument:   instrument,
+		InstrumentID: instrumentID,
+		Filepath:     blob.Key,
+		FileName:     helpers.SanitizeFilename(filename, format),
+		FileType:     format.ContentType,
+	}
+	firstVersion := &model.NotesheetVersion{
+		Size:         blob.Size,
+		ChangeNote:   changeNote,
+		UploadedByID: &userID,
 	}
 
-	if err := u.trackRepo.AddNotesheetToTrack(notesheet, subgroupIDs); err != nil {
+	if err := u.trackRepo.AddNotesheetToTrack(notesheet, subgroupIDs, firstVersion); err != nil {
 		return nil, err
 	}
 
 	return notesheet, nil
 }
 
-// GetUserNotesheets retrieves notesheets available to a specific user.
+// requireNotesheetTargets checks that the user may upload notesheets to the
+// track for the given subgroups, all of which belong to the track's group,
+// and returns the track.
+func (u *TrackUsecase) requireNotesheetTargets(trackID uint, subgroupIDs []uint, userID uint) (*model.Track, error) {
+	track, err := u.trackRepo.GetTrackByID(trackID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.policy.RequireInSubgroups(userID, track.GroupID, subgroupIDs, helpers.PermNotesheetUpload); err != nil {
+		return nil, err
+	}
+
+	for _, subgroupID := range subgroupIDs {
+		subgroup, err := u.subgroupRepo.GetSubgroupByID(subgroupID)
+		if err != nil {
+			return nil, err
+		}
+		if subgroup.GroupID != track.GroupID {
+			return nil, errors.New("subgroup does not belong to track's group")
+		}
+	}
+	return track, nil
+}
+
+// GetUserNotesheets retrieves the notesheets of a track for the subgroups
+// the user is in and the instruments they play.
 func (u *TrackUsecase) GetUserNotesheets(trackID, userID uint) ([]*model.Notesheet, error) {
 	return u.trackRepo.GetUserNotesheets(trackID, userID)
 }
@@ -170,35 +255,262 @@ func (u *TrackUsecase) GetTrackNotesheets(trackID uint, userID uint) ([]*model.N
 	return u.trackRepo.GetTrackNotesheets(trackID)
 }
 
-// UpdateNotesheetFilepath updates the file path for a notesheet.
-func (u *TrackUsecase) UpdateNotesheetFilepath(notesheetID uint, userID uint, filepath string) (*model.Notesheet, error) {
+// SetNotesheetInstrument changes which instrument a notesheet is a part
+// for, given as in AddNotesheet.
+func (u *TrackUsecase) SetNotesheetInstrument(notesheetID uint, instrumentID *uint, instrument string, userID uint) (*model.Notesheet, error) {
 	notesheet, err := u.trackRepo.GetNotesheet(notesheetID)
+	if err != nil {
+		return nil, errors.New("notesheet not found")
+	}
+
+	track, err := u.requireNotesheetUpload(notesheet, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	track, err := u.trackRepo.GetTrackByID(notesheet.TrackId)
+	notesheet.InstrumentID, notesheet.Instrument, err = u.resolveInstrument(track.GroupID, instrumentID, instrument)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.trackRepo.SetNotesheetInstrument(notesheet); err != nil {
+		return nil, err
+	}
+
+	return u.trackRepo.GetNotesheet(notesheetID)
+}
+
+// UploadNotesheetFile stores an uploaded file as a new version of the file
+// of a notesheet, which becomes the current one. Previous versions are kept.
+func (u *TrackUsecase) UploadNotesheetFile(notesheetID uint, userID uint,
+	filename string, file UploadedFile, changeNote string) (*model.Notesheet, error) {
+	changeNote, err := normalizeChangeNote(changeNote)
 	if err != nil {
 		return nil, err
 	}
 
-	role, err := u.groupRepo.GetUserRole(userID, track.GroupID)
+	notesheet, err := u.trackRepo.GetNotesheet(notesheetID)
 	if err != nil {
-		return nil, errors.New("user not in group")
+		return nil, err
 	}
 
-	if !helpers.IsManagerOrModeratorRole(role) {
-		return nil, errors.New("insufficient permissions")
+	track, err := u.requireNotesheetUpload(notesheet, userID)
+	if err != nil {
+		return nil, err
 	}
 
-	err = u.trackRepo.UpdateNotesheetFilepath(notesheetID, filepath)
+	blob, format, err := u.storeUpload(track.GroupID, file)
 	if err != nil {
 		return nil, err
 	}
 
+	version := &model.NotesheetVersion{
+		NotesheetID:  notesheetID,
+		Filepath:     blob.Key,
+		FileName:     helpers.SanitizeFilename(filename, format),
+		FileType:     format.ContentType,
+		Size:         blob.Size,
+		ChangeNote:   changeNote,
+		UploadedByID: &userID,
+	}
+	if err := u.trackRepo.AddNotesheetVersion(version); err != nil {
+		return nil, err
+	}
+
 	return u.trackRepo.GetNotesheet(notesheetID)
 }
 
+// GetNotesheetVersions lists the versions of the file of a notesheet,
+// newest first, marking the current one.
+func (u *TrackUsecase) GetNotesheetVersions(notesheetID uint, userID uint) ([]domain.NotesheetVersion, error) {
+	notesheet, err := u.GetNotesheet(notesheetID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := u.trackRepo.GetNotesheetVersions(notesheetID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.NotesheetVersion, 0, len(versions))
+	for _, version := range versions {
+		summary := domain.NotesheetVersion{
+			ID:           version.ID,
+			NotesheetID:  version.NotesheetID,
+			Version:      version.Version,
+			FileName:     version.FileName,
+			FileType:     version.FileType,
+			Size:         version.Size,
+			ChangeNote:   version.ChangeNote,
+			UploadedByID: version.UploadedByID,
+			UploadedAt:   version.CreatedAt,
+			Current:      version.Version == notesheet.CurrentVersion,
+		}
+		if version.UploadedBy != nil {
+			summary.UploadedByName = strings.TrimSpace(version.UploadedBy.FirstName + " " + version.UploadedBy.LastName)
+		}
+		result = append(result, summary)
+	}
+	return result, nil
+}
+
+// RestoreNotesheetVersion makes a previous version of the file of a
+// notesheet the current one again.
+func (u *TrackUsecase) RestoreNotesheetVersion(versionID uint, userID uint) (*model.Notesheet, error) {
+	version, err := u.trackRepo.GetNotesheetVersion(versionID)
+	if err != nil {
+		return nil, errors.New("version not found")
+	}
+
+	notesheet, err := u.trackRepo.GetNotesheet(version.NotesheetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := u.requireNotesheetUpload(notesheet, userID); err != nil {
+		return nil, err
+	}
+
+	if err := u.trackRepo.RestoreNotesheetVersion(version); err != nil {
+		return nil, err
+	}
+
+	return u.trackRepo.GetNotesheet(notesheet.ID)
+}
+
+// OpenNotesheetVersionFile opens the file of a version of a notesheet for
+// reading, along with the name it is downloaded under.
+func (u *TrackUsecase) OpenNotesheetVersionFile(versionID uint, userID uint) (*services.ObjectReader, string, error) {
+	version, err := u.trackRepo.GetNotesheetVersion(versionID)
+	if err != nil {
+		return nil, "", errors.New("version not found")
+	}
+
+	if _, err := u.GetNotesheet(version.NotesheetID, userID); err != nil {
+		return nil, "", err
+	}
+
+	return u.openFile(version.Filepath, version.FileName)
+}
+
+// OpenNotesheetFile opens the file of a notesheet for reading, along with
+// the name it is downloaded under.
+func (u *TrackUsecase) OpenNotesheetFile(notesheetID uint, userID uint) (*services.ObjectReader, string, error) {
+	notesheet, err := u.GetNotesheet(notesheetID, userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if notesheet.Filepath == "" {
+		return nil, "", errors.New("no file uploaded for this notesheet")
+	}
+
+	return u.openFile(notesheet.Filepath, notesheet.FileName)
+}
+
+// requireNotesheetUpload checks that the user may upload files of the
+// notesheet for all of its subgroups, and returns the notesheet's track.
+func (u *TrackUsecase) requireNotesheetUpload(notesheet *model.Notesheet, userID uint) (*model.Track, error) {
+	track, err := u.trackRepo.GetTrackByID(notesheet.TrackId)
+	if err != nil {
+		return nil, err
+	}
+
+	subgroupIDs := make([]uint, 0, len(notesheet.Subgroups))
+	for _, subgroup := range notesheet.Subgroups {
+		subgroupIDs = append(subgroupIDs, subgroup.ID)
+	}
+
+	if err := u.policy.RequireInSubgroups(userID, track.GroupID, subgroupIDs, helpers.PermNotesheetUpload); err != nil {
+		return nil, err
+	}
+	return track, nil
+}
+
+// resolveInstrument returns the catalogue instrument a notesheet of the
+// group is a part for, if any, and the name of the notesheet's instrument:
+// that of the catalogue instrument, or else the given name.
+func (u *TrackUsecase) resolveInstrument(groupID uint, instrumentID *uint, name string) (*uint, string, error) {
+	if instrumentID == nil {
+		return nil, strings.TrimSpace(name), nil
+	}
+
+	instrument, err := u.instrumentRepo.GetInstrumentByID(*instrumentID)
+	if err != nil || instrument.GroupID != groupID {
+		return nil, "", errors.New("instrument does not belong to track's group")
+	}
+	return &instrument.ID, instrument.Name, nil
+}
+
+// storeUpload checks that an uploaded notesheet file is in an accepted
+// format, within the file size limit and fits in the storage quota of the
+// group, and stores it. Files the group already stores do not count
+// against its quota again.
+func (u *TrackUsecase) storeUpload(groupID uint, file UploadedFile) (*model.Blob, helpers.FileFormat, error) {
+	hash, size, err := helpers.HashContent(file)
+	if err != nil {
+		return nil, helpers.FileFormat{}, err
+	}
+	if size > u.uploads.MaxFileSize {
+		return nil, helpers.FileFormat{}, fmt.Errorf("%w, files may be at most %d MB", ErrFileTooLarge, u.uploads.MaxFileSize>>20)
+	}
+
+	format, err := helpers.DetectNotesheetFormat(file, size)
+	if err != nil {
+		return nil, helpers.FileFormat{}, err
+	}
+
+	stored, err := u.trackRepo.GroupHasFile(groupID, helpers.BlobKey(hash))
+	if err != nil {
+		return nil, helpers.FileFormat{}, err
+	}
+	if !stored {
+		usage, err := u.trackRepo.GetGroupStorageUsage(groupID)
+		if err != nil {
+			return nil, helpers.FileFormat{}, err
+		}
+		if usage+size > u.uploads.GroupStorageQuota {
+			return nil, helpers.FileFormat{}, fmt.Errorf("%w, %d of %d MB used", ErrStorageQuotaExceeded, usage>>20, u.uploads.GroupStorageQuota>>20)
+		}
+	}
+
+	blob, err := u.blobs.StoreHashed(file, hash, size, format.ContentType)
+	if err != nil {
+		return nil, helpers.FileFormat{}, err
+	}
+	return blob, format, nil
+}
+
+// openFile opens a stored notesheet file, named name or after its key.
+func (u *TrackUsecase) openFile(filepath, name string) (*services.ObjectReader, string, error) {
+	key := notesheetFileKey(filepath)
+	object, err := services.OpenObject(context.Background(), u.storage, key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if name == "" {
+		name = path.Base(key)
+	}
+	return object, name, nil
+}
+
+// normalizeChangeNote trims the note describing a notesheet version and
+// checks its length.
+func normalizeChangeNote(note string) (string, error) {
+	note = strings.TrimSpace(note)
+	if len([]rune(note)) > maxChangeNoteLength {
+		return "", fmt.Errorf("change note must be at most %d characters", maxChangeNoteLength)
+	}
+	return note, nil
+}
+
+// notesheetFileKey returns the storage key of a notesheet file, reading
+// paths kept before storage keys were introduced as keys.
+func notesheetFileKey(filepath string) string {
+	return strings.TrimPrefix(filepath, legacyUploadDir)
+}
+
 // GetNotesheet retrieves notesheet details if user has access.
 func (u *TrackUsecase) GetNotesheet(notesheetID uint, userID uint) (*model.Notesheet, error) {
 	notesheet, err := u.trackRepo.GetNotesheet(notesheetID)
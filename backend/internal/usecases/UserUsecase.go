@@ -0,0 +1,32 @@
+package usecases
+
+import (
+	"band-manager-backend/internal/repositories"
+	"errors"
+)
+
+// UserUsecase handles the preferences of users.
+type UserUsecase struct {
+	userRepo *repositories.UserRepository
+}
+
+func NewUserUsecase() *UserUsecase {
+	return &UserUsecase{
+		userRepo: repositories.NewUserRepository(),
+	}
+}
+
+// GetEventReminders reports whether a user gets event reminders.
+func (u *UserUsecase) GetEventReminders(userID uint) (bool, error) {
+	user, err := u.userRepo.GetUserByID(userID)
+	if err != nil {
+		return false, errors.New("user not found")
+	}
+	return user.EventReminders, nil
+}
+
+// SetEventReminders turns event reminders for a user on or off. Notices of
+// changed and cancelled events are sent regardless.
+func (u *UserUsecase) SetEventReminders(userID uint, enabled bool) error {
+	return u.userRepo.SetEventReminders(userID, enabled)
+}
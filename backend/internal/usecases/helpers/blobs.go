@@ -0,0 +1,37 @@
+package helpers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
+)
+
+// HashContent returns the hex-encoded SHA-256 and the size of the content,
+// rewinding it to the start.
+func HashContent(content io.ReadSeeker) (string, int64, error) {
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", 0, err
+	}
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, content)
+	if err != nil {
+		return "", 0, err
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
+
+// BlobKey returns the storage key of the blob with the given hash. Blobs
+// are spread over directories by the first two characters of their hash.
+func BlobKey(hash string) string {
+	return "blobs/" + hash[:2] + "/" + hash
+}
+
+// IsBlobKey reports whether the storage key is that of a blob.
+func IsBlobKey(key string) bool {
+	return strings.HasPrefix(key, "blobs/")
+}
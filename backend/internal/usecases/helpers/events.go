@@ -0,0 +1,93 @@
+package helpers
+
+import (
+	"band-manager-backend/internal/model"
+	"errors"
+	"math"
+	"time"
+)
+
+// Event types.
+const (
+	EventTypeRehearsal = "rehearsal"
+	EventTypeConcert   = "concert"
+	EventTypeRecording = "recording_session"
+	EventTypeMeeting   = "meeting"
+)
+
+// EventTypes lists all event types.
+var EventTypes = []string{EventTypeRehearsal, EventTypeConcert, EventTypeRecording, EventTypeMeeting}
+
+// DefaultTimeZone is used for groups that have not chosen a time zone.
+const DefaultTimeZone = "Europe/Warsaw"
+
+// DefaultEventDuration is used for timed events created without an end.
+const DefaultEventDuration = 2 * time.Hour
+
+// IsValidEventType reports whether the value is a known event type.
+func IsValidEventType(eventType string) bool {
+	for _, t := range EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadTimeZone returns the location of an IANA time zone name. Local and
+// empty names are rejected so that events never depend on the server's zone.
+func LoadTimeZone(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, errors.New("invalid time zone")
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, errors.New("invalid time zone")
+	}
+	return location, nil
+}
+
+// EventLocation returns the time zone of an event, falling back to the default one.
+func EventLocation(event *model.Event) *time.Location {
+	if location, err := LoadTimeZone(event.TimeZone); err == nil {
+		return location
+	}
+	location, _ := LoadTimeZone(DefaultTimeZone)
+	return location
+}
+
+// EventEnd returns the exclusive end of an event. All-day events last until
+// the end of their last day; events stored without an end last the default duration.
+func EventEnd(event *model.Event) time.Time {
+	if event.AllDay {
+		end := event.EndDate
+		if end.IsZero() {
+			end = event.Date
+		}
+		return end.In(EventLocation(event)).AddDate(0, 0, 1)
+	}
+	if event.EndDate.IsZero() {
+		return event.Date.Add(DefaultEventDuration)
+	}
+	return event.EndDate
+}
+
+// OccurrenceEnd returns the end of the occurrence of a recurring event that
+// starts at start, keeping the length of the event.
+func OccurrenceEnd(event *model.Event, start time.Time) time.Time {
+	if event.EndDate.IsZero() {
+		return event.EndDate
+	}
+	if event.AllDay {
+		location := EventLocation(event)
+		days := int(math.Round(event.EndDate.In(location).Sub(event.Date.In(location)).Hours() / 24))
+		return start.In(location).AddDate(0, 0, days)
+	}
+	return start.Add(event.EndDate.Sub(event.Date))
+}
+
+// StartOfDay returns midnight of the day containing t in the location.
+func StartOfDay(t time.Time, location *time.Location) time.Time {
+	year, month, day := t.In(location).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, location)
+}
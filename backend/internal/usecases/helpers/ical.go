@@ -0,0 +1,148 @@
+package helpers
+
+import (
+	"band-manager-backend/internal/model"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxICalLineLength is the longest content line, in octets, allowed by RFC 5545.
+const maxICalLineLength = 75
+
+// ICalendar renders events as an iCalendar (RFC 5545) document. Recurring
+// events are published with their rule; occurrences edited separately are
+// published as overrides of their series and cancelled ones as exceptions.
+// Times are given with IANA time zone identifiers, which calendar apps
+// resolve themselves.
+func ICalendar(name string, events []*model.Event, now time.Time) string {
+	published := make(map[uint]*model.Event, len(events))
+	overridden := make(map[uint][]time.Time)
+	for _, event := range events {
+		published[event.ID] = event
+		if event.SeriesID != nil && event.OriginalDate != nil {
+			overridden[*event.SeriesID] = append(overridden[*event.SeriesID], *event.OriginalDate)
+		}
+	}
+
+	var b strings.Builder
+	writeICalLine(&b, "BEGIN:VCALENDAR")
+	writeICalLine(&b, "VERSION:2.0")
+	writeICalLine(&b, "PRODID:-//Band Manager//Calendar Feed//PL")
+	writeICalLine(&b, "CALSCALE:GREGORIAN")
+	writeICalLine(&b, "METHOD:PUBLISH")
+	writeICalLine(&b, "X-WR-CALNAME:"+escapeICalText(name))
+
+	for _, event := range events {
+		writeICalLine(&b, "BEGIN:VEVENT")
+
+		var series *model.Event
+		if event.SeriesID != nil && event.OriginalDate != nil {
+			series = published[*event.SeriesID]
+		}
+		if series != nil {
+			writeICalLine(&b, "UID:"+eventUID(series.ID))
+			writeICalLine(&b, icalDateProperty("RECURRENCE-ID", series, *event.OriginalDate))
+		} else {
+			writeICalLine(&b, "UID:"+eventUID(event.ID))
+		}
+
+		writeICalLine(&b, "DTSTAMP:"+now.UTC().Format("20060102T150405Z"))
+		writeICalLine(&b, icalDateProperty("DTSTART", event, event.Date))
+		writeICalLine(&b, icalDateProperty("DTEND", event, EventEnd(event)))
+
+		if event.RecurrenceRule != "" {
+			writeICalLine(&b, "RRULE:"+event.RecurrenceRule)
+			for _, exception := range event.Exceptions {
+				if !containsTime(overridden[event.ID], exception.OccurrenceDate) {
+					writeICalLine(&b, icalDateProperty("EXDATE", event, exception.OccurrenceDate))
+				}
+			}
+		}
+
+		writeICalLine(&b, "SUMMARY:"+escapeICalText(event.Title))
+		if event.Location != "" {
+			writeICalLine(&b, "LOCATION:"+escapeICalText(event.Location))
+		}
+		if description := icalDescription(event); description != "" {
+			writeICalLine(&b, "DESCRIPTION:"+escapeICalText(description))
+		}
+		if event.Type != "" {
+			writeICalLine(&b, "CATEGORIES:"+escapeICalText(event.Type))
+		}
+		writeICalLine(&b, "END:VEVENT")
+	}
+
+	writeICalLine(&b, "END:VCALENDAR")
+	return b.String()
+}
+
+// icalDescription combines the description of an event with its setlist.
+func icalDescription(event *model.Event) string {
+	description := event.Description
+	setlist := SetlistSummary(event)
+	if len(setlist) == 0 {
+		return description
+	}
+
+	if description != "" {
+		description += "\n\n"
+	}
+	description += "Setlista:"
+	for i, name := range setlist {
+		description += fmt.Sprintf("\n%d. %s", i+1, name)
+	}
+	return description
+}
+
+// icalDateProperty formats a date-time property in the time zone of the
+// event, or as a date for all-day events.
+func icalDateProperty(name string, event *model.Event, t time.Time) string {
+	location, err := LoadTimeZone(event.TimeZone)
+	if err != nil {
+		return name + ":" + t.UTC().Format("20060102T150405Z")
+	}
+	if event.AllDay {
+		return name + ";VALUE=DATE:" + t.In(location).Format("20060102")
+	}
+	return name + ";TZID=" + event.TimeZone + ":" + t.In(location).Format("20060102T150405")
+}
+
+func eventUID(eventID uint) string {
+	return fmt.Sprintf("event-%d@band-manager", eventID)
+}
+
+// escapeICalText escapes a TEXT property value.
+func escapeICalText(value string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", "",
+	).Replace(value)
+}
+
+// writeICalLine writes a content line, folding it into continuation lines
+// without splitting multi-byte characters.
+func writeICalLine(b *strings.Builder, line string) {
+	limit := maxICalLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space that counts towards their length.
+		limit = maxICalLineLength - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+func isRuneStart(c byte) bool {
+	return c&0xC0 != 0x80
+}
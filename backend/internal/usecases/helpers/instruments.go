@@ -0,0 +1,67 @@
+package helpers
+
+import (
+	"band-manager-backend/internal/model"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MaxInstrumentNameLength limits the names of catalogue instruments, in characters.
+const MaxInstrumentNameLength = 100
+
+// InstrumentFamilies are the families catalogue instruments belong to.
+var InstrumentFamilies = []string{"woodwind", "brass", "percussion", "strings", "keyboard", "vocal", "other"}
+
+// Clefs are the clefs parts of catalogue instruments are written in.
+var Clefs = []string{"treble", "bass", "alto", "tenor", "percussion"}
+
+// Transpositions are the keys instruments sound in when playing a written
+// C, C being concert pitch.
+var Transpositions = []string{"C", "Db", "D", "Eb", "E", "F", "F#", "G", "Ab", "A", "Bb", "B"}
+
+// NormalizeInstrument validates a catalogue instrument, trimming its name
+// and writing its family, transposition and clef in their canonical form.
+// Instruments are in concert pitch, the treble clef and the "other" family
+// unless given.
+func NormalizeInstrument(instrument *model.Instrument) error {
+	instrument.Name = strings.TrimSpace(instrument.Name)
+	if instrument.Name == "" {
+		return errors.New("instrument name is required")
+	}
+	if len([]rune(instrument.Name)) > MaxInstrumentNameLength {
+		return fmt.Errorf("instrument name must be at most %d characters", MaxInstrumentNameLength)
+	}
+
+	family, ok := matchOption(InstrumentFamilies, instrument.Family, "other")
+	if !ok {
+		return fmt.Errorf("unknown instrument family, use one of: %s", strings.Join(InstrumentFamilies, ", "))
+	}
+	transposition := strings.NewReplacer("♭", "b", "♯", "#").Replace(instrument.Transposition)
+	transposition, ok = matchOption(Transpositions, transposition, "C")
+	if !ok {
+		return fmt.Errorf("unknown transposition, use one of: %s", strings.Join(Transpositions, ", "))
+	}
+	clef, ok := matchOption(Clefs, instrument.Clef, "treble")
+	if !ok {
+		return fmt.Errorf("unknown clef, use one of: %s", strings.Join(Clefs, ", "))
+	}
+
+	instrument.Family, instrument.Transposition, instrument.Clef = family, transposition, clef
+	return nil
+}
+
+// matchOption returns the option equal to the value ignoring case and
+// surrounding spaces, or the default for empty values.
+func matchOption(options []string, value, defaultValue string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return defaultValue, true
+	}
+	for _, option := range options {
+		if strings.EqualFold(option, value) {
+			return option, true
+		}
+	}
+	return "", false
+}
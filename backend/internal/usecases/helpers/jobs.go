@@ -0,0 +1,44 @@
+package helpers
+
+import "time"
+
+// Job types.
+const (
+	JobTypeVerificationEmail    = "email.verification"
+	JobTypePasswordResetEmail   = "email.password_reset"
+	JobTypeInvitationEmail      = "email.invitation"
+	JobTypeJoinRequestEmail     = "email.join_request_decision"
+	JobTypeEventEmail           = "email.event"
+	JobTypeAnnouncementEmail    = "email.announcement"
+	JobTypeEventReminderEmail   = "email.event_reminder"
+	JobTypeEventChangeEmail     = "email.event_change"
+	JobTypeEventCancelEmail     = "email.event_cancellation"
+	JobTypeEventReminder        = "event.reminder"
+	JobTypeCalendarSyncEvent    = "calendar.sync_event"
+	JobTypeCalendarRemoveCopy   = "calendar.remove_copy"
+	JobTypeCalendarSyncUpcoming = "calendar.sync_upcoming"
+)
+
+// DefaultJobMaxAttempts is how many times a job is run before it is given up on.
+const DefaultJobMaxAttempts = 8
+
+const (
+	jobBaseBackoff = 30 * time.Second
+	jobMaxBackoff  = 6 * time.Hour
+)
+
+// JobBackoff returns the delay before retrying a job that failed the given
+// number of times. The delay doubles with every attempt up to a cap.
+func JobBackoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	backoff := jobBaseBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= jobMaxBackoff {
+			return jobMaxBackoff
+		}
+	}
+	return backoff
+}
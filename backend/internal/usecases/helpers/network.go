@@ -0,0 +1,46 @@
+package helpers
+
+import (
+	"net"
+	"strings"
+)
+
+// ClientIP returns the address of the client of a request received from
+// remoteAddr. X-Forwarded-For is only honoured for requests from trusted
+// proxies: the client is then the last address in it that is not one of a
+// trusted proxy, since the client can put anything before that.
+func ClientIP(remoteAddr, forwardedFor string, trustedProxies []*net.IPNet) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	if forwardedFor == "" || !isTrustedProxy(host, trustedProxies) {
+		return host
+	}
+
+	addresses := strings.Split(forwardedFor, ",")
+	for i := len(addresses) - 1; i >= 0; i-- {
+		address := strings.TrimSpace(addresses[i])
+		if net.ParseIP(address) == nil {
+			break
+		}
+		host = address
+		if !isTrustedProxy(address, trustedProxies) {
+			break
+		}
+	}
+	return host
+}
+
+func isTrustedProxy(address string, trustedProxies []*net.IPNet) bool {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false
+	}
+	for _, network := range trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,295 @@
+package helpers
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Page sizes in points.
+const (
+	A4Width  = 595.28
+	A4Height = 841.89
+)
+
+// PDFFont selects one of the standard fonts every PDF reader provides, so
+// documents need no embedded fonts.
+type PDFFont int
+
+const (
+	FontRegular PDFFont = iota
+	FontBold
+)
+
+// pdfGlyph is a character outside ASCII printable by the standard fonts,
+// encoded with a custom code. Accented letters are as wide as their base letter.
+type pdfGlyph struct {
+	code  byte
+	name  string
+	base  rune
+	width int
+}
+
+// helveticaWidths and helveticaBoldWidths hold the widths of the ASCII
+// characters from space to tilde, in thousandths of the font size.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
+
+// pdfGlyphs maps the supported characters outside ASCII to their codes.
+var pdfGlyphs = map[rune]pdfGlyph{
+	'ą': {128, "aogonek", 'a', 0},
+	'ć': {129, "cacute", 'c', 0},
+	'ę': {130, "eogonek", 'e', 0},
+	'ł': {131, "lslash", 'l', 0},
+	'ń': {132, "nacute", 'n', 0},
+	'ó': {133, "oacute", 'o', 0},
+	'ś': {134, "sacute", 's', 0},
+	'ź': {135, "zacute", 'z', 0},
+	'ż': {136, "zdotaccent", 'z', 0},
+	'Ą': {137, "Aogonek", 'A', 0},
+	'Ć': {138, "Cacute", 'C', 0},
+	'Ę': {139, "Eogonek", 'E', 0},
+	'Ł': {140, "Lslash", 'L', 0},
+	'Ń': {141, "Nacute", 'N', 0},
+	'Ó': {142, "Oacute", 'O', 0},
+	'Ś': {143, "Sacute", 'S', 0},
+	'Ź': {144, "Zacute", 'Z', 0},
+	'Ż': {145, "Zdotaccent", 'Z', 0},
+	'ä': {146, "adieresis", 'a', 0},
+	'ö': {147, "odieresis", 'o', 0},
+	'ü': {148, "udieresis", 'u', 0},
+	'Ä': {149, "Adieresis", 'A', 0},
+	'Ö': {150, "Odieresis", 'O', 0},
+	'Ü': {151, "Udieresis", 'U', 0},
+	'ß': {152, "germandbls", 0, 611},
+	'é': {153, "eacute", 'e', 0},
+	'è': {154, "egrave", 'e', 0},
+	'á': {155, "aacute", 'a', 0},
+	'à': {156, "agrave", 'a', 0},
+	'č': {157, "ccaron", 'c', 0},
+	'š': {158, "scaron", 's', 0},
+	'ž': {159, "zcaron", 'z', 0},
+	'–': {160, "endash", 0, 556},
+	'—': {161, "emdash", 0, 1000},
+	'„': {162, "quotedblbase", 0, 333},
+	'”': {163, "quotedblright", 0, 333},
+	'“': {164, "quotedblleft", 0, 333},
+	'’': {165, "quoteright", 0, 222},
+	'•': {166, "bullet", 0, 350},
+	'…': {167, "ellipsis", 0, 1000},
+	'♭': {168, "b", 0, 556},
+	'♯': {169, "numbersign", 0, 556},
+}
+
+// pdfUnknownCharacter replaces characters the standard fonts cannot print.
+const pdfUnknownCharacter = '?'
+
+// PDFDocument builds simple documents of text and lines. Coordinates are in
+// points from the top left corner of the page.
+type PDFDocument struct {
+	width  float64
+	height float64
+	pages  []*bytes.Buffer
+}
+
+// NewPDFDocument creates an empty document with pages of the given size.
+func NewPDFDocument(width, height float64) *PDFDocument {
+	return &PDFDocument{width: width, height: height}
+}
+
+// AddPage starts a new page; following drawing goes to it.
+func (d *PDFDocument) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+}
+
+// PageCount returns the number of pages added so far.
+func (d *PDFDocument) PageCount() int {
+	return len(d.pages)
+}
+
+// Width returns the page width.
+func (d *PDFDocument) Width() float64 {
+	return d.width
+}
+
+// Height returns the page height.
+func (d *PDFDocument) Height() float64 {
+	return d.height
+}
+
+// Text draws a line of text with its baseline at y on the given page.
+func (d *PDFDocument) Text(page int, x, y float64, font PDFFont, size float64, text string) {
+	fmt.Fprintf(d.pages[page], "BT /F%d %.2f Tf %.2f %.2f Td (%s) Tj ET\n",
+		int(font)+1, size, x, d.height-y, encodePDFText(text))
+}
+
+// Line draws a straight line on the given page.
+func (d *PDFDocument) Line(page int, x1, y1, x2, y2, lineWidth float64) {
+	fmt.Fprintf(d.pages[page], "%.2f w %.2f %.2f m %.2f %.2f l S\n",
+		lineWidth, x1, d.height-y1, x2, d.height-y2)
+}
+
+// Bytes renders the document. Documents without pages get one empty page.
+func (d *PDFDocument) Bytes() []byte {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	var b bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, b.Len())
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	// Objects 1-5 are the catalog, the page tree, the fonts and their
+	// encoding; every page is followed by its content stream.
+	firstPage := 6
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+
+	b.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d /MediaBox [0 0 %.2f %.2f] >>",
+		strings.Join(kids, " "), len(d.pages), d.width, d.height))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding 5 0 R >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding 5 0 R >>")
+	object(fmt.Sprintf("<< /Type /Encoding /BaseEncoding /WinAnsiEncoding /Differences [%s] >>", pdfDifferences()))
+	for i, content := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			firstPage+2*i+1))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return b.Bytes()
+}
+
+// TextWidth returns the width of text printed in the given font and size.
+func TextWidth(text string, font PDFFont, size float64) float64 {
+	widths := &helveticaWidths
+	if font == FontBold {
+		widths = &helveticaBoldWidths
+	}
+
+	total := 0
+	for _, r := range text {
+		if glyph, ok := pdfGlyphs[r]; ok {
+			if glyph.base == 0 {
+				total += glyph.width
+				continue
+			}
+			r = glyph.base
+		}
+		if r < ' ' || r > '~' {
+			r = pdfUnknownCharacter
+		}
+		total += widths[r-' ']
+	}
+	return float64(total) * size / 1000
+}
+
+// WrapText splits text into lines no wider than width, breaking at spaces
+// and, for words longer than a line, inside words. Line breaks in text are kept.
+func WrapText(text string, font PDFFont, size, width float64) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if TextWidth(candidate, font, size) <= width {
+				line = candidate
+				continue
+			}
+			if line != "" {
+				lines = append(lines, line)
+			}
+			for TextWidth(word, font, size) > width && utf8.RuneCountInString(word) > 1 {
+				split := fittingPrefix(word, font, size, width)
+				lines = append(lines, word[:split])
+				word = word[split:]
+			}
+			line = word
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// fittingPrefix returns the length in bytes of the longest prefix of word,
+// at least one character long, no wider than width.
+func fittingPrefix(word string, font PDFFont, size, width float64) int {
+	_, end := utf8.DecodeRuneInString(word)
+	for i := range word {
+		if i <= end {
+			continue
+		}
+		if TextWidth(word[:i], font, size) > width {
+			break
+		}
+		end = i
+	}
+	return end
+}
+
+// encodePDFText encodes text for a PDF string literal in the encoding of
+// the document fonts.
+func encodePDFText(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= ' ' && r <= '~':
+			b.WriteRune(r)
+		case r == '\t':
+			b.WriteByte(' ')
+		default:
+			if glyph, ok := pdfGlyphs[r]; ok {
+				fmt.Fprintf(&b, "\\%03o", glyph.code)
+			} else {
+				b.WriteRune(pdfUnknownCharacter)
+			}
+		}
+	}
+	return b.String()
+}
+
+// pdfDifferences lists the glyph names of the custom codes.
+func pdfDifferences() string {
+	names := make([]string, 256)
+	first, last := 255, 0
+	for _, glyph := range pdfGlyphs {
+		names[glyph.code] = "/" + glyph.name
+		first = min(first, int(glyph.code))
+		last = max(last, int(glyph.code))
+	}
+	return fmt.Sprintf("%d %s", first, strings.Join(names[first:last+1], " "))
+}
@@ -1,11 +1,184 @@
 package helpers
 
+import "band-manager-backend/internal/model"
+
 const (
 	RoleManager   = "manager"
 	RoleMember    = "member"
 	RoleModerator = "moderator"
 )
 
-func IsManagerOrModeratorRole(role string) bool {
-	return role == RoleManager || role == RoleModerator
+const (
+	SubgroupRoleLeader = "leader"
+	SubgroupRoleMember = "member"
+)
+
+// Permission names a single action a group member may be allowed to perform.
+type Permission string
+
+const (
+	PermTrackCreate           Permission = "track.create"
+	PermTrackUpdate           Permission = "track.update"
+	PermTrackDelete           Permission = "track.delete"
+	PermNotesheetUpload       Permission = "notesheet.upload"
+	PermEventCreate           Permission = "event.create"
+	PermEventUpdate           Permission = "event.update"
+	PermEventDelete           Permission = "event.delete"
+	PermEventAttendanceView   Permission = "event.attendance.view"
+	PermEventConflictsForce   Permission = "event.conflicts.force"
+	PermAnnouncementSend      Permission = "announcement.send"
+	PermAnnouncementDelete    Permission = "announcement.delete"
+	PermSubgroupCreate        Permission = "subgroup.create"
+	PermSubgroupUpdate        Permission = "subgroup.update"
+	PermSubgroupDelete        Permission = "subgroup.delete"
+	PermSubgroupMembersAdd    Permission = "subgroup.members.add"
+	PermSubgroupMembersRemove Permission = "subgroup.members.remove"
+	PermSubgroupLeadersAssign Permission = "subgroup.leaders.assign"
+	PermMemberRemove          Permission = "member.remove"
+	PermMemberInvite          Permission = "member.invite"
+	PermJoinRequestsManage    Permission = "member.requests.manage"
+	PermGroupSettingsUpdate   Permission = "group.settings.update"
+	PermMemberRoleUpdate      Permission = "member.role.update"
+	PermTokenView             Permission = "token.view"
+	PermTokenRefresh          Permission = "token.refresh"
+	PermRolesManage           Permission = "roles.manage"
+	PermInstrumentsManage     Permission = "instruments.manage"
+)
+
+// AllPermissions lists every permission known to the application.
+var AllPermissions = []Permission{
+	PermTrackCreate,
+	PermTrackUpdate,
+	PermTrackDelete,
+	PermNotesheetUpload,
+	PermEventCreate,
+	PermEventUpdate,
+	PermEventDelete,
+	PermEventAttendanceView,
+	PermEventConflictsForce,
+	PermAnnouncementSend,
+	PermAnnouncementDelete,
+	PermSubgroupCreate,
+	PermSubgroupUpdate,
+	PermSubgroupDelete,
+	PermSubgroupMembersAdd,
+	PermSubgroupMembersRemove,
+	PermSubgroupLeadersAssign,
+	PermMemberRemove,
+	PermMemberInvite,
+	PermJoinRequestsManage,
+	PermGroupSettingsUpdate,
+	PermMemberRoleUpdate,
+	PermTokenView,
+	PermTokenRefresh,
+	PermRolesManage,
+	PermInstrumentsManage,
+}
+
+// moderatorPermissions are granted to moderators: managing the band's
+// content and members, but not its roles, access token or subgroup rosters,
+// and not scheduling events over members' other bookings.
+var moderatorPermissions = []Permission{
+	PermTrackCreate,
+	PermTrackUpdate,
+	PermTrackDelete,
+	PermNotesheetUpload,
+	PermEventCreate,
+	PermEventUpdate,
+	PermEventDelete,
+	PermEventAttendanceView,
+	PermAnnouncementSend,
+	PermAnnouncementDelete,
+	PermSubgroupCreate,
+	PermSubgroupUpdate,
+	PermSubgroupDelete,
+	PermSubgroupMembersAdd,
+	PermMemberRemove,
+	PermJoinRequestsManage,
+	PermInstrumentsManage,
+}
+
+// BuiltInRolePermissions maps every built-in role to the permissions it grants.
+var BuiltInRolePermissions = map[string][]Permission{
+	RoleManager:   AllPermissions,
+	RoleModerator: moderatorPermissions,
+	RoleMember:    {},
+}
+
+// SubgroupLeaderPermissions are granted to subgroup leaders, limited to
+// their own subgroup, regardless of their role in the group.
+var SubgroupLeaderPermissions = []Permission{
+	PermSubgroupMembersAdd,
+	PermSubgroupMembersRemove,
+	PermNotesheetUpload,
+	PermAnnouncementSend,
+}
+
+// IsValidSubgroupRole reports whether the role is leader or member.
+func IsValidSubgroupRole(role string) bool {
+	return role == SubgroupRoleLeader || role == SubgroupRoleMember
+}
+
+// IsBuiltInRole reports whether the role is one of manager, moderator or member.
+func IsBuiltInRole(role string) bool {
+	_, ok := BuiltInRolePermissions[role]
+	return ok
+}
+
+// IsValidPermission reports whether the permission is known to the application.
+func IsValidPermission(permission Permission) bool {
+	for _, p := range AllPermissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPermission reports whether the permission is present in the list.
+func HasPermission(permissions []Permission, permission Permission) bool {
+	for _, p := range permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// MissingPermissions returns the permissions of granted that are not in held.
+func MissingPermissions(held, granted []Permission) []Permission {
+	var missing []Permission
+	for _, permission := range granted {
+		if !HasPermission(held, permission) {
+			missing = append(missing, permission)
+		}
+	}
+	return missing
+}
+
+// ResolveRolePermissions returns the permissions granted by a role: those of
+// the built-in role of that name, or else those stored on customRole, the
+// group's role of that name, which is nil if the group defines none. Custom
+// roles cannot shadow built-in ones, and unknown stored permissions grant nothing.
+func ResolveRolePermissions(role string, customRole *model.CustomRole) []Permission {
+	if permissions, ok := BuiltInRolePermissions[role]; ok {
+		return permissions
+	}
+	if customRole == nil {
+		return nil
+	}
+
+	permissions := make([]Permission, 0, len(customRole.Permissions))
+	for _, permission := range customRole.Permissions {
+		if IsValidPermission(Permission(permission)) {
+			permissions = append(permissions, Permission(permission))
+		}
+	}
+	return permissions
+}
+
+// RoleHasPermission reports whether a built-in role grants the permission.
+// Custom roles are resolved by the policy in the usecases package.
+func RoleHasPermission(role string, permission Permission) bool {
+	return HasPermission(BuiltInRolePermissions[role], permission)
 }
@@ -0,0 +1,217 @@
+package helpers
+
+import (
+	"band-manager-backend/internal/domain"
+	"band-manager-backend/internal/model"
+	"fmt"
+	"strings"
+)
+
+// Printable layouts of events.
+const (
+	ExportLayoutStage     = "stage"
+	ExportLayoutProgramme = "programme"
+)
+
+const (
+	pdfMargin = 50.0
+	// Stage setlists use the largest font that fits all entries on one
+	// page, within these bounds.
+	minStageFontSize = 20.0
+	maxStageFontSize = 40.0
+)
+
+// StageSetlistPDF renders the setlist of an event as an A4 page to be put
+// on stage: entry titles in large print, breaks and tempo smaller.
+func StageSetlistPDF(event *model.Event, setlist domain.Setlist) []byte {
+	doc := NewPDFDocument(A4Width, A4Height)
+	flow := newPDFFlow(doc)
+
+	location := EventLocation(event)
+	flow.text(pdfMargin, FontBold, 14, event.Title)
+	flow.text(pdfMargin, FontRegular, 10, event.Date.In(location).Format("02.01.2006")+", "+event.Location)
+	flow.rule()
+
+	if len(setlist.Entries) == 0 {
+		flow.text(pdfMargin, FontRegular, 14, "Brak utworów w setliście.")
+		return doc.Bytes()
+	}
+
+	size := (doc.Height() - pdfMargin - flow.y) / (float64(len(setlist.Entries)) * 1.45)
+	size = min(max(size, minStageFontSize), maxStageFontSize)
+	numberWidth := TextWidth(fmt.Sprintf("%d. ", len(setlist.Entries)), FontBold, size)
+	textX := pdfMargin + numberWidth
+
+	for _, entry := range setlist.Entries {
+		if entry.IsBreak {
+			flow.paragraph(textX, FontRegular, size*0.6, "— "+entry.Title+" —")
+			flow.space(size * 0.15)
+			continue
+		}
+
+		lines := WrapText(entry.Title, FontBold, size, doc.Width()-pdfMargin-textX)
+		flow.reserve(size * 1.3)
+		flow.doc.Text(flow.page, pdfMargin, flow.y+size, FontBold, size, fmt.Sprintf("%d.", entry.Position))
+		for _, line := range lines {
+			flow.text(textX, FontBold, size, line)
+		}
+		if details := entryDetails(entry); details != "" {
+			flow.text(textX, FontRegular, size*0.45, details)
+		}
+		flow.space(size * 0.15)
+	}
+
+	return doc.Bytes()
+}
+
+// EventProgrammePDF renders an event as an A4 programme: its time, place
+// and description, the setlist with planned start times and the notes for
+// each subgroup.
+func EventProgrammePDF(event *model.Event, setlist domain.Setlist, notes []domain.SubgroupNote) []byte {
+	doc := NewPDFDocument(A4Width, A4Height)
+	flow := newPDFFlow(doc)
+	location := EventLocation(event)
+	timeX := pdfMargin
+	titleX := pdfMargin + 50
+	right := doc.Width() - pdfMargin
+
+	if event.Group.Name != "" {
+		flow.text(pdfMargin, FontRegular, 11, event.Group.Name)
+	}
+	flow.paragraph(pdfMargin, FontBold, 22, event.Title)
+	flow.text(pdfMargin, FontRegular, 12, programmeEventTime(event))
+	if event.Location != "" {
+		flow.paragraph(pdfMargin, FontRegular, 12, event.Location)
+	}
+	if description := strings.TrimSpace(event.Description); description != "" {
+		flow.space(6)
+		flow.paragraph(pdfMargin, FontRegular, 11, description)
+	}
+	flow.rule()
+
+	flow.text(pdfMargin, FontBold, 14, "Program")
+	flow.space(4)
+	if len(setlist.Entries) == 0 {
+		flow.text(pdfMargin, FontRegular, 11, "Brak utworów w programie.")
+	}
+	for _, entry := range setlist.Entries {
+		font := FontBold
+		if entry.IsBreak {
+			font = FontRegular
+		}
+
+		duration := ""
+		if entry.DurationSeconds > 0 {
+			duration = FormatDuration(entry.DurationSeconds)
+		}
+		titleWidth := right - titleX - TextWidth(duration, FontRegular, 11) - 10
+
+		flow.reserve(11 * 1.3)
+		baseline := flow.y + 11
+		flow.doc.Text(flow.page, timeX, baseline, FontRegular, 11, entry.PlannedStart.In(location).Format("15:04"))
+		flow.doc.Text(flow.page, right-TextWidth(duration, FontRegular, 11), baseline, FontRegular, 11, duration)
+		for _, line := range WrapText(fmt.Sprintf("%d. %s", entry.Position, entry.Title), font, 11, titleWidth) {
+			flow.text(titleX, font, 11, line)
+		}
+		if details := entryDetails(entry); details != "" {
+			flow.text(titleX, FontRegular, 9, details)
+		}
+		if entry.Notes != "" {
+			flow.paragraph(titleX, FontRegular, 9, entry.Notes)
+		}
+		flow.space(3)
+	}
+	if len(setlist.Entries) > 0 {
+		flow.space(6)
+		flow.text(pdfMargin, FontRegular, 11, "Czas trwania: "+FormatDuration(setlist.TotalDurationSeconds))
+		if setlist.PlannedEnd != nil {
+			flow.text(pdfMargin, FontRegular, 11, "Planowane zakończenie: "+setlist.PlannedEnd.In(location).Format("15:04"))
+		}
+	}
+
+	if len(notes) > 0 {
+		flow.rule()
+		flow.text(pdfMargin, FontBold, 14, "Uwagi dla sekcji")
+		flow.space(4)
+		for _, note := range notes {
+			flow.text(pdfMargin, FontBold, 11, note.SubgroupName)
+			flow.paragraph(pdfMargin, FontRegular, 11, note.Notes)
+			flow.space(6)
+		}
+	}
+
+	for page := 0; page < doc.PageCount(); page++ {
+		footer := fmt.Sprintf("Strona %d z %d", page+1, doc.PageCount())
+		doc.Text(page, (doc.Width()-TextWidth(footer, FontRegular, 9))/2, doc.Height()-pdfMargin/2, FontRegular, 9, footer)
+	}
+	return doc.Bytes()
+}
+
+// programmeEventTime renders the start and end of an event in its time zone.
+func programmeEventTime(event *model.Event) string {
+	location := EventLocation(event)
+	start := event.Date.In(location)
+
+	if event.AllDay {
+		last := EventEnd(event).AddDate(0, 0, -1)
+		if last.Format("2006-01-02") == start.Format("2006-01-02") {
+			return start.Format("02.01.2006") + " (cały dzień)"
+		}
+		return fmt.Sprintf("%s – %s (cały dzień)", start.Format("02.01.2006"), last.Format("02.01.2006"))
+	}
+
+	end := EventEnd(event).In(location)
+	if end.Format("2006-01-02") == start.Format("2006-01-02") {
+		return fmt.Sprintf("%s, %s – %s", start.Format("02.01.2006"), start.Format("15:04"), end.Format("15:04"))
+	}
+	return fmt.Sprintf("%s – %s", start.Format("02.01.2006 15:04"), end.Format("02.01.2006 15:04"))
+}
+
+// pdfFlow lays out text from the top of the page down, continuing on a new
+// page when the current one is full.
+type pdfFlow struct {
+	doc  *PDFDocument
+	page int
+	y    float64
+}
+
+func newPDFFlow(doc *PDFDocument) *pdfFlow {
+	doc.AddPage()
+	return &pdfFlow{doc: doc, y: pdfMargin}
+}
+
+// reserve starts a new page unless height fits on the current one.
+func (f *pdfFlow) reserve(height float64) {
+	if f.y+height > f.doc.Height()-pdfMargin {
+		f.doc.AddPage()
+		f.page = f.doc.PageCount() - 1
+		f.y = pdfMargin
+	}
+}
+
+// text writes a line of text and moves below it.
+func (f *pdfFlow) text(x float64, font PDFFont, size float64, text string) {
+	f.reserve(size * 1.3)
+	f.doc.Text(f.page, x, f.y+size, font, size, text)
+	f.y += size * 1.3
+}
+
+// paragraph writes text wrapped between x and the right margin.
+func (f *pdfFlow) paragraph(x float64, font PDFFont, size float64, text string) {
+	for _, line := range WrapText(text, font, size, f.doc.Width()-pdfMargin-x) {
+		f.text(x, font, size, line)
+	}
+}
+
+// space moves down by height.
+func (f *pdfFlow) space(height float64) {
+	f.y += height
+}
+
+// rule draws a horizontal line across the page between sections.
+func (f *pdfFlow) rule() {
+	f.reserve(20)
+	f.y += 8
+	f.doc.Line(f.page, pdfMargin, f.y, f.doc.Width()-pdfMargin, f.y, 0.5)
+	f.y += 12
+}
@@ -0,0 +1,14 @@
+package helpers
+
+import "band-manager-backend/internal/model"
+
+// VerifiedRecipients filters out users who have not confirmed their email address.
+func VerifiedRecipients(users []*model.User) []*model.User {
+	var verified []*model.User
+	for _, user := range users {
+		if user.EmailVerified {
+			verified = append(verified, user)
+		}
+	}
+	return verified
+}
@@ -0,0 +1,268 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FreqDaily   = "DAILY"
+	FreqWeekly  = "WEEKLY"
+	FreqMonthly = "MONTHLY"
+)
+
+// Scopes of an edit or cancellation of a recurring event.
+const (
+	SeriesScopeThis      = "this"
+	SeriesScopeFollowing = "following"
+	SeriesScopeAll       = "all"
+)
+
+// maxOccurrenceIterations bounds rule expansion so malformed rules cannot loop forever.
+const maxOccurrenceIterations = 10000
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// RecurrenceRule is the supported subset of an RFC 5545 RRULE:
+// FREQ (DAILY, WEEKLY, MONTHLY), INTERVAL, COUNT, UNTIL and BYDAY for weekly rules.
+type RecurrenceRule struct {
+	Freq     string
+	Interval int
+	Count    int
+	Until    *time.Time
+	ByDay    []time.Weekday
+}
+
+// IsValidSeriesScope reports whether the scope is this, following or all.
+func IsValidSeriesScope(scope string) bool {
+	return scope == SeriesScopeThis || scope == SeriesScopeFollowing || scope == SeriesScopeAll
+}
+
+// ParseRecurrenceRule parses an RRULE value such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH;COUNT=10".
+// The optional "RRULE:" prefix is accepted.
+func ParseRecurrenceRule(value string) (*RecurrenceRule, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" {
+		return nil, errors.New("empty recurrence rule")
+	}
+
+	rule := &RecurrenceRule{Interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid recurrence rule part %q", part)
+		}
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.Freq = strings.ToUpper(val)
+			if rule.Freq != FreqDaily && rule.Freq != FreqWeekly && rule.Freq != FreqMonthly {
+				return nil, fmt.Errorf("unsupported frequency %q", val)
+			}
+		case "INTERVAL":
+			interval, err := strconv.Atoi(val)
+			if err != nil || interval < 1 {
+				return nil, errors.New("interval must be a positive number")
+			}
+			rule.Interval = interval
+		case "COUNT":
+			count, err := strconv.Atoi(val)
+			if err != nil || count < 1 {
+				return nil, errors.New("count must be a positive number")
+			}
+			rule.Count = count
+		case "UNTIL":
+			until, err := parseRuleTime(val)
+			if err != nil {
+				return nil, err
+			}
+			rule.Until = &until
+		case "BYDAY":
+			for _, code := range strings.Split(val, ",") {
+				day, ok := weekdayCodes[strings.ToUpper(code)]
+				if !ok {
+					return nil, fmt.Errorf("unsupported weekday %q", code)
+				}
+				rule.ByDay = append(rule.ByDay, day)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported recurrence rule part %q", key)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, errors.New("recurrence rule requires FREQ")
+	}
+	if rule.Count > 0 && rule.Until != nil {
+		return nil, errors.New("recurrence rule cannot have both COUNT and UNTIL")
+	}
+	if len(rule.ByDay) > 0 && rule.Freq != FreqWeekly {
+		return nil, errors.New("BYDAY is only supported for weekly rules")
+	}
+
+	sort.Slice(rule.ByDay, func(i, j int) bool {
+		return mondayIndex(rule.ByDay[i]) < mondayIndex(rule.ByDay[j])
+	})
+
+	return rule, nil
+}
+
+// String formats the rule back into its RRULE value.
+func (r *RecurrenceRule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		codes := make([]string, 0, len(r.ByDay))
+		for _, day := range r.ByDay {
+			for code, weekday := range weekdayCodes {
+				if weekday == day {
+					codes = append(codes, code)
+				}
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Occurrences returns the start times of the occurrences of a series starting
+// at start that fall within [from, to]. Excluded occurrences are skipped but,
+// as in RFC 5545, still count towards COUNT.
+func (r *RecurrenceRule) Occurrences(start, from, to time.Time, excluded []time.Time) []time.Time {
+	var occurrences []time.Time
+	r.each(start, func(index int, occurrence time.Time) bool {
+		if occurrence.After(to) {
+			return false
+		}
+		if !occurrence.Before(from) && !containsTime(excluded, occurrence) {
+			occurrences = append(occurrences, occurrence)
+		}
+		return true
+	})
+	return occurrences
+}
+
+// IsOccurrence reports whether t is the start of an occurrence of the series.
+func (r *RecurrenceRule) IsOccurrence(start, t time.Time) bool {
+	found := false
+	r.each(start, func(index int, occurrence time.Time) bool {
+		if occurrence.Equal(t) {
+			found = true
+		}
+		return occurrence.Before(t)
+	})
+	return found
+}
+
+// CountBefore returns how many occurrences of the series start before t.
+func (r *RecurrenceRule) CountBefore(start, t time.Time) int {
+	count := 0
+	r.each(start, func(index int, occurrence time.Time) bool {
+		if !occurrence.Before(t) {
+			return false
+		}
+		count++
+		return true
+	})
+	return count
+}
+
+// each calls fn for every occurrence in order until fn returns false or the series ends.
+func (r *RecurrenceRule) each(start time.Time, fn func(index int, occurrence time.Time) bool) {
+	index := 0
+	emit := func(occurrence time.Time) bool {
+		if occurrence.Before(start) {
+			return true
+		}
+		if r.Until != nil && occurrence.After(*r.Until) {
+			return false
+		}
+		if r.Count > 0 && index >= r.Count {
+			return false
+		}
+		index++
+		return fn(index-1, occurrence)
+	}
+
+	for period := 0; period < maxOccurrenceIterations; period++ {
+		switch r.Freq {
+		case FreqDaily:
+			if !emit(start.AddDate(0, 0, period*r.Interval)) {
+				return
+			}
+		case FreqWeekly:
+			weekStart := start.AddDate(0, 0, -mondayIndex(start.Weekday())+period*7*r.Interval)
+			days := r.ByDay
+			if len(days) == 0 {
+				days = []time.Weekday{start.Weekday()}
+			}
+			for _, day := range days {
+				if !emit(weekStart.AddDate(0, 0, mondayIndex(day))) {
+					return
+				}
+			}
+		case FreqMonthly:
+			year, month, day := start.Date()
+			hour, minute, second := start.Clock()
+			target := time.Date(year, month+time.Month(period*r.Interval), 1, hour, minute, second, start.Nanosecond(), start.Location())
+			if day > daysIn(target.Year(), target.Month()) {
+				continue
+			}
+			if !emit(target.AddDate(0, 0, day-1)) {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+func parseRuleTime(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			if layout == "20060102" {
+				t = t.Add(24*time.Hour - time.Second)
+			}
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid UNTIL value %q", value)
+}
+
+// mondayIndex returns the position of the weekday in a week starting on Monday.
+func mondayIndex(day time.Weekday) int {
+	return (int(day) + 6) % 7
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func containsTime(times []time.Time, t time.Time) bool {
+	for _, candidate := range times {
+		if candidate.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package helpers
+
+import (
+	"band-manager-backend/internal/model"
+	"fmt"
+	"sort"
+)
+
+// Reminders are sent between a minute and MaxReminderMinutes before an
+// event, at most MaxReminders times.
+const (
+	MaxReminderMinutes = 30 * 24 * 60
+	MaxReminders       = 5
+)
+
+// NormalizeReminderMinutes validates how many minutes before an event
+// reminders are sent and returns them without duplicates, earliest first.
+// Nil stays nil.
+func NormalizeReminderMinutes(minutes []int) ([]int64, error) {
+	if minutes == nil {
+		return nil, nil
+	}
+
+	seen := make(map[int]bool, len(minutes))
+	normalized := make([]int64, 0, len(minutes))
+	for _, m := range minutes {
+		if m < 1 || m > MaxReminderMinutes {
+			return nil, fmt.Errorf("reminders must be between 1 and %d minutes before the event", MaxReminderMinutes)
+		}
+		if !seen[m] {
+			seen[m] = true
+			normalized = append(normalized, int64(m))
+		}
+	}
+	if len(normalized) > MaxReminders {
+		return nil, fmt.Errorf("at most %d reminders are allowed", MaxReminders)
+	}
+
+	sort.Slice(normalized, func(i, j int) bool {
+		return normalized[i] > normalized[j]
+	})
+	return normalized, nil
+}
+
+// EventReminderMinutes returns how many minutes before an event reminders
+// are sent: those of the event or, if it has none of its own, of its group.
+func EventReminderMinutes(event *model.Event) []int {
+	values := event.ReminderMinutes
+	if values == nil {
+		values = event.Group.ReminderMinutes
+	}
+
+	minutes := make([]int, 0, len(values))
+	for _, value := range values {
+		minutes = append(minutes, int(value))
+	}
+	return minutes
+}
@@ -0,0 +1,17 @@
+package helpers
+
+const (
+	RSVPYes        = "yes"
+	RSVPNo         = "no"
+	RSVPMaybe      = "maybe"
+	RSVPUnanswered = "unanswered"
+)
+
+// RSVPStatuses lists every attendance status in display order.
+var RSVPStatuses = []string{RSVPYes, RSVPMaybe, RSVPNo, RSVPUnanswered}
+
+// IsValidRSVPResponse reports whether a member may answer with the status.
+// Unanswered is only the initial state and cannot be chosen.
+func IsValidRSVPResponse(status string) bool {
+	return status == RSVPYes || status == RSVPNo || status == RSVPMaybe
+}
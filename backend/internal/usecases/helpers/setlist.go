@@ -0,0 +1,182 @@
+package helpers
+
+import (
+	"band-manager-backend/internal/domain"
+	"band-manager-backend/internal/model"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultBreakTitle names breaks added without a title.
+const DefaultBreakTitle = "Przerwa"
+
+// Setlist export formats.
+const (
+	SetlistFormatText = "txt"
+	SetlistFormatCSV  = "csv"
+)
+
+// BuildSetlist orders the setlist entries of an event and plans their start
+// times. The first entry starts with the event unless its start is pinned;
+// every following unpinned entry starts when the previous one ends.
+func BuildSetlist(event *model.Event, performances []model.Performance) domain.Setlist {
+	sorted := sortedPerformances(performances)
+
+	setlist := domain.Setlist{
+		EventID: event.ID,
+		Entries: make([]domain.SetlistEntry, 0, len(sorted)),
+	}
+
+	next := event.Date
+	for _, performance := range sorted {
+		start := next
+		if performance.StartTime != nil {
+			start = *performance.StartTime
+		}
+		next = start.Add(time.Duration(performance.DurationSeconds) * time.Second)
+
+		setlist.Entries = append(setlist.Entries, domain.SetlistEntry{
+			ID:              performance.ID,
+			Position:        performance.Position,
+			TrackID:         performance.TrackID,
+			Title:           performanceTitle(performance),
+			IsBreak:         performance.TrackID == nil,
+			StartTime:       performance.StartTime,
+			PlannedStart:    start,
+			DurationSeconds: performance.DurationSeconds,
+			Key:             performance.Key,
+			Tempo:           performance.Tempo,
+			Notes:           performance.Notes,
+		})
+		setlist.TotalDurationSeconds += performance.DurationSeconds
+	}
+
+	if len(sorted) > 0 {
+		setlist.PlannedEnd = &next
+	}
+	return setlist
+}
+
+// SetlistSummary lists the titles of the setlist entries of an event in
+// running order. Events without a setlist list their tracks instead.
+func SetlistSummary(event *model.Event) []string {
+	if len(event.Performances) == 0 {
+		names := make([]string, 0, len(event.Tracks))
+		for _, track := range event.Tracks {
+			names = append(names, track.Name)
+		}
+		return names
+	}
+
+	sorted := sortedPerformances(event.Performances)
+	names := make([]string, 0, len(sorted))
+	for _, performance := range sorted {
+		names = append(names, performanceTitle(performance))
+	}
+	return names
+}
+
+// SetlistText renders a setlist as plain text with planned start times in
+// the time zone of the event.
+func SetlistText(event *model.Event, setlist domain.Setlist) string {
+	location := EventLocation(event)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n%s, %s\n\n", event.Title, event.Date.In(location).Format("02.01.2006"), event.Location)
+	for _, entry := range setlist.Entries {
+		fmt.Fprintf(&b, "%2d. %s  %s", entry.Position, entry.PlannedStart.In(location).Format("15:04"), entry.Title)
+		if entry.DurationSeconds > 0 {
+			fmt.Fprintf(&b, " (%s)", FormatDuration(entry.DurationSeconds))
+		}
+		if details := entryDetails(entry); details != "" {
+			fmt.Fprintf(&b, " - %s", details)
+		}
+		b.WriteString("\n")
+		if entry.Notes != "" {
+			fmt.Fprintf(&b, "      %s\n", entry.Notes)
+		}
+	}
+	fmt.Fprintf(&b, "\nCzas trwania: %s\n", FormatDuration(setlist.TotalDurationSeconds))
+	return b.String()
+}
+
+// SetlistCSV renders a setlist as CSV with planned start times in the time
+// zone of the event.
+func SetlistCSV(event *model.Event, setlist domain.Setlist) ([]byte, error) {
+	location := EventLocation(event)
+
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	records := [][]string{{"position", "planned_start", "title", "break", "duration", "key", "tempo", "notes"}}
+	for _, entry := range setlist.Entries {
+		tempo := ""
+		if entry.Tempo > 0 {
+			tempo = strconv.Itoa(entry.Tempo)
+		}
+		records = append(records, []string{
+			strconv.Itoa(entry.Position),
+			entry.PlannedStart.In(location).Format("2006-01-02 15:04"),
+			entry.Title,
+			strconv.FormatBool(entry.IsBreak),
+			FormatDuration(entry.DurationSeconds),
+			entry.Key,
+			tempo,
+			entry.Notes,
+		})
+	}
+	if err := w.WriteAll(records); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// FormatDuration formats seconds as m:ss, or h:mm:ss from an hour on.
+func FormatDuration(seconds int) string {
+	if seconds < 0 {
+		seconds = 0
+	}
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds%3600/60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+// entryDetails joins the key and tempo of an entry.
+func entryDetails(entry domain.SetlistEntry) string {
+	var details []string
+	if entry.Key != "" {
+		details = append(details, entry.Key)
+	}
+	if entry.Tempo > 0 {
+		details = append(details, fmt.Sprintf("%d BPM", entry.Tempo))
+	}
+	return strings.Join(details, ", ")
+}
+
+// performanceTitle returns the title of a setlist entry, falling back to the
+// name of its track or, for breaks, to the default break title.
+func performanceTitle(performance model.Performance) string {
+	if performance.Title != "" {
+		return performance.Title
+	}
+	if performance.TrackID == nil {
+		return DefaultBreakTitle
+	}
+	if performance.Track != nil {
+		return performance.Track.Name
+	}
+	return ""
+}
+
+func sortedPerformances(performances []model.Performance) []model.Performance {
+	sorted := append([]model.Performance(nil), performances...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Position < sorted[j].Position
+	})
+	return sorted
+}
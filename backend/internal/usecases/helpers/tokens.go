@@ -0,0 +1,34 @@
+package helpers
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// GenerateToken returns a random hex-encoded token of the given byte length.
+func GenerateToken(length int) (string, error) {
+	bytes := make([]byte, length)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+// HashToken returns the SHA-256 hex digest of a token, used to store
+// single-use secrets without keeping them in plain text.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// UnsentTokenHash returns the hash of a random token that is thrown away.
+// Token records hold it until the job emailing their link issues the real
+// token, so that tokens are never stored in job payloads.
+func UnsentTokenHash() (string, error) {
+	token, err := GenerateToken(32)
+	if err != nil {
+		return "", err
+	}
+	return HashToken(token), nil
+}
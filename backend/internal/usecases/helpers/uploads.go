@@ -0,0 +1,156 @@
+package helpers
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"path"
+	"strings"
+	"unicode"
+)
+
+// FileFormat is a format notesheet files may be uploaded in. Files are
+// stored with the content type of their detected format, whatever type the
+// client claimed.
+type FileFormat struct {
+	Name        string
+	ContentType string
+	// Extensions are the file name extensions of the format, the first
+	// being given to files named without one of them.
+	Extensions []string
+}
+
+// Formats accepted for notesheet files.
+var (
+	FormatPDF          = FileFormat{"PDF", "application/pdf", []string{".pdf"}}
+	FormatPNG          = FileFormat{"PNG", "image/png", []string{".png"}}
+	FormatJPEG         = FileFormat{"JPEG", "image/jpeg", []string{".jpg", ".jpeg"}}
+	FormatMusicXML     = FileFormat{"MusicXML", "application/vnd.recordare.musicxml+xml", []string{".musicxml", ".xml"}}
+	FormatMXL          = FileFormat{"MXL", "application/vnd.recordare.musicxml", []string{".mxl"}}
+	FormatMIDI         = FileFormat{"MIDI", "audio/midi", []string{".mid", ".midi"}}
+	FormatMuseScore    = FileFormat{"MuseScore", "application/x-musescore", []string{".mscz"}}
+	FormatMuseScoreXML = FileFormat{"MuseScore", "application/x-musescore+xml", []string{".mscx"}}
+)
+
+// Errors of files that cannot be notesheets.
+var (
+	ErrEmptyFile             = errors.New("file is empty")
+	ErrUnsupportedFileFormat = errors.New("unsupported file format, upload PDF, PNG, JPEG, MusicXML, MXL, MIDI or MuseScore files")
+)
+
+const (
+	// sniffLength is how much of a file is read to detect its format. XML
+	// files may have long prologues before their root element.
+	sniffLength = 4096
+	// maxFilenameLength limits sanitized file names, in characters.
+	maxFilenameLength = 120
+)
+
+// DetectNotesheetFormat detects the format of a notesheet file from its
+// content. Compressed MusicXML and MuseScore files are both ZIP archives and
+// are told apart by the files they contain.
+func DetectNotesheetFormat(content io.ReaderAt, size int64) (FileFormat, error) {
+	head := make([]byte, min(size, sniffLength))
+	if _, err := content.ReadAt(head, 0); err != nil && err != io.EOF {
+		return FileFormat{}, err
+	}
+	if len(head) == 0 {
+		return FileFormat{}, ErrEmptyFile
+	}
+
+	switch {
+	case bytes.HasPrefix(head, []byte("%PDF-")):
+		return FormatPDF, nil
+	case bytes.HasPrefix(head, []byte("\x89PNG\r\n\x1a\n")):
+		return FormatPNG, nil
+	case bytes.HasPrefix(head, []byte("\xff\xd8\xff")):
+		return FormatJPEG, nil
+	case bytes.HasPrefix(head, []byte("MThd")):
+		return FormatMIDI, nil
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")):
+		return detectArchiveFormat(content, size)
+	}
+
+	text := bytes.TrimLeft(bytes.TrimPrefix(head, []byte("\xef\xbb\xbf")), " \t\r\n")
+	if bytes.HasPrefix(text, []byte("<")) {
+		switch {
+		case bytes.Contains(text, []byte("<score-partwise")), bytes.Contains(text, []byte("<score-timewise")):
+			return FormatMusicXML, nil
+		case bytes.Contains(text, []byte("<museScore")):
+			return FormatMuseScoreXML, nil
+		}
+	}
+	return FileFormat{}, ErrUnsupportedFileFormat
+}
+
+// detectArchiveFormat tells compressed MuseScore files, which contain a
+// MuseScore score, from compressed MusicXML files, which contain a
+// container file pointing at the score.
+func detectArchiveFormat(content io.ReaderAt, size int64) (FileFormat, error) {
+	archive, err := zip.NewReader(content, size)
+	if err != nil {
+		return FileFormat{}, ErrUnsupportedFileFormat
+	}
+
+	container := false
+	for _, file := range archive.File {
+		if strings.HasSuffix(strings.ToLower(file.Name), ".mscx") {
+			return FormatMuseScore, nil
+		}
+		if file.Name == "META-INF/container.xml" {
+			container = true
+		}
+	}
+	if container {
+		return FormatMXL, nil
+	}
+	return FileFormat{}, ErrUnsupportedFileFormat
+}
+
+// SanitizeFilename makes a client-supplied file name safe to store and to
+// send back in downloads: directories are dropped, control and reserved
+// characters replaced, and the name shortened and given an extension of
+// the file's format.
+func SanitizeFilename(name string, format FileFormat) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+
+	var cleaned strings.Builder
+	space := false
+	for _, r := range name {
+		switch {
+		case unicode.IsSpace(r):
+			space = true
+			continue
+		case unicode.IsControl(r) || strings.ContainsRune(`/\:*?"<>|`, r):
+			r = '_'
+		}
+		if space && cleaned.Len() > 0 {
+			cleaned.WriteByte(' ')
+		}
+		space = false
+		cleaned.WriteRune(r)
+	}
+	name = strings.Trim(cleaned.String(), ". ")
+
+	base, extension := name, strings.ToLower(path.Ext(name))
+	known := false
+	for _, formatExtension := range format.Extensions {
+		if extension == formatExtension {
+			known = true
+		}
+	}
+	if known {
+		base = strings.TrimRight(strings.TrimSuffix(name, path.Ext(name)), ". ")
+	} else {
+		extension = format.Extensions[0]
+	}
+
+	if runes := []rune(base); len(runes) > maxFilenameLength {
+		base = strings.TrimRight(string(runes[:maxFilenameLength]), ". ")
+	}
+	if base == "" {
+		base = "notesheet"
+	}
+	return base + extension
+}
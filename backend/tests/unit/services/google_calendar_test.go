@@ -0,0 +1,137 @@
+package services
+
+import (
+	"band-manager-backend/internal/model"
+	"band-manager-backend/internal/services"
+	"testing"
+	"time"
+)
+
+func newGoogleCalendarService() (*services.GoogleCalendarService, *services.FakeGoogleCalendarAPI) {
+	api := services.NewFakeGoogleCalendarAPI()
+	return services.NewGoogleCalendarServiceWithAPI(api), api
+}
+
+func TestGoogleCalendarServiceLifecycle(t *testing.T) {
+	service, api := newGoogleCalendarService()
+
+	token, err := service.ExchangeCode("user-1")
+	if err != nil {
+		t.Fatalf("ExchangeCode() error = %v", err)
+	}
+	tokens := service.TokenSource(token)
+
+	warsaw, _ := time.LoadLocation("Europe/Warsaw")
+	event := &model.Event{
+		Title:    "Próba",
+		Location: "Sala prób",
+		Date:     time.Date(2025, 5, 6, 18, 0, 0, 0, warsaw),
+		EndDate:  time.Date(2025, 5, 6, 20, 30, 0, 0, warsaw),
+		TimeZone: "Europe/Warsaw",
+	}
+
+	id, err := service.CreateCalendarEvent(tokens, event)
+	if err != nil {
+		t.Fatalf("CreateCalendarEvent() error = %v", err)
+	}
+
+	created := api.Events("user-1")[id]
+	if created == nil {
+		t.Fatalf("event %q not found in calendar", id)
+	}
+	if created.Start.DateTime != "2025-05-06T18:00:00+02:00" || created.End.DateTime != "2025-05-06T20:30:00+02:00" {
+		t.Errorf("times = %s - %s, want 2025-05-06T18:00:00+02:00 - 2025-05-06T20:30:00+02:00", created.Start.DateTime, created.End.DateTime)
+	}
+	if created.Start.TimeZone != "Europe/Warsaw" {
+		t.Errorf("Start.TimeZone = %q, want Europe/Warsaw", created.Start.TimeZone)
+	}
+
+	event.Title = "Koncert"
+	if err := service.UpdateCalendarEvent(tokens, id, event); err != nil {
+		t.Fatalf("UpdateCalendarEvent() error = %v", err)
+	}
+	if summary := api.Events("user-1")[id].Summary; summary != "Koncert" {
+		t.Errorf("Summary = %q, want Koncert", summary)
+	}
+	if len(api.Events("user-2")) != 0 {
+		t.Errorf("event leaked into another user's calendar")
+	}
+
+	if err := service.DeleteCalendarEvent(tokens, id); err != nil {
+		t.Fatalf("DeleteCalendarEvent() error = %v", err)
+	}
+	if len(api.Events("user-1")) != 0 {
+		t.Errorf("event still in calendar after delete")
+	}
+	if err := service.DeleteCalendarEvent(tokens, id); err != nil {
+		t.Errorf("DeleteCalendarEvent() of deleted event error = %v", err)
+	}
+}
+
+func TestGoogleCalendarServiceEventMapping(t *testing.T) {
+	warsaw, _ := time.LoadLocation("Europe/Warsaw")
+
+	tests := []struct {
+		name           string
+		event          model.Event
+		wantStart      string
+		wantEnd        string
+		wantRecurrence []string
+	}{
+		{
+			name: "all-day",
+			event: model.Event{
+				Date:     time.Date(2025, 7, 4, 0, 0, 0, 0, warsaw),
+				EndDate:  time.Date(2025, 7, 6, 0, 0, 0, 0, warsaw),
+				AllDay:   true,
+				TimeZone: "Europe/Warsaw",
+			},
+			wantStart: "2025-07-04",
+			wantEnd:   "2025-07-07",
+		},
+		{
+			name: "recurring with exceptions",
+			event: model.Event{
+				Date:           time.Date(2025, 3, 4, 19, 0, 0, 0, warsaw),
+				EndDate:        time.Date(2025, 3, 4, 21, 0, 0, 0, warsaw),
+				TimeZone:       "Europe/Warsaw",
+				RecurrenceRule: "FREQ=WEEKLY;COUNT=10",
+				Exceptions: []model.EventException{
+					{OccurrenceDate: time.Date(2025, 4, 1, 19, 0, 0, 0, warsaw)},
+				},
+			},
+			wantStart:      "2025-03-04T19:00:00+01:00",
+			wantEnd:        "2025-03-04T21:00:00+01:00",
+			wantRecurrence: []string{"RRULE:FREQ=WEEKLY;COUNT=10", "EXDATE;TZID=Europe/Warsaw:20250401T190000"},
+		},
+	}
+
+	for _, tt := range tests {
+		service, api := newGoogleCalendarService()
+		token, _ := service.ExchangeCode("user")
+		id, err := service.CreateCalendarEvent(service.TokenSource(token), &tt.event)
+		if err != nil {
+			t.Fatalf("%s: CreateCalendarEvent() error = %v", tt.name, err)
+		}
+		created := api.Events("user")[id]
+
+		start, end := created.Start.DateTime, created.End.DateTime
+		if tt.event.AllDay {
+			start, end = created.Start.Date, created.End.Date
+		}
+		if start != tt.wantStart || end != tt.wantEnd {
+			t.Errorf("%s: times = %s - %s, want %s - %s", tt.name, start, end, tt.wantStart, tt.wantEnd)
+		}
+
+		if len(created.Recurrence) != len(tt.wantRecurrence) {
+			t.Errorf("%s: Recurrence = %v, want %v", tt.name, created.Recurrence, tt.wantRecurrence)
+			continue
+		}
+		for i := range tt.wantRecurrence {
+			if created.Recurrence[i] != tt.wantRecurrence[i] {
+				t.Errorf("%s: Recurrence = %v, want %v", tt.name, created.Recurrence, tt.wantRecurrence)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"band-manager-backend/internal/services"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func newStorages(t *testing.T) map[string]services.Storage {
+	t.Helper()
+
+	local, err := services.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStorage() error = %v", err)
+	}
+
+	server := httptest.NewServer(services.NewFakeS3Server("notesheets", "access", "secret"))
+	t.Cleanup(server.Close)
+	s3, err := services.NewS3Storage(services.S3Options{
+		Endpoint:  server.URL,
+		Bucket:    "notesheets",
+		AccessKey: "access",
+		SecretKey: "secret",
+		PathStyle: true,
+	})
+	if err != nil {
+		t.Fatalf("NewS3Storage() error = %v", err)
+	}
+
+	return map[string]services.Storage{"local": local, "s3": s3}
+}
+
+func TestStorageLifecycle(t *testing.T) {
+	ctx := context.Background()
+	key := "notesheets/1_Marsz żałobny (trąbka).pdf"
+	content := "%PDF-1.4 partia trąbki"
+
+	for name, storage := range newStorages(t) {
+		t.Run(name, func(t *testing.T) {
+			if err := storage.Put(ctx, key, strings.NewReader(content), int64(len(content)), "application/pdf"); err != nil {
+				t.Fatalf("Put() error = %v", err)
+			}
+
+			info, err := storage.Stat(ctx, key)
+			if err != nil {
+				t.Fatalf("Stat() error = %v", err)
+			}
+			if info.Size != int64(len(content)) || info.ContentType != "application/pdf" {
+				t.Errorf("Stat() = %+v, want size %d and type application/pdf", info, len(content))
+			}
+
+			body, err := storage.Get(ctx, key)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			data, _ := io.ReadAll(body)
+			body.Close()
+			if string(data) != content {
+				t.Errorf("Get() = %q, want %q", data, content)
+			}
+
+			body, err = storage.GetRange(ctx, key, 9, 6)
+			if err != nil {
+				t.Fatalf("GetRange() error = %v", err)
+			}
+			data, _ = io.ReadAll(body)
+			body.Close()
+			if string(data) != "partia" {
+				t.Errorf("GetRange() = %q, want %q", data, "partia")
+			}
+
+			if err := storage.Delete(ctx, key); err != nil {
+				t.Fatalf("Delete() error = %v", err)
+			}
+			if _, err := storage.Stat(ctx, key); !errors.Is(err, services.ErrObjectNotFound) {
+				t.Errorf("Stat() after Delete() error = %v, want ErrObjectNotFound", err)
+			}
+			if _, err := storage.Get(ctx, key); !errors.Is(err, services.ErrObjectNotFound) {
+				t.Errorf("Get() after Delete() error = %v, want ErrObjectNotFound", err)
+			}
+			if err := storage.Delete(ctx, key); err != nil {
+				t.Errorf("Delete() of missing object error = %v", err)
+			}
+		})
+	}
+}
+
+func TestStorageList(t *testing.T) {
+	ctx := context.Background()
+	keys := []string{"blobs/ab/abc", "blobs/cd/cde", "1_part.pdf"}
+
+	for name, storage := range newStorages(t) {
+		t.Run(name, func(t *testing.T) {
+			for _, key := range keys {
+				if err := storage.Put(ctx, key, strings.NewReader(key), int64(len(key)), ""); err != nil {
+					t.Fatalf("Put(%q) error = %v", key, err)
+				}
+			}
+
+			tests := []struct {
+				prefix string
+				want   []string
+			}{
+				{"", []string{"1_part.pdf", "blobs/ab/abc", "blobs/cd/cde"}},
+				{"blobs/", []string{"blobs/ab/abc", "blobs/cd/cde"}},
+				{"notesheets/", nil},
+			}
+
+			for _, tt := range tests {
+				objects, err := storage.List(ctx, tt.prefix)
+				if err != nil {
+					t.Fatalf("List(%q) error = %v", tt.prefix, err)
+				}
+				var got []string
+				for _, object := range objects {
+					got = append(got, object.Key)
+					if object.Size != int64(len(object.Key)) || object.ModTime.IsZero() {
+						t.Errorf("List(%q) object = %+v", tt.prefix, object)
+					}
+				}
+				sort.Strings(got)
+				if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+					t.Errorf("List(%q) = %v, want %v", tt.prefix, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestStorageRejectsKeysOutsideRoot(t *testing.T) {
+	keys := []string{"", "/etc/passwd", "../secret.pdf", "notesheets/../../secret.pdf", "notesheets//part.pdf"}
+
+	for name, storage := range newStorages(t) {
+		for _, key := range keys {
+			if err := storage.Put(context.Background(), key, strings.NewReader("x"), 1, ""); err == nil {
+				t.Errorf("%s: Put(%q) succeeded, want error", name, key)
+			}
+		}
+	}
+}
+
+func TestServeObjectRange(t *testing.T) {
+	ctx := context.Background()
+	content := strings.Repeat("0123456789", 100)
+
+	for name, storage := range newStorages(t) {
+		t.Run(name, func(t *testing.T) {
+			if err := storage.Put(ctx, "part.pdf", strings.NewReader(content), -1, "application/pdf"); err != nil {
+				t.Fatalf("Put() error = %v", err)
+			}
+
+			tests := []struct {
+				name       string
+				rangeValue string
+				wantStatus int
+				wantBody   string
+			}{
+				{"whole file", "", http.StatusOK, content},
+				{"middle", "bytes=10-14", http.StatusPartialContent, "01234"},
+				{"suffix", "bytes=-3", http.StatusPartialContent, "789"},
+				{"beyond the end", "bytes=2000-", http.StatusRequestedRangeNotSatisfiable, ""},
+			}
+
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					object, err := services.OpenObject(ctx, storage, "part.pdf")
+					if err != nil {
+						t.Fatalf("OpenObject() error = %v", err)
+					}
+					defer object.Close()
+
+					req := httptest.NewRequest(http.MethodGet, "/part.pdf", nil)
+					if tt.rangeValue != "" {
+						req.Header.Set("Range", tt.rangeValue)
+					}
+					rec := httptest.NewRecorder()
+					http.ServeContent(rec, req, "part.pdf", object.Info.ModTime, object)
+
+					if rec.Code != tt.wantStatus {
+						t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+					}
+					if tt.wantBody != "" && rec.Body.String() != tt.wantBody {
+						t.Errorf("body = %q, want %q", rec.Body.String(), tt.wantBody)
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestS3StorageSignsRequests(t *testing.T) {
+	server := httptest.NewServer(services.NewFakeS3Server("notesheets", "access", "secret"))
+	defer server.Close()
+
+	storage, err := services.NewS3Storage(services.S3Options{
+		Endpoint:  server.URL,
+		Bucket:    "notesheets",
+		AccessKey: "access",
+		SecretKey: "wrong",
+		PathStyle: true,
+	})
+	if err != nil {
+		t.Fatalf("NewS3Storage() error = %v", err)
+	}
+
+	err = storage.Put(context.Background(), "part.pdf", strings.NewReader("x"), 1, "")
+	if err == nil || !strings.Contains(err.Error(), "SignatureDoesNotMatch") {
+		t.Errorf("Put() with wrong secret error = %v, want SignatureDoesNotMatch", err)
+	}
+}
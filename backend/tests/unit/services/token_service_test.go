@@ -0,0 +1,96 @@
+package services
+
+import (
+	"band-manager-backend/internal/config"
+	"band-manager-backend/internal/services"
+	"testing"
+	"time"
+)
+
+func newTokenService(ttl time.Duration) *services.TokenService {
+	return services.NewTokenService(&config.Config{
+		AuthConfig: &config.AuthConfig{
+			JWTSecret:       "test-secret",
+			AccessTokenTTL:  ttl,
+			RefreshTokenTTL: ttl,
+		},
+	})
+}
+
+func TestTokenServiceVerify(t *testing.T) {
+	service := newTokenService(time.Minute)
+
+	accessToken, err := service.IssueAccessToken(42, 7)
+	if err != nil {
+		t.Fatalf("IssueAccessToken() error = %v", err)
+	}
+
+	refreshToken, err := service.IssueRefreshToken(42, 7, "refresh-1")
+	if err != nil {
+		t.Fatalf("IssueRefreshToken() error = %v", err)
+	}
+
+	expired, _ := newTokenService(-time.Minute).IssueAccessToken(42, 7)
+	foreign, _ := services.NewTokenService(&config.Config{
+		AuthConfig: &config.AuthConfig{JWTSecret: "other-secret", AccessTokenTTL: time.Minute},
+	}).IssueAccessToken(42, 7)
+
+	tests := []struct {
+		name      string
+		token     string
+		tokenType string
+		tokenID   string
+		wantErr   bool
+	}{
+		{
+			name:      "should accept valid access token",
+			token:     accessToken,
+			tokenType: services.TokenTypeAccess,
+		},
+		{
+			name:      "should accept valid refresh token",
+			token:     refreshToken,
+			tokenType: services.TokenTypeRefresh,
+			tokenID:   "refresh-1",
+		},
+		{
+			name:      "should reject refresh token used as access token",
+			token:     refreshToken,
+			tokenType: services.TokenTypeAccess,
+			wantErr:   true,
+		},
+		{
+			name:      "should reject expired token",
+			token:     expired,
+			tokenType: services.TokenTypeAccess,
+			wantErr:   true,
+		},
+		{
+			name:      "should reject token signed with another secret",
+			token:     foreign,
+			tokenType: services.TokenTypeAccess,
+			wantErr:   true,
+		},
+		{
+			name:      "should reject malformed token",
+			token:     "not-a-token",
+			tokenType: services.TokenTypeAccess,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := service.Verify(tt.token, tt.tokenType)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (claims.UserID != 42 || claims.SessionID != 7) {
+				t.Errorf("Verify() = user %d session %d, want user 42 session 7", claims.UserID, claims.SessionID)
+			}
+			if !tt.wantErr && claims.TokenID != tt.tokenID {
+				t.Errorf("Verify() token ID = %q, want %q", claims.TokenID, tt.tokenID)
+			}
+		})
+	}
+}
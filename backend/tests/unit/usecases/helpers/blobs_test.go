@@ -0,0 +1,54 @@
+package helpers
+
+import (
+	"band-manager-backend/internal/usecases/helpers"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestHashContent(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		wantHash string
+	}{
+		{"empty", "", "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+		{"text", "abc", "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := strings.NewReader(tt.content)
+			content.Seek(1, io.SeekStart)
+
+			hash, size, err := helpers.HashContent(content)
+			if err != nil {
+				t.Fatalf("HashContent() error = %v", err)
+			}
+			if hash != tt.wantHash || size != int64(len(tt.content)) {
+				t.Errorf("HashContent() = %s, %d, want %s, %d", hash, size, tt.wantHash, len(tt.content))
+			}
+
+			rest, _ := io.ReadAll(content)
+			if string(rest) != tt.content {
+				t.Errorf("content after HashContent() = %q, want it rewound", rest)
+			}
+		})
+	}
+}
+
+func TestBlobKey(t *testing.T) {
+	hash := "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+	key := helpers.BlobKey(hash)
+
+	if key != "blobs/ba/"+hash {
+		t.Errorf("BlobKey() = %q", key)
+	}
+	if !helpers.IsBlobKey(key) {
+		t.Errorf("IsBlobKey(%q) = false", key)
+	}
+	if helpers.IsBlobKey("notesheets/1_part.pdf") || helpers.IsBlobKey("1_part.pdf") {
+		t.Errorf("IsBlobKey() = true for a file stored before blobs")
+	}
+}
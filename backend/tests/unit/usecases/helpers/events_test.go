@@ -0,0 +1,99 @@
+package helpers
+
+import (
+	"band-manager-backend/internal/model"
+	"band-manager-backend/internal/usecases/helpers"
+	"testing"
+	"time"
+)
+
+func TestIsValidEventType(t *testing.T) {
+	tests := []struct {
+		eventType string
+		expected  bool
+	}{
+		{helpers.EventTypeRehearsal, true},
+		{helpers.EventTypeConcert, true},
+		{helpers.EventTypeRecording, true},
+		{helpers.EventTypeMeeting, true},
+		{"", false},
+		{"party", false},
+	}
+
+	for _, tt := range tests {
+		if result := helpers.IsValidEventType(tt.eventType); result != tt.expected {
+			t.Errorf("IsValidEventType(%q) = %v, want %v", tt.eventType, result, tt.expected)
+		}
+	}
+}
+
+func TestLoadTimeZone(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{"Europe/Warsaw", false},
+		{"America/New_York", false},
+		{"UTC", false},
+		{"", true},
+		{"Local", true},
+		{"Mars/Olympus_Mons", true},
+	}
+
+	for _, tt := range tests {
+		if _, err := helpers.LoadTimeZone(tt.name); (err != nil) != tt.wantErr {
+			t.Errorf("LoadTimeZone(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestEventEnd(t *testing.T) {
+	warsaw, _ := helpers.LoadTimeZone("Europe/Warsaw")
+	start := time.Date(2025, 3, 29, 0, 0, 0, 0, warsaw)
+
+	tests := []struct {
+		name     string
+		event    model.Event
+		expected time.Time
+	}{
+		{
+			name:     "timed",
+			event:    model.Event{Date: start, EndDate: start.Add(90 * time.Minute), TimeZone: "Europe/Warsaw"},
+			expected: start.Add(90 * time.Minute),
+		},
+		{
+			name:     "timed without end",
+			event:    model.Event{Date: start, TimeZone: "Europe/Warsaw"},
+			expected: start.Add(helpers.DefaultEventDuration),
+		},
+		{
+			name:     "all-day across daylight saving time change",
+			event:    model.Event{Date: start, EndDate: start.AddDate(0, 0, 1), AllDay: true, TimeZone: "Europe/Warsaw"},
+			expected: time.Date(2025, 3, 31, 0, 0, 0, 0, warsaw),
+		},
+	}
+
+	for _, tt := range tests {
+		if result := helpers.EventEnd(&tt.event); !result.Equal(tt.expected) {
+			t.Errorf("%s: EventEnd() = %v, want %v", tt.name, result, tt.expected)
+		}
+	}
+}
+
+func TestOccurrenceEnd(t *testing.T) {
+	warsaw, _ := helpers.LoadTimeZone("Europe/Warsaw")
+	start := time.Date(2025, 3, 27, 0, 0, 0, 0, warsaw)
+	allDay := model.Event{Date: start, EndDate: start.AddDate(0, 0, 2), AllDay: true, TimeZone: "Europe/Warsaw"}
+
+	occurrence := start.AddDate(0, 0, 7)
+	expected := time.Date(2025, 4, 5, 0, 0, 0, 0, warsaw)
+	if result := helpers.OccurrenceEnd(&allDay, occurrence); !result.Equal(expected) {
+		t.Errorf("OccurrenceEnd() = %v, want %v", result, expected)
+	}
+
+	timed := model.Event{Date: start.Add(19 * time.Hour), EndDate: start.Add(21 * time.Hour), TimeZone: "Europe/Warsaw"}
+	occurrence = timed.Date.AddDate(0, 0, 7)
+	if result := helpers.OccurrenceEnd(&timed, occurrence); !result.Equal(occurrence.Add(2 * time.Hour)) {
+		t.Errorf("OccurrenceEnd() = %v, want %v", result, occurrence.Add(2*time.Hour))
+	}
+}
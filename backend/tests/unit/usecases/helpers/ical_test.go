@@ -0,0 +1,107 @@
+package helpers
+
+import (
+	"band-manager-backend/internal/model"
+	"band-manager-backend/internal/usecases/helpers"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestICalendar(t *testing.T) {
+	warsaw, _ := helpers.LoadTimeZone("Europe/Warsaw")
+	start := time.Date(2025, 5, 5, 19, 0, 0, 0, warsaw)
+	seriesID := uint(1)
+	cancelled := start.AddDate(0, 0, 7)
+	moved := start.AddDate(0, 0, 14)
+	march := &model.Track{ID: 1, Name: "Marsz"}
+	waltz := &model.Track{ID: 2, Name: "Walc"}
+
+	events := []*model.Event{
+		{
+			ID:             1,
+			Title:          "Próba, sekcja dęta",
+			Location:       "Sala 2; piętro 1",
+			Description:    "Przynieście nuty",
+			Type:           helpers.EventTypeRehearsal,
+			Date:           start,
+			EndDate:        start.Add(2 * time.Hour),
+			TimeZone:       "Europe/Warsaw",
+			RecurrenceRule: "FREQ=WEEKLY",
+			Exceptions: []model.EventException{
+				{OccurrenceDate: cancelled},
+				{OccurrenceDate: moved},
+			},
+			Tracks: []*model.Track{march, waltz},
+			Performances: []model.Performance{
+				{TrackID: &march.ID, Track: march, Position: 3},
+				{Position: 2},
+				{TrackID: &waltz.ID, Track: waltz, Position: 1},
+			},
+		},
+		{
+			ID:           2,
+			Title:        "Próba przeniesiona",
+			Date:         moved.Add(time.Hour),
+			EndDate:      moved.Add(3 * time.Hour),
+			TimeZone:     "Europe/Warsaw",
+			SeriesID:     &seriesID,
+			OriginalDate: &moved,
+		},
+		{
+			ID:       3,
+			Title:    "Koncert",
+			Date:     time.Date(2025, 6, 1, 0, 0, 0, 0, warsaw),
+			EndDate:  time.Date(2025, 6, 2, 0, 0, 0, 0, warsaw),
+			AllDay:   true,
+			TimeZone: "Europe/Warsaw",
+		},
+	}
+
+	calendar := helpers.ICalendar("Orkiestra", events, time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC))
+
+	expected := []string{
+		"BEGIN:VCALENDAR\r\n",
+		"X-WR-CALNAME:Orkiestra\r\n",
+		"UID:event-1@band-manager\r\nDTSTAMP:20250501T120000Z\r\nDTSTART;TZID=Europe/Warsaw:20250505T190000\r\n",
+		"DTEND;TZID=Europe/Warsaw:20250505T210000\r\n",
+		"RRULE:FREQ=WEEKLY\r\n",
+		"EXDATE;TZID=Europe/Warsaw:20250512T190000\r\n",
+		"SUMMARY:Próba\\, sekcja dęta\r\n",
+		"LOCATION:Sala 2\\; piętro 1\r\n",
+		"DESCRIPTION:Przynieście nuty\\n\\nSetlista:\\n1. Walc\\n2. Przerwa\\n3. Marsz\r\n",
+		"UID:event-1@band-manager\r\nRECURRENCE-ID;TZID=Europe/Warsaw:20250519T190000\r\n",
+		"DTSTART;VALUE=DATE:20250601\r\nDTEND;VALUE=DATE:20250603\r\n",
+		"END:VCALENDAR\r\n",
+	}
+	for _, fragment := range expected {
+		if !strings.Contains(calendar, fragment) {
+			t.Errorf("calendar does not contain %q:\n%s", fragment, calendar)
+		}
+	}
+
+	if strings.Contains(calendar, "EXDATE;TZID=Europe/Warsaw:20250519T190000") {
+		t.Errorf("occurrence replaced by an override must not be excluded:\n%s", calendar)
+	}
+}
+
+func TestICalendarFoldsLongLines(t *testing.T) {
+	event := &model.Event{
+		ID:          1,
+		Title:       "Koncert",
+		Description: strings.Repeat("żółć ", 40),
+		Date:        time.Date(2025, 5, 5, 19, 0, 0, 0, time.UTC),
+		TimeZone:    "UTC",
+	}
+
+	calendar := helpers.ICalendar("Orkiestra", []*model.Event{event}, time.Now())
+
+	for _, line := range strings.Split(strings.TrimSuffix(calendar, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line longer than 75 octets: %q", line)
+		}
+		if strings.ToValidUTF8(line, "") != line {
+			t.Errorf("line splits a multi-byte character: %q", line)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package helpers
+
+import (
+	"band-manager-backend/internal/model"
+	"band-manager-backend/internal/usecases/helpers"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeInstrument(t *testing.T) {
+	tests := []struct {
+		name       string
+		instrument model.Instrument
+		want       model.Instrument
+		wantErr    bool
+	}{
+		{
+			name:       "defaults",
+			instrument: model.Instrument{Name: "  Tuba "},
+			want:       model.Instrument{Name: "Tuba", Family: "other", Transposition: "C", Clef: "treble"},
+		},
+		{
+			name:       "canonical forms",
+			instrument: model.Instrument{Name: "Klarnet B", Family: "Woodwind", Transposition: "bb", Clef: " TREBLE "},
+			want:       model.Instrument{Name: "Klarnet B", Family: "woodwind", Transposition: "Bb", Clef: "treble"},
+		},
+		{
+			name:       "flat sign",
+			instrument: model.Instrument{Name: "Saksofon altowy", Family: "woodwind", Transposition: "E♭"},
+			want:       model.Instrument{Name: "Saksofon altowy", Family: "woodwind", Transposition: "Eb", Clef: "treble"},
+		},
+		{
+			name:       "bass clef",
+			instrument: model.Instrument{Name: "Puzon", Family: "brass", Clef: "bass"},
+			want:       model.Instrument{Name: "Puzon", Family: "brass", Transposition: "C", Clef: "bass"},
+		},
+		{name: "missing name", instrument: model.Instrument{Name: " "}, wantErr: true},
+		{name: "long name", instrument: model.Instrument{Name: strings.Repeat("a", 101)}, wantErr: true},
+		{name: "unknown family", instrument: model.Instrument{Name: "Theremin", Family: "electronic"}, wantErr: true},
+		{name: "unknown transposition", instrument: model.Instrument{Name: "Róg", Transposition: "H"}, wantErr: true},
+		{name: "unknown clef", instrument: model.Instrument{Name: "Altówka", Clef: "soprano"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instrument := tt.instrument
+			err := helpers.NormalizeInstrument(&instrument)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NormalizeInstrument() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if instrument.Name != tt.want.Name || instrument.Family != tt.want.Family ||
+				instrument.Transposition != tt.want.Transposition || instrument.Clef != tt.want.Clef {
+				t.Errorf("NormalizeInstrument() = %+v, want %+v", instrument, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package helpers
+
+import (
+	"band-manager-backend/internal/usecases/helpers"
+	"testing"
+	"time"
+)
+
+func TestJobBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{0, 30 * time.Second},
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{8, 64 * time.Minute},
+		{10, 256 * time.Minute},
+		{11, 6 * time.Hour},
+		{1000, 6 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		if result := helpers.JobBackoff(tt.attempts); result != tt.expected {
+			t.Errorf("JobBackoff(%d) = %v, want %v", tt.attempts, result, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package helpers
+
+import (
+	"band-manager-backend/internal/usecases/helpers"
+	"net"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	_, proxies, _ := net.ParseCIDR("10.0.0.0/8")
+	trusted := []*net.IPNet{proxies}
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor string
+		expected     string
+	}{
+		{"direct request", "203.0.113.7:51234", "", "203.0.113.7"},
+		{"forged header from untrusted client", "203.0.113.7:51234", "198.51.100.1", "203.0.113.7"},
+		{"trusted proxy", "10.0.0.2:443", "198.51.100.1", "198.51.100.1"},
+		{"forged entry before proxy", "10.0.0.2:443", "1.2.3.4, 198.51.100.1", "198.51.100.1"},
+		{"chain of trusted proxies", "10.0.0.2:443", "198.51.100.1, 10.0.0.5", "198.51.100.1"},
+		{"invalid entry", "10.0.0.2:443", "198.51.100.1, garbage", "10.0.0.2"},
+		{"trusted proxy without header", "10.0.0.2:443", "", "10.0.0.2"},
+		{"ipv6 client", "[2001:db8::1]:443", "", "2001:db8::1"},
+		{"address without port", "203.0.113.7", "", "203.0.113.7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := helpers.ClientIP(tt.remoteAddr, tt.forwardedFor, trusted); result != tt.expected {
+				t.Errorf("ClientIP(%q, %q) = %q, want %q", tt.remoteAddr, tt.forwardedFor, result, tt.expected)
+			}
+		})
+	}
+
+	if result := helpers.ClientIP("10.0.0.2:443", "198.51.100.1", nil); result != "10.0.0.2" {
+		t.Errorf("ClientIP() without trusted proxies = %q, want %q", result, "10.0.0.2")
+	}
+}
@@ -0,0 +1,147 @@
+package helpers
+
+import (
+	"band-manager-backend/internal/domain"
+	"band-manager-backend/internal/model"
+	"band-manager-backend/internal/usecases/helpers"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTextWidth(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		font helpers.PDFFont
+		want float64
+	}{
+		{"regular", "Walc", helpers.FontRegular, 10 * (944 + 556 + 222 + 500) / 1000.0},
+		{"bold", "Walc", helpers.FontBold, 10 * (944 + 556 + 278 + 556) / 1000.0},
+		{"accented letters are as wide as their base", "Łąka", helpers.FontRegular, 10 * (556 + 556 + 500 + 556) / 1000.0},
+		{"unknown characters print as question marks", "♪", helpers.FontRegular, 10 * 556 / 1000.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := helpers.TextWidth(tt.text, tt.font, 10); got != tt.want {
+				t.Errorf("TextWidth(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	width := helpers.TextWidth("Marsz Radetzky", helpers.FontRegular, 10)
+
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"fits", "Marsz", []string{"Marsz"}},
+		{"breaks at spaces", "Marsz Radetzky op. 228", []string{"Marsz Radetzky", "op. 228"}},
+		{"keeps line breaks", "Marsz\nWalc", []string{"Marsz", "Walc"}},
+		{"breaks long words", "Marszmarszmarszmarsz", []string{"Marszmarszmar", "szmarsz"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := helpers.WrapText(tt.text, helpers.FontRegular, 10, width)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("WrapText(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventPDFs(t *testing.T) {
+	warsaw, _ := helpers.LoadTimeZone("Europe/Warsaw")
+	start := time.Date(2025, 6, 1, 18, 0, 0, 0, warsaw)
+	event := &model.Event{
+		ID:          7,
+		Title:       "Koncert (plenerowy)",
+		Location:    "Rynek Główny",
+		Description: "Zbiórka o 17:00.",
+		Date:        start,
+		EndDate:     start.Add(2 * time.Hour),
+		TimeZone:    "Europe/Warsaw",
+		Group:       model.Group{Name: "Orkiestra Dęta"},
+	}
+	waltz := &model.Track{ID: 2, Name: "Walc"}
+	performances := []model.Performance{
+		{ID: 1, TrackID: &waltz.ID, Track: waltz, Position: 1, DurationSeconds: 245, Key: "D-dur", Tempo: 90},
+		{ID: 2, Position: 2, DurationSeconds: 600},
+	}
+	for i := 3; i <= 60; i++ {
+		performances = append(performances, model.Performance{ID: uint(i), TrackID: &waltz.ID, Track: waltz, Position: i, DurationSeconds: 180})
+	}
+	setlist := helpers.BuildSetlist(event, performances)
+	notes := []domain.SubgroupNote{{SubgroupID: 1, SubgroupName: "Trąbki", Notes: "Stroje galowe."}}
+
+	tests := []struct {
+		name      string
+		data      []byte
+		fragments []string
+	}{
+		{
+			"stage setlist",
+			helpers.StageSetlistPDF(event, setlist),
+			[]string{`(Koncert \(plenerowy\))`, "(Walc)", "(D-dur, 90 BPM)", "(\\241 Przerwa \\241)"},
+		},
+		{
+			"programme",
+			helpers.EventProgrammePDF(event, setlist, notes),
+			[]string{"(Orkiestra D\\202ta)", "(01.06.2025, 18:00 \\240 20:00)", "(Rynek G\\203\\205wny)", "(18:04)", "(Tr\\200bki)", "(Strona 1 z 2)", "(Strona 2 z 2)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkPDFStructure(t, tt.data)
+			for _, fragment := range tt.fragments {
+				if !bytes.Contains(tt.data, []byte(fragment)) {
+					t.Errorf("PDF does not contain %q", fragment)
+				}
+			}
+		})
+	}
+}
+
+// checkPDFStructure checks that the cross-reference table points at the
+// objects and that stream lengths are right.
+func checkPDFStructure(t *testing.T, data []byte) {
+	t.Helper()
+
+	if !bytes.HasPrefix(data, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(data, []byte("%%EOF\n")) {
+		t.Fatalf("PDF has no header or trailer")
+	}
+
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(data)
+	if startxref == nil {
+		t.Fatalf("PDF has no startxref")
+	}
+	xref, _ := strconv.Atoi(string(startxref[1]))
+	if !bytes.HasPrefix(data[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref %d does not point at the cross-reference table", xref)
+	}
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(data[xref:], -1)
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		if header := fmt.Sprintf("%d 0 obj\n", i+1); !bytes.HasPrefix(data[offset:], []byte(header)) {
+			t.Errorf("object %d is not at offset %d", i+1, offset)
+		}
+	}
+
+	for _, stream := range regexp.MustCompile(`(?s)<< /Length (\d+) >>\nstream\n(.*?)endstream`).FindAllSubmatch(data, -1) {
+		length, _ := strconv.Atoi(string(stream[1]))
+		if length != len(stream[2]) {
+			t.Errorf("stream length %d, want %d", length, len(stream[2]))
+		}
+	}
+}
@@ -1,49 +1,230 @@
 package helpers
 
 import (
+	"band-manager-backend/internal/model"
 	"band-manager-backend/internal/usecases/helpers"
+	"reflect"
 	"testing"
+
+	"github.com/lib/pq"
 )
 
-func TestIsManagerOrModeratorRole(t *testing.T) {
+func TestRoleHasPermission(t *testing.T) {
+	moderatorDenied := map[helpers.Permission]bool{
+		helpers.PermEventConflictsForce:   true,
+		helpers.PermSubgroupMembersRemove: true,
+		helpers.PermSubgroupLeadersAssign: true,
+		helpers.PermMemberInvite:          true,
+		helpers.PermGroupSettingsUpdate:   true,
+		helpers.PermMemberRoleUpdate:      true,
+		helpers.PermTokenView:             true,
+		helpers.PermTokenRefresh:          true,
+		helpers.PermRolesManage:           true,
+	}
+
 	tests := []struct {
 		name     string
 		role     string
-		expected bool
+		expected func(helpers.Permission) bool
 	}{
 		{
-			name:     "should return true for manager role",
+			name:     "manager has every permission",
 			role:     helpers.RoleManager,
-			expected: true,
+			expected: func(helpers.Permission) bool { return true },
 		},
 		{
-			name:     "should return true for moderator role",
+			name:     "moderator manages content but not roles or token",
 			role:     helpers.RoleModerator,
-			expected: true,
+			expected: func(p helpers.Permission) bool { return !moderatorDenied[p] },
 		},
 		{
-			name:     "should return false for member role",
+			name:     "member has no permissions",
 			role:     helpers.RoleMember,
-			expected: false,
+			expected: func(helpers.Permission) bool { return false },
 		},
 		{
-			name:     "should return false for empty role",
+			name:     "empty role has no permissions",
 			role:     "",
-			expected: false,
+			expected: func(helpers.Permission) bool { return false },
 		},
 		{
-			name:     "should return false for invalid role",
+			name:     "unknown role has no permissions",
 			role:     "invalid_role",
-			expected: false,
+			expected: func(helpers.Permission) bool { return false },
+		},
+	}
+
+	for _, tt := range tests {
+		for _, permission := range helpers.AllPermissions {
+			t.Run(tt.name+"/"+string(permission), func(t *testing.T) {
+				result := helpers.RoleHasPermission(tt.role, permission)
+				if want := tt.expected(permission); result != want {
+					t.Errorf("RoleHasPermission(%q, %q) = %v, want %v",
+						tt.role, permission, result, want)
+				}
+			})
+		}
+	}
+}
+
+func TestIsBuiltInRole(t *testing.T) {
+	tests := []struct {
+		role     string
+		expected bool
+	}{
+		{helpers.RoleManager, true},
+		{helpers.RoleModerator, true},
+		{helpers.RoleMember, true},
+		{"", false},
+		{"section_leader", false},
+	}
+
+	for _, tt := range tests {
+		if result := helpers.IsBuiltInRole(tt.role); result != tt.expected {
+			t.Errorf("IsBuiltInRole(%q) = %v, want %v", tt.role, result, tt.expected)
+		}
+	}
+}
+
+func TestIsValidPermission(t *testing.T) {
+	for _, permission := range helpers.AllPermissions {
+		if !helpers.IsValidPermission(permission) {
+			t.Errorf("IsValidPermission(%q) = false, want true", permission)
+		}
+	}
+
+	for _, permission := range []helpers.Permission{"", "track", "track.create.all"} {
+		if helpers.IsValidPermission(permission) {
+			t.Errorf("IsValidPermission(%q) = true, want false", permission)
+		}
+	}
+}
+
+func TestIsValidSubgroupRole(t *testing.T) {
+	tests := []struct {
+		role     string
+		expected bool
+	}{
+		{helpers.SubgroupRoleLeader, true},
+		{helpers.SubgroupRoleMember, true},
+		{helpers.RoleManager, false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if result := helpers.IsValidSubgroupRole(tt.role); result != tt.expected {
+			t.Errorf("IsValidSubgroupRole(%q) = %v, want %v", tt.role, result, tt.expected)
+		}
+	}
+}
+
+func TestResolveRolePermissions(t *testing.T) {
+	librarian := &model.CustomRole{
+		Name:        "librarian",
+		Permissions: pq.StringArray{string(helpers.PermTrackCreate), string(helpers.PermNotesheetUpload)},
+	}
+
+	tests := []struct {
+		name       string
+		role       string
+		customRole *model.CustomRole
+		expected   []helpers.Permission
+	}{
+		{
+			name:       "custom role grants its permissions",
+			role:       "librarian",
+			customRole: librarian,
+			expected:   []helpers.Permission{helpers.PermTrackCreate, helpers.PermNotesheetUpload},
+		},
+		{
+			name: "unknown stored permissions grant nothing",
+			role: "section_leader",
+			customRole: &model.CustomRole{
+				Name:        "section_leader",
+				Permissions: pq.StringArray{"group.delete", string(helpers.PermEventCreate)},
+			},
+			expected: []helpers.Permission{helpers.PermEventCreate},
+		},
+		{
+			name:       "custom role without permissions",
+			role:       "guest",
+			customRole: &model.CustomRole{Name: "guest"},
+			expected:   []helpers.Permission{},
+		},
+		{
+			name:       "missing custom role grants nothing",
+			role:       "librarian",
+			customRole: nil,
+			expected:   nil,
+		},
+		{
+			name:       "built-in role cannot be shadowed",
+			role:       helpers.RoleMember,
+			customRole: &model.CustomRole{Name: helpers.RoleMember, Permissions: pq.StringArray{string(helpers.PermRolesManage)}},
+			expected:   helpers.BuiltInRolePermissions[helpers.RoleMember],
+		},
+		{
+			name:       "built-in role without custom role",
+			role:       helpers.RoleModerator,
+			customRole: nil,
+			expected:   helpers.BuiltInRolePermissions[helpers.RoleModerator],
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := helpers.ResolveRolePermissions(tt.role, tt.customRole)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("ResolveRolePermissions(%q) = %v, want %v", tt.role, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMissingPermissions(t *testing.T) {
+	tests := []struct {
+		name     string
+		held     []helpers.Permission
+		granted  []helpers.Permission
+		expected []helpers.Permission
+	}{
+		{
+			name:     "moderator cannot approve with a custom role holding roles.manage",
+			held:     helpers.BuiltInRolePermissions[helpers.RoleModerator],
+			granted:  []helpers.Permission{helpers.PermTrackCreate, helpers.PermRolesManage},
+			expected: []helpers.Permission{helpers.PermRolesManage},
+		},
+		{
+			name:     "moderator can grant a weaker custom role",
+			held:     helpers.BuiltInRolePermissions[helpers.RoleModerator],
+			granted:  []helpers.Permission{helpers.PermTrackCreate, helpers.PermEventCreate},
+			expected: nil,
+		},
+		{
+			name:     "inviter cannot grant moderator",
+			held:     []helpers.Permission{helpers.PermMemberInvite},
+			granted:  helpers.BuiltInRolePermissions[helpers.RoleModerator],
+			expected: helpers.BuiltInRolePermissions[helpers.RoleModerator],
+		},
+		{
+			name:     "manager can grant every role",
+			held:     helpers.BuiltInRolePermissions[helpers.RoleManager],
+			granted:  helpers.AllPermissions,
+			expected: nil,
+		},
+		{
+			name:     "anyone can grant member",
+			held:     nil,
+			granted:  helpers.BuiltInRolePermissions[helpers.RoleMember],
+			expected: nil,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := helpers.IsManagerOrModeratorRole(tt.role)
-			if result != tt.expected {
-				t.Errorf("IsManagerOrModeratorRole(%s) = %v, want %v",
-					tt.role, result, tt.expected)
+			result := helpers.MissingPermissions(tt.held, tt.granted)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("MissingPermissions() = %v, want %v", result, tt.expected)
 			}
 		})
 	}
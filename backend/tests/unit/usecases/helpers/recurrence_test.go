@@ -0,0 +1,148 @@
+package helpers
+
+import (
+	"band-manager-backend/internal/usecases/helpers"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day, hour int) time.Time {
+	return time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+}
+
+func TestParseRecurrenceRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{name: "weekly with days", value: "FREQ=WEEKLY;BYDAY=TH,TU;COUNT=4", want: "FREQ=WEEKLY;BYDAY=TU,TH;COUNT=4"},
+		{name: "rrule prefix", value: "RRULE:FREQ=DAILY;INTERVAL=2", want: "FREQ=DAILY;INTERVAL=2"},
+		{name: "until date", value: "FREQ=MONTHLY;UNTIL=20250301", want: "FREQ=MONTHLY;UNTIL=20250301T235959Z"},
+		{name: "missing freq", value: "INTERVAL=2", wantErr: true},
+		{name: "unsupported freq", value: "FREQ=YEARLY", wantErr: true},
+		{name: "count and until", value: "FREQ=DAILY;COUNT=2;UNTIL=20250101", wantErr: true},
+		{name: "byday on daily", value: "FREQ=DAILY;BYDAY=MO", wantErr: true},
+		{name: "zero interval", value: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{name: "empty", value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := helpers.ParseRecurrenceRule(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseRecurrenceRule(%q) expected error", tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRecurrenceRule(%q) unexpected error: %v", tt.value, err)
+			}
+			if got := rule.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecurrenceRuleOccurrences(t *testing.T) {
+	// 2025-01-07 is a Tuesday.
+	start := date(2025, time.January, 7, 18)
+	farFuture := date(2030, time.January, 1, 0)
+
+	tests := []struct {
+		name     string
+		rule     string
+		start    time.Time
+		from     time.Time
+		to       time.Time
+		excluded []time.Time
+		want     []time.Time
+	}{
+		{
+			name: "daily with interval and count",
+			rule: "FREQ=DAILY;INTERVAL=2;COUNT=3",
+			from: start, to: farFuture,
+			want: []time.Time{start, date(2025, time.January, 9, 18), date(2025, time.January, 11, 18)},
+		},
+		{
+			name: "weekly on tuesdays and thursdays",
+			rule: "FREQ=WEEKLY;BYDAY=TU,TH;COUNT=4",
+			from: start, to: farFuture,
+			want: []time.Time{start, date(2025, time.January, 9, 18), date(2025, time.January, 14, 18), date(2025, time.January, 16, 18)},
+		},
+		{
+			name: "biweekly until",
+			rule: "FREQ=WEEKLY;INTERVAL=2;UNTIL=20250205",
+			from: start, to: farFuture,
+			want: []time.Time{start, date(2025, time.January, 21, 18), date(2025, time.February, 4, 18)},
+		},
+		{
+			name:  "monthly skips short months",
+			rule:  "FREQ=MONTHLY;COUNT=3",
+			start: date(2025, time.January, 31, 18),
+			from:  date(2025, time.January, 31, 18), to: farFuture,
+			want: []time.Time{date(2025, time.January, 31, 18), date(2025, time.March, 31, 18), date(2025, time.May, 31, 18)},
+		},
+		{
+			name: "range limits expansion",
+			rule: "FREQ=WEEKLY",
+			from: date(2025, time.January, 10, 0), to: date(2025, time.January, 25, 0),
+			want: []time.Time{date(2025, time.January, 14, 18), date(2025, time.January, 21, 18)},
+		},
+		{
+			name: "exceptions are skipped but counted",
+			rule: "FREQ=WEEKLY;COUNT=3",
+			from: start, to: farFuture,
+			excluded: []time.Time{date(2025, time.January, 14, 18)},
+			want:     []time.Time{start, date(2025, time.January, 21, 18)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := helpers.ParseRecurrenceRule(tt.rule)
+			if err != nil {
+				t.Fatalf("ParseRecurrenceRule(%q) unexpected error: %v", tt.rule, err)
+			}
+
+			seriesStart := tt.start
+			if seriesStart.IsZero() {
+				seriesStart = start
+			}
+
+			got := rule.Occurrences(seriesStart, tt.from, tt.to, tt.excluded)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Occurrences() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("Occurrences()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRecurrenceRuleIsOccurrence(t *testing.T) {
+	start := date(2025, time.January, 7, 18)
+	rule, err := helpers.ParseRecurrenceRule("FREQ=WEEKLY;COUNT=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !rule.IsOccurrence(start, date(2025, time.January, 14, 18)) {
+		t.Error("expected second week to be an occurrence")
+	}
+	if rule.IsOccurrence(start, date(2025, time.January, 21, 18)) {
+		t.Error("expected third week to be past COUNT")
+	}
+	if rule.IsOccurrence(start, date(2025, time.January, 14, 19)) {
+		t.Error("expected different time not to be an occurrence")
+	}
+	if got := rule.CountBefore(start, date(2025, time.January, 14, 18)); got != 1 {
+		t.Errorf("CountBefore() = %d, want 1", got)
+	}
+}
@@ -0,0 +1,63 @@
+package helpers
+
+import (
+	"band-manager-backend/internal/model"
+	"band-manager-backend/internal/usecases/helpers"
+	"fmt"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestNormalizeReminderMinutes(t *testing.T) {
+	tests := []struct {
+		name    string
+		minutes []int
+		want    []int64
+		wantErr bool
+	}{
+		{"nil stays nil", nil, nil, false},
+		{"empty turns reminders off", []int{}, []int64{}, false},
+		{"sorted earliest first without duplicates", []int{60, 1440, 60, 10080}, []int64{10080, 1440, 60}, false},
+		{"zero minutes", []int{0}, nil, true},
+		{"too early", []int{helpers.MaxReminderMinutes + 1}, nil, true},
+		{"too many", []int{1, 2, 3, 4, 5, 6}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := helpers.NormalizeReminderMinutes(tt.minutes)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NormalizeReminderMinutes(%v) error = %v, wantErr %v", tt.minutes, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if (got == nil) != (tt.want == nil) || fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("NormalizeReminderMinutes(%v) = %#v, want %#v", tt.minutes, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventReminderMinutes(t *testing.T) {
+	group := model.Group{ReminderMinutes: pq.Int64Array{1440, 120}}
+
+	tests := []struct {
+		name  string
+		event *model.Event
+		want  []int
+	}{
+		{"uses the group's reminders", &model.Event{Group: group}, []int{1440, 120}},
+		{"event overrides the group", &model.Event{Group: group, ReminderMinutes: pq.Int64Array{30}}, []int{30}},
+		{"event turns reminders off", &model.Event{Group: group, ReminderMinutes: pq.Int64Array{}}, []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := helpers.EventReminderMinutes(tt.event); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("EventReminderMinutes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package helpers
+
+import (
+	"band-manager-backend/internal/usecases/helpers"
+	"testing"
+)
+
+func TestIsValidRSVPResponse(t *testing.T) {
+	tests := []struct {
+		status   string
+		expected bool
+	}{
+		{helpers.RSVPYes, true},
+		{helpers.RSVPNo, true},
+		{helpers.RSVPMaybe, true},
+		{helpers.RSVPUnanswered, false},
+		{"", false},
+		{"YES", false},
+	}
+
+	for _, tt := range tests {
+		if result := helpers.IsValidRSVPResponse(tt.status); result != tt.expected {
+			t.Errorf("IsValidRSVPResponse(%q) = %v, want %v", tt.status, result, tt.expected)
+		}
+	}
+}
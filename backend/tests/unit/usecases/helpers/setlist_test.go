@@ -0,0 +1,104 @@
+package helpers
+
+import (
+	"band-manager-backend/internal/model"
+	"band-manager-backend/internal/usecases/helpers"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildSetlist(t *testing.T) {
+	warsaw, _ := helpers.LoadTimeZone("Europe/Warsaw")
+	start := time.Date(2025, 6, 1, 18, 0, 0, 0, warsaw)
+	secondSet := start.Add(time.Hour)
+	march := &model.Track{ID: 1, Name: "Marsz"}
+	waltz := &model.Track{ID: 2, Name: "Walc"}
+
+	event := &model.Event{ID: 7, Title: "Koncert", Date: start, TimeZone: "Europe/Warsaw"}
+	performances := []model.Performance{
+		{ID: 13, TrackID: &march.ID, Track: march, Position: 4, StartTime: &secondSet, DurationSeconds: 200},
+		{ID: 10, TrackID: &waltz.ID, Track: waltz, Position: 1, DurationSeconds: 240, Key: "D-dur", Tempo: 90},
+		{ID: 12, Position: 3, DurationSeconds: 900},
+		{ID: 11, TrackID: &march.ID, Track: march, Position: 2, Title: "Marsz (bis)", DurationSeconds: 180},
+	}
+
+	setlist := helpers.BuildSetlist(event, performances)
+
+	expected := []struct {
+		id      uint
+		title   string
+		isBreak bool
+		start   time.Time
+	}{
+		{10, "Walc", false, start},
+		{11, "Marsz (bis)", false, start.Add(4 * time.Minute)},
+		{12, helpers.DefaultBreakTitle, true, start.Add(7 * time.Minute)},
+		{13, "Marsz", false, secondSet},
+	}
+	if len(setlist.Entries) != len(expected) {
+		t.Fatalf("BuildSetlist() returned %d entries, want %d", len(setlist.Entries), len(expected))
+	}
+	for i, tt := range expected {
+		entry := setlist.Entries[i]
+		if entry.ID != tt.id || entry.Title != tt.title || entry.IsBreak != tt.isBreak || !entry.PlannedStart.Equal(tt.start) {
+			t.Errorf("entry %d = {%d %q %v %v}, want {%d %q %v %v}",
+				i, entry.ID, entry.Title, entry.IsBreak, entry.PlannedStart, tt.id, tt.title, tt.isBreak, tt.start)
+		}
+	}
+
+	if setlist.TotalDurationSeconds != 1520 {
+		t.Errorf("TotalDurationSeconds = %d, want 1520", setlist.TotalDurationSeconds)
+	}
+	if setlist.PlannedEnd == nil || !setlist.PlannedEnd.Equal(secondSet.Add(200*time.Second)) {
+		t.Errorf("PlannedEnd = %v, want %v", setlist.PlannedEnd, secondSet.Add(200*time.Second))
+	}
+
+	empty := helpers.BuildSetlist(event, nil)
+	if len(empty.Entries) != 0 || empty.PlannedEnd != nil {
+		t.Errorf("BuildSetlist() of empty setlist = %+v", empty)
+	}
+}
+
+func TestSetlistSummaryFallsBackToTracks(t *testing.T) {
+	event := &model.Event{Tracks: []*model.Track{{ID: 1, Name: "Marsz"}, {ID: 2, Name: "Walc"}}}
+
+	summary := helpers.SetlistSummary(event)
+	if strings.Join(summary, ",") != "Marsz,Walc" {
+		t.Errorf("SetlistSummary() = %v, want [Marsz Walc]", summary)
+	}
+}
+
+func TestSetlistExports(t *testing.T) {
+	warsaw, _ := helpers.LoadTimeZone("Europe/Warsaw")
+	start := time.Date(2025, 6, 1, 18, 0, 0, 0, warsaw)
+	waltz := &model.Track{ID: 2, Name: "Walc, op. 1"}
+	event := &model.Event{ID: 7, Title: "Koncert", Location: "Filharmonia", Date: start, TimeZone: "Europe/Warsaw"}
+	setlist := helpers.BuildSetlist(event, []model.Performance{
+		{ID: 1, TrackID: &waltz.ID, Track: waltz, Position: 1, DurationSeconds: 245, Key: "D-dur", Tempo: 90, Notes: "Bez repetycji"},
+		{ID: 2, Position: 2, DurationSeconds: 3600},
+	})
+
+	text := helpers.SetlistText(event, setlist)
+	for _, fragment := range []string{
+		"Koncert\n01.06.2025, Filharmonia\n",
+		" 1. 18:00  Walc, op. 1 (4:05) - D-dur, 90 BPM\n      Bez repetycji\n",
+		" 2. 18:04  Przerwa (1:00:00)\n",
+		"Czas trwania: 1:04:05\n",
+	} {
+		if !strings.Contains(text, fragment) {
+			t.Errorf("SetlistText() does not contain %q:\n%s", fragment, text)
+		}
+	}
+
+	data, err := helpers.SetlistCSV(event, setlist)
+	if err != nil {
+		t.Fatalf("SetlistCSV() error = %v", err)
+	}
+	expected := "position,planned_start,title,break,duration,key,tempo,notes\n" +
+		"1,2025-06-01 18:00,\"Walc, op. 1\",false,4:05,D-dur,90,Bez repetycji\n" +
+		"2,2025-06-01 18:04,Przerwa,true,1:00:00,,,\n"
+	if string(data) != expected {
+		t.Errorf("SetlistCSV() = %q, want %q", data, expected)
+	}
+}
@@ -0,0 +1,60 @@
+package helpers
+
+import (
+	"band-manager-backend/internal/usecases/helpers"
+	"testing"
+)
+
+func TestGenerateToken(t *testing.T) {
+	first, err := helpers.GenerateToken(32)
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+
+	second, err := helpers.GenerateToken(32)
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+
+	if len(first) != 64 {
+		t.Errorf("GenerateToken(32) length = %d, want 64", len(first))
+	}
+
+	if first == second {
+		t.Error("GenerateToken() returned the same token twice")
+	}
+}
+
+func TestHashToken(t *testing.T) {
+	if helpers.HashToken("secret") != helpers.HashToken("secret") {
+		t.Error("HashToken() is not deterministic")
+	}
+
+	if helpers.HashToken("secret") == helpers.HashToken("other") {
+		t.Error("HashToken() returned the same hash for different tokens")
+	}
+
+	if helpers.HashToken("secret") == "secret" {
+		t.Error("HashToken() returned the token in plain text")
+	}
+}
+
+func TestUnsentTokenHash(t *testing.T) {
+	first, err := helpers.UnsentTokenHash()
+	if err != nil {
+		t.Fatalf("UnsentTokenHash() error = %v", err)
+	}
+
+	second, err := helpers.UnsentTokenHash()
+	if err != nil {
+		t.Fatalf("UnsentTokenHash() error = %v", err)
+	}
+
+	if len(first) != len(helpers.HashToken("")) {
+		t.Errorf("UnsentTokenHash() length = %d, want %d", len(first), len(helpers.HashToken("")))
+	}
+
+	if first == second {
+		t.Error("UnsentTokenHash() returned the same hash twice")
+	}
+}
@@ -0,0 +1,92 @@
+package helpers
+
+import (
+	"archive/zip"
+	"band-manager-backend/internal/usecases/helpers"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func zipArchive(t *testing.T, names ...string) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, name := range names {
+		if _, err := archive.Create(name); err != nil {
+			t.Fatalf("Create(%q) error = %v", name, err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	return buf.String()
+}
+
+func TestDetectNotesheetFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr error
+	}{
+		{"pdf", "%PDF-1.7\n%âãÏÓ", "application/pdf", nil},
+		{"png", "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR", "image/png", nil},
+		{"jpeg", "\xff\xd8\xff\xe0\x00\x10JFIF", "image/jpeg", nil},
+		{"midi", "MThd\x00\x00\x00\x06\x00\x01", "audio/midi", nil},
+		{"musicxml", "\xef\xbb\xbf<?xml version=\"1.0\"?>\n<!DOCTYPE score-partwise>\n<score-partwise version=\"4.0\">", "application/vnd.recordare.musicxml+xml", nil},
+		{"timewise musicxml", "<score-timewise>", "application/vnd.recordare.musicxml+xml", nil},
+		{"mscx", "<?xml version=\"1.0\"?>\n<museScore version=\"4.20\">", "application/x-musescore+xml", nil},
+		{"mxl", zipArchive(t, "mimetype", "META-INF/container.xml", "score.xml"), "application/vnd.recordare.musicxml", nil},
+		{"mscz", zipArchive(t, "META-INF/container.xml", "Marsz.mscx"), "application/x-musescore", nil},
+		{"other zip", zipArchive(t, "word/document.xml"), "", helpers.ErrUnsupportedFileFormat},
+		{"other xml", "<?xml version=\"1.0\"?><svg>", "", helpers.ErrUnsupportedFileFormat},
+		{"html", "<html><script>alert(1)</script>", "", helpers.ErrUnsupportedFileFormat},
+		{"executable", "MZ\x90\x00", "", helpers.ErrUnsupportedFileFormat},
+		{"empty", "", "", helpers.ErrEmptyFile},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, err := helpers.DetectNotesheetFormat(strings.NewReader(tt.content), int64(len(tt.content)))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DetectNotesheetFormat() error = %v, want %v", err, tt.wantErr)
+			}
+			if format.ContentType != tt.want {
+				t.Errorf("DetectNotesheetFormat() = %q, want %q", format.ContentType, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeFilename(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		format   helpers.FileFormat
+		want     string
+	}{
+		{"kept", "Marsz żałobny - trąbka.pdf", helpers.FormatPDF, "Marsz żałobny - trąbka.pdf"},
+		{"directories", "../../etc/passwd.pdf", helpers.FormatPDF, "passwd.pdf"},
+		{"windows path", `C:\Users\kapela\nuty.png`, helpers.FormatPNG, "nuty.png"},
+		{"reserved characters", `a:b*c?"d"<e>|f.pdf`, helpers.FormatPDF, "a_b_c__d__e__f.pdf"},
+		{"control characters", "nuty\x00\r\n\tpartia.pdf", helpers.FormatPDF, "nuty_ partia.pdf"},
+		{"extension case", "Partia.JPEG", helpers.FormatJPEG, "Partia.jpeg"},
+		{"wrong extension", "partia.exe", helpers.FormatPDF, "partia.exe.pdf"},
+		{"no extension", "partia", helpers.FormatMIDI, "partia.mid"},
+		{"hidden", ".htaccess", helpers.FormatPDF, "htaccess.pdf"},
+		{"empty", "", helpers.FormatMXL, "notesheet.mxl"},
+		{"only extension", ".pdf", helpers.FormatPDF, "pdf.pdf"},
+		{"too long", strings.Repeat("ą", 300) + ".pdf", helpers.FormatPDF, strings.Repeat("ą", 120) + ".pdf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := helpers.SanitizeFilename(tt.filename, tt.format); got != tt.want {
+				t.Errorf("SanitizeFilename(%q) = %q, want %q", tt.filename, got, tt.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,42 @@ import { JWT } from "next-auth/jwt";
 import { BACKEND_URL } from "@/src/config/api";
 import Credentials from "next-auth/providers/credentials";
 
+/**
+ * Exchanges the refresh token for a new pair of backend tokens.
+ * The token is left without an access token if the refresh fails,
+ * so that requests made with it are rejected by the backend.
+ */
+async function refreshAccessToken(token: JWT): Promise<JWT> {
+  try {
+    const response = await fetch(`${BACKEND_URL}/api/verify/refresh`, {
+      method: "POST",
+      headers: {
+        "Content-Type": "application/json",
+      },
+      body: JSON.stringify({
+        refresh_token: token.refreshToken,
+      }),
+    });
+
+    if (!response.ok) {
+      const errorText = await response.text();
+      throw new Error(errorText);
+    }
+
+    const data = await response.json();
+
+    return {
+      ...token,
+      accessToken: data.access_token,
+      refreshToken: data.refresh_token,
+      accessTokenExpires: Date.now() + data.expires_in * 1000,
+    };
+  } catch (err) {
+    console.error("Token refresh error:", err);
+    return { ...token, accessToken: undefined };
+  }
+}
+
 /**
  * NextAuth configuration options for the application.
  * Sets up authentication with credentials provider and handles session/token management.
@@ -28,7 +64,7 @@ export const authOptions: AuthOptions = {
        * Features:
        * - Email and password form fields
        * - Backend verification via /api/verify/login
-       * - Returns user ID on successful auth
+       * - Returns user ID and backend tokens on successful auth
        */
       async authorize(
         credentials:
@@ -61,6 +97,9 @@ export const authOptions: AuthOptions = {
 
           return {
             id: data.id,
+            accessToken: data.access_token,
+            refreshToken: data.refresh_token,
+            accessTokenExpires: Date.now() + data.expires_in * 1000,
           };
         } catch (err) {
           console.error("Login error:", err);
@@ -72,7 +111,8 @@ export const authOptions: AuthOptions = {
   callbacks: {
     /**
      * JWT callback to customize token contents.
-     * Adds user ID to the JWT token when created.
+     * Adds user ID and backend tokens to the JWT token when created
+     * and refreshes the backend tokens once the access token expires.
      */
     async jwt({
       token,
@@ -80,8 +120,18 @@ export const authOptions: AuthOptions = {
     }: Parameters<CallbacksOptions["jwt"]>[0]): Promise<JWT> {
       if (user) {
         token.id = user.id;
+        token.accessToken = user.accessToken;
+        token.refreshToken = user.refreshToken;
+        token.accessTokenExpires = user.accessTokenExpires;
+        return token;
+      }
+      if (
+        token.accessTokenExpires &&
+        Date.now() < token.accessTokenExpires - 30 * 1000
+      ) {
+        return token;
       }
-      return token;
+      return refreshAccessToken(token);
     },
     /**
      * Session callback to customize session object.
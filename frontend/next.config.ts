@@ -19,7 +19,7 @@ const nextConfig: NextConfig = {
    * Rules:
    * 1. Notesheet creation endpoint
    *    - Source: /api/track/notesheet/create
-   *    - Requires user-id header
+   *    - Requires authorization header
    *
    * 2. Next.js Authentication endpoints
    *    - Source: /api/auth/*
@@ -33,7 +33,7 @@ const nextConfig: NextConfig = {
    * 4. General API endpoints
    *    - Source: /api/*
    *    - Proxies to backend API
-   *    - Requires user-id header
+   *    - Requires authorization header
    */
   async rewrites() {
     return [
@@ -43,7 +43,7 @@ const nextConfig: NextConfig = {
         has: [
           {
             type: "header",
-            key: "user-id",
+            key: "authorization",
           },
         ],
       },
@@ -61,7 +61,7 @@ const nextConfig: NextConfig = {
         has: [
           {
             type: "header",
-            key: "user-id",
+            key: "authorization",
           },
         ],
       },
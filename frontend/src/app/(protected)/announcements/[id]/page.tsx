@@ -69,9 +69,7 @@ export default function AnnouncementDetailsPage() {
     if (sessionStatus === "loading") return;
     const fetchAnnouncementDetails = async () => {
       try {
-        const response = await fetch(
-          `/api/announcement/user/${session?.user?.id}`,
-        );
+        const response = await fetch(`/api/announcement/user`);
         if (!response.ok) throw new Error("Failed to fetch announcement");
         const data = await response.json();
         const announcementData = data.announcements.filter(
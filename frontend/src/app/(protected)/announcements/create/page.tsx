@@ -83,18 +83,14 @@ export default function CreateAnnouncement() {
     if (sessionStatus === "loading") return;
     const fetchSubgroups = async () => {
       try {
-        const subgroupsResponse = await fetch(
-          `/api/subgroup/group/${groupId}/${session?.user?.id}`,
-        );
+        const subgroupsResponse = await fetch(`/api/subgroup/group/${groupId}`);
         if (!subgroupsResponse.ok) {
           throw new Error("Failed to fetch subgroups");
         }
         const subgroupsData = await subgroupsResponse.json();
         setSubgroups(subgroupsData.subgroups);
 
-        const usersResponse = await fetch(
-          `/api/group/members/${groupId}/${session?.user?.id}`,
-        );
+        const usersResponse = await fetch(`/api/group/members/${groupId}`);
         if (!usersResponse.ok) {
           throw new Error("Failed to fetch users");
         }
@@ -176,7 +172,6 @@ export default function CreateAnnouncement() {
         body: JSON.stringify({
           ...announcementForm,
           group_id: groupId,
-          sender_id: session?.user?.id,
         }),
       });
 
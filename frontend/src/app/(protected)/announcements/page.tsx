@@ -64,9 +64,7 @@ export default function AnnouncementsPage() {
     const fetchAnnouncements = async () => {
       setRenderState({ status: "loading" });
       try {
-        const response = await fetch(
-          `/api/announcement/user/${session?.user?.id}`,
-        );
+        const response = await fetch(`/api/announcement/user`);
         if (!response.ok) {
           throw new Error("Failed to fetch announcements");
         }
@@ -99,7 +97,7 @@ export default function AnnouncementsPage() {
   const removeAnnouncement = async (announcementId: number) => {
     try {
       const response = await fetch(
-        `/api/announcement/delete/${announcementId}/${session?.user?.id}`,
+        `/api/announcement/delete/${announcementId}`,
         {
           method: "DELETE",
           headers: {
@@ -103,16 +103,14 @@ export default function EventDetailsPage() {
     if (sessionStatus === "loading") return;
     const fetchEventDetails = async () => {
       try {
-        const eventResponse = await fetch(
-          `/api/event/info/${id}/${session?.user?.id}`,
-        );
+        const eventResponse = await fetch(`/api/event/info/${id}`);
         if (!eventResponse.ok) throw new Error("Failed to fetch event");
         const eventData = await eventResponse.json();
         const updatedTracks = await Promise.all(
           eventData.tracks.map(async (track: Track) => {
             try {
               const notesheetResponse = await fetch(
-                `/api/track/user/notesheets/${track.id}/${session?.user?.id}`,
+                `/api/track/user/notesheets/${track.id}`,
               );
               if (!notesheetResponse.ok) {
                 console.error(
@@ -161,7 +159,7 @@ export default function EventDetailsPage() {
   const handleDownload = async (notesheetId: number, fileName: string) => {
     try {
       const response = await fetch(
-        `/api/track/notesheet/file/${notesheetId}/${session?.user?.id}`,
+        `/api/track/notesheet/file/${notesheetId}`,
         { method: "GET" },
       );
 
@@ -98,27 +98,21 @@ export default function CreateEvent() {
     if (sessionStatus === "loading") return;
     const fetchData = async () => {
       try {
-        const tracksResponse = await fetch(
-          `/api/track/group/${groupId}/${session?.user?.id}`,
-        );
+        const tracksResponse = await fetch(`/api/track/group/${groupId}`);
         if (!tracksResponse.ok) {
           throw new Error("Failed to fetch tracks");
         }
         const tracksData = await tracksResponse.json();
         setTracks(tracksData.tracks);
 
-        const usersResponse = await fetch(
-          `/api/group/members/${groupId}/${session?.user?.id}`,
-        );
+        const usersResponse = await fetch(`/api/group/members/${groupId}`);
         if (!usersResponse.ok) {
           throw new Error("Failed to fetch users");
         }
         const usersData = await usersResponse.json();
         setAvailableUsers(usersData.members);
 
-        const subgroupResponse = await fetch(
-          `/api/subgroup/group/${groupId}/${session?.user?.id}`,
-        );
+        const subgroupResponse = await fetch(`/api/subgroup/group/${groupId}`);
         if (!subgroupResponse.ok) {
           throw new Error("Failed to fetch subgroups");
         }
@@ -213,7 +207,6 @@ export default function CreateEvent() {
           ...eventForm,
           date: formattedDate,
           group_id: groupId,
-          user_id: session?.user?.id,
         }),
       });
 
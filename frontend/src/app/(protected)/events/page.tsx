@@ -58,7 +58,7 @@ export default function EventsPage() {
     const fetchEvents = async () => {
       setRenderState(RenderState.LOADING);
       try {
-        const response = await fetch(`/api/event/user/${session?.user?.id}`);
+        const response = await fetch(`/api/event/user`);
         if (!response.ok) {
           throw new Error("Failed to fetch events");
         }
@@ -96,7 +96,6 @@ export default function EventsPage() {
         },
         body: JSON.stringify({
           groupId: groupId,
-          userId: session?.user?.id,
         }),
       });
       if (!response.ok) {
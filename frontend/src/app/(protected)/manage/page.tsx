@@ -88,9 +88,7 @@ export default function ManageGroupPage() {
     if (sessionStatus === "loading") return;
     const fetchData = async () => {
       try {
-        const infoResponse = await fetch(
-          `/api/group/${groupId}/${session?.user?.id}`,
-        );
+        const infoResponse = await fetch(`/api/group/${groupId}`);
         if (!infoResponse.ok) throw new Error("Failed to fetch group info");
         const infoData = (await infoResponse.json()) as GroupInfoResponse;
         setGroupInfo({
@@ -99,9 +97,7 @@ export default function ManageGroupPage() {
           access_token: infoData.access_token,
         });
 
-        const membersResponse = await fetch(
-          `/api/group/members/${groupId}/${session?.user?.id}`,
-        );
+        const membersResponse = await fetch(`/api/group/members/${groupId}`);
         if (!membersResponse.ok) throw new Error("Failed to fetch members");
         const membersData =
           (await membersResponse.json()) as GroupMembersResponse;
@@ -136,16 +132,13 @@ export default function ManageGroupPage() {
     newRole: GroupMember["role"],
   ) => {
     try {
-      const response = await fetch(
-        `/api/group/role/${groupId}/${userId}/${session?.user?.id}`,
-        {
-          method: "PUT",
-          headers: {
-            "Content-Type": "application/json",
-          },
-          body: JSON.stringify({ new_role: newRole }),
+      const response = await fetch(`/api/group/role/${groupId}/${userId}`, {
+        method: "PUT",
+        headers: {
+          "Content-Type": "application/json",
         },
-      );
+        body: JSON.stringify({ new_role: newRole }),
+      });
 
       if (!response.ok) {
         throw new Error("Failed to update role");
@@ -168,12 +161,9 @@ export default function ManageGroupPage() {
    */
   const handleRemoveMember = async (userId: number) => {
     try {
-      const response = await fetch(
-        `/api/group/remove/${groupId}/${session?.user?.id}/${userId}`,
-        {
-          method: "DELETE",
-        },
-      );
+      const response = await fetch(`/api/group/remove/${groupId}/${userId}`, {
+        method: "DELETE",
+      });
 
       if (!response.ok) {
         throw new Error("Failed to remove member");
@@ -199,15 +189,12 @@ export default function ManageGroupPage() {
   const handleRefreshToken = async () => {
     setIsRefreshingToken(true);
     try {
-      const response = await fetch(
-        `/api/group/refresh-token/${groupId}/${session?.user?.id}`,
-        {
-          method: "PUT",
-          headers: {
-            "Content-Type": "application/json",
-          },
+      const response = await fetch(`/api/group/refresh-token/${groupId}`, {
+        method: "PUT",
+        headers: {
+          "Content-Type": "application/json",
         },
-      );
+      });
 
       if (!response.ok) {
         throw new Error("Failed to regenerate token");
@@ -56,9 +56,7 @@ export default function CreateSubgroupPage() {
     if (sessionStatus === "loading") return;
     const fetchUsers = async () => {
       try {
-        const response = await fetch(
-          `/api/group/members/${groupId}/${session?.user?.id}`,
-        );
+        const response = await fetch(`/api/group/members/${groupId}`);
 
         if (!response.ok) {
           throw new Error("Failed to fetch subgroups");
@@ -101,7 +99,6 @@ export default function CreateSubgroupPage() {
           group_id: groupId,
           name: name,
           description: description,
-          user_id: session?.user.id,
         }),
       });
 
@@ -112,7 +109,7 @@ export default function CreateSubgroupPage() {
       const subgroupInfoData = await subgroupInfoResponse.json();
 
       const subgroupUsersResponse = await fetch(
-        `/api/subgroup/members/add/${subgroupInfoData.id}/${session?.user?.id}`,
+        `/api/subgroup/members/add/${subgroupInfoData.id}`,
         {
           method: "POST",
           headers: {
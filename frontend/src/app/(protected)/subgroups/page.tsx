@@ -69,9 +69,7 @@ export default function SubgroupsPage() {
     if (sessionStatus === "loading") return;
     const fetchData = async () => {
       try {
-        const membersResponse = await fetch(
-          `/api/group/members/${groupId}/${session?.user?.id}`,
-        );
+        const membersResponse = await fetch(`/api/group/members/${groupId}`);
 
         if (!membersResponse.ok) {
           throw new Error("Failed to fetch members");
@@ -80,9 +78,7 @@ export default function SubgroupsPage() {
         const membersData = await membersResponse.json();
         setAvailableUsers(membersData.members);
 
-        const subgroupsResponse = await fetch(
-          `/api/subgroup/group/${groupId}/${session?.user?.id}`,
-        );
+        const subgroupsResponse = await fetch(`/api/subgroup/group/${groupId}`);
 
         if (!subgroupsResponse.ok) {
           throw new Error("Failed to fetch subgroups");
@@ -124,7 +120,7 @@ export default function SubgroupsPage() {
   const handleRemoveUser = async (subgroupId: number, userId: number) => {
     try {
       const response = await fetch(
-        `/api/subgroup/members/remove/${subgroupId}/${userId}/${session?.user?.id}`,
+        `/api/subgroup/members/remove/${subgroupId}/${userId}`,
         {
           method: "DELETE",
           headers: {
@@ -160,18 +156,15 @@ export default function SubgroupsPage() {
    */
   const handleAddSelectedUsers = async (subgroupId: number) => {
     try {
-      const response = await fetch(
-        `/api/subgroup/members/add/${subgroupId}/${session?.user?.id}`,
-        {
-          method: "POST",
-          headers: {
-            "Content-Type": "application/json",
-          },
-          body: JSON.stringify({
-            user_ids: selectedUserIds,
-          }),
+      const response = await fetch(`/api/subgroup/members/add/${subgroupId}`, {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
         },
-      );
+        body: JSON.stringify({
+          user_ids: selectedUserIds,
+        }),
+      });
 
       if (!response.ok) {
         throw new Error("Failed to add users to subgroup");
@@ -215,15 +208,12 @@ export default function SubgroupsPage() {
    */
   const handleDeleteSubgroup = async (subgroupId: number) => {
     try {
-      const response = await fetch(
-        `/api/subgroup/delete/${subgroupId}/${session?.user?.id}`,
-        {
-          method: "DELETE",
-          headers: {
-            "Content-Type": "application/json",
-          },
+      const response = await fetch(`/api/subgroup/delete/${subgroupId}`, {
+        method: "DELETE",
+        headers: {
+          "Content-Type": "application/json",
         },
-      );
+      });
 
       if (!response.ok) {
         throw new Error("Failed to delete subgroup");
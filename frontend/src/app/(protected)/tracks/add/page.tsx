@@ -69,9 +69,7 @@ export default function AddTrack() {
     if (sessionStatus === "loading") return;
     const fetchSubgroups = async () => {
       try {
-        const response = await fetch(
-          `/api/subgroup/group/${groupId}/${session?.user?.id}`,
-        );
+        const response = await fetch(`/api/subgroup/group/${groupId}`);
 
         if (!response.ok) {
           throw new Error("Failed to fetch subgroups");
@@ -188,7 +186,6 @@ export default function AddTrack() {
           title: trackTitle,
           description: trackDescription,
           group_id: groupId,
-          user_id: session?.user?.id,
         }),
       });
 
@@ -208,10 +205,6 @@ export default function AddTrack() {
         const notesheetFormData = new FormData();
         notesheetFormData.append("file", notesheet.file);
         notesheetFormData.append("track_id", trackId.toString());
-        notesheetFormData.append(
-          "user_id",
-          session?.user?.id?.toString() || "",
-        );
         const subgroupIdsString = JSON.stringify(notesheet.subgroup_ids);
         notesheetFormData.append("subgroup_ids", subgroupIdsString);
 
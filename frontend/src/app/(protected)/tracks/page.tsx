@@ -51,9 +51,7 @@ export default function TracksPage() {
     if (sessionStatus === "loading") return;
     const fetchTracks = async () => {
       try {
-        const response = await fetch(
-          `/api/track/group/${groupId}/${session?.user?.id}`,
-        );
+        const response = await fetch(`/api/track/group/${groupId}`);
 
         if (!response.ok) {
           throw new Error("Failed to fetch events");
@@ -79,15 +77,12 @@ export default function TracksPage() {
    */
   const removeTrack = async (trackId: number) => {
     try {
-      const response = await fetch(
-        `/api/track/delete/${trackId}/${session?.user?.id}`,
-        {
-          method: "DELETE",
-          headers: {
-            "Content-Type": "application/json",
-          },
+      const response = await fetch(`/api/track/delete/${trackId}`, {
+        method: "DELETE",
+        headers: {
+          "Content-Type": "application/json",
         },
-      );
+      });
 
       if (!response.ok) {
         throw new Error("Failed to delete track");
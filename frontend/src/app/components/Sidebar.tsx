@@ -73,7 +73,7 @@ export default function Sidebar() {
     if (sessionStatus === "loading") return;
     const fetchGroups = async () => {
       try {
-        const response = await fetch(`/api/group/user/${session?.user?.id}`);
+        const response = await fetch(`/api/group/user`);
 
         if (!response.ok) {
           throw new Error("Failed to fetch groups");
@@ -106,7 +106,6 @@ export default function Sidebar() {
           "Content-Type": "application/json",
         },
         body: JSON.stringify({
-          user_id: session?.user?.id,
           access_token: joinToken,
         }),
       });
@@ -151,7 +150,6 @@ export default function Sidebar() {
           "Content-Type": "application/json",
         },
         body: JSON.stringify({
-          user_id: session?.user?.id,
           name: groupName,
           description: groupDescription,
         }),
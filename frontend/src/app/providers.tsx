@@ -2,6 +2,10 @@
 
 import { SessionProvider } from "next-auth/react";
 
+/**
+ * The session is refetched periodically, so that the backend access token
+ * the middleware forwards is refreshed before it expires.
+ */
 export default function Providers({ children }: { children: React.ReactNode }) {
-  return <SessionProvider>{children}</SessionProvider>;
+  return <SessionProvider refetchInterval={5 * 60}>{children}</SessionProvider>;
 }
@@ -38,7 +38,10 @@ export default withAuth(
 
     if (path.startsWith("/api/") && !isPublicPath(path) && req.nextauth.token) {
       const requestHeaders = new Headers(req.headers);
-      requestHeaders.set("user-id", req.nextauth.token.id as string);
+      requestHeaders.set(
+        "authorization",
+        `Bearer ${req.nextauth.token.accessToken}`,
+      );
       requestHeaders.set("x-auth-timestamp", Date.now().toString());
 
       return NextResponse.next({
@@ -4,6 +4,9 @@ import { DefaultSession } from "next-auth";
 declare module "next-auth" {
   interface User {
     id?: number | null;
+    accessToken?: string;
+    refreshToken?: string;
+    accessTokenExpires?: number;
   }
 
   interface Session {
@@ -12,3 +15,12 @@ declare module "next-auth" {
     } & DefaultSession["user"];
   }
 }
+
+declare module "next-auth/jwt" {
+  interface JWT {
+    id?: number | null;
+    accessToken?: string;
+    refreshToken?: string;
+    accessTokenExpires?: number;
+  }
+}